@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -15,14 +17,16 @@ import (
 	"pawrest/internal/api/middleware"
 	"pawrest/internal/api/routes"
 	"pawrest/internal/db"
+	"pawrest/internal/db/memory"
 	"pawrest/internal/yamlconfig"
 )
 
 type serverFlags struct {
-	https *bool
-	port  *string
-	cert  *string
-	key   *string
+	https  *bool
+	memory *bool
+	port   *string
+	cert   *string
+	key    *string
 }
 
 func main() {
@@ -30,13 +34,22 @@ func main() {
 	portFlag := flag.String("port", "", "Server port")
 	certFlag := flag.String("cert", "keys/server.pem", "TLS certificate file location")
 	keyFlag := flag.String("key", "keys/server.key", "TLS private key file location")
+	memoryFlag := flag.Bool("memory", false, "Serve the sample data from memory instead of a database")
 	flag.Parse()
 
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	flags := serverFlags{
-		https: httpsFlag,
-		port:  portFlag,
-		cert:  certFlag,
-		key:   keyFlag,
+		https:  httpsFlag,
+		memory: memoryFlag,
+		port:   portFlag,
+		cert:   certFlag,
+		key:    keyFlag,
 	}
 
 	if err := run(flags); err != nil {
@@ -45,18 +58,39 @@ func main() {
 }
 
 func run(flags serverFlags) error {
+	var driver string
+	if *flags.memory {
+		driver = "memory"
+	}
+
 	log.Println("Parsing env.yaml file...")
-	cfg, err := yamlconfig.Parse("env.yaml")
+	cfg, err := yamlconfig.ParseDriver("env.yaml", driver)
 	if err != nil {
 		return err
 	}
 
-	log.Println("Connecting to the database...")
-	database, err := db.ConnectToDB(cfg)
-	if err != nil {
-		return err
+	var database db.DatabaseInterface
+
+	if cfg.DBDriver == "memory" {
+		log.Println("Loading the in-memory database...")
+		database = memory.NewSeeded()
+	} else {
+		log.Println("Connecting to the database...")
+		conn, err := db.Connect(cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.CloseDB()
+
+		log.Println("Applying database migrations...")
+		n, err := conn.MigrateUp(context.Background())
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migration/s\n", n)
+
+		database = conn
 	}
-	defer database.CloseDB()
 
 	if err := middleware.InitLogger("log.csv"); err != nil {
 		return fmt.Errorf("failed to initialize logging middleware: %v", err)
@@ -136,6 +170,70 @@ func run(flags serverFlags) error {
 	return nil
 }
 
+// runMigrate implements the "migrate up|down [n]|status" subcommand.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [n]|status")
+	}
+
+	cfg, err := yamlconfig.Parse("env.yaml")
+	if err != nil {
+		return err
+	}
+
+	if cfg.DBDriver == "memory" {
+		return errors.New("the in-memory database doesn't use migrations")
+	}
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.CloseDB()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		n, err := database.MigrateUp(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migration/s\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to revert %q", args[1])
+			}
+		}
+
+		n, err := database.MigrateDown(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Reverted %d migration/s\n", n)
+	case "status":
+		status, err := database.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range status {
+			appliedAt := m.AppliedAt
+			if appliedAt == "" {
+				appliedAt = "pending"
+			}
+			fmt.Printf("%04d_%-20s %s\n", m.Version, m.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	return nil
+}
+
 func isFlagPassed(flagName string) bool {
 	found := false
 	flag.Visit(func(f *flag.Flag) {
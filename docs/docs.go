@@ -22,9 +22,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with a list of all authors as JSON. Optional filtering, sorting and pagination is available through parameters.",
+                "description": "Responds with a list of all authors as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "Authors"
@@ -63,7 +68,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sorting by a column",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
                         "name": "sort_by",
                         "in": "query"
                     },
@@ -78,6 +83,60 @@ const docTemplate = `{
                         "description": "Offset returned resources",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (last_name:Lem,birth_year.lt:1850)",
+                        "name": "or",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,last_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the records by, e.g. death_year; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -88,24 +147,38 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/Author"
                             }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -118,10 +191,16 @@ const docTemplate = `{
                 ],
                 "description": "Accepts a JSON body to create a new author. Responds with the created author and set ` + "`" + `Location` + "`" + ` header or an error message.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Authors"
@@ -154,25 +233,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -201,13 +280,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -222,7 +301,10 @@ const docTemplate = `{
                 ],
                 "description": "Responds with the queried author as JSON or an error message.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Authors"
@@ -235,6 +317,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,last_name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -247,25 +341,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request - Invalid author id",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -278,7 +372,10 @@ const docTemplate = `{
                 ],
                 "description": "Accepts a JSON body to update a author. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Authors"
@@ -309,31 +406,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -365,31 +462,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request - Invalid author id",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -418,6 +515,10 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content - Successfully responded with available options",
                         "headers": {
+                            "Accept-Patch": {
+                                "type": "string",
+                                "description": "Formats of the bodies of PATCH requests"
+                            },
                             "Allow": {
                                 "type": "string",
                                 "description": "Allowed operations for the resource"
@@ -427,13 +528,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -444,9 +545,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body with patch data to a author. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "description": "Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial author, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "tags": [
                     "Authors"
@@ -477,57 +583,81 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - A test operation of the JSON patch failed",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - The body is in an unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity - An operation of the JSON patch can't be applied",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/books": {
+        "/authors/{id}/books": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with a list of all books as JSON. Optional filtering, sorting and pagination is available through parameters.",
+                "description": "Responds with a list of the books of the author as JSON or an error message if the author doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Get a list of all books",
+                "summary": "Get a list of books of one author",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Book id",
+                        "type": "integer",
+                        "description": "Author id",
                         "name": "id",
-                        "in": "query"
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -547,12 +677,6 @@ const docTemplate = `{
                         "name": "pages",
                         "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Author id",
-                        "name": "author",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "Genre id",
@@ -567,7 +691,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sorting by a column",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
                         "name": "sort_by",
                         "in": "query"
                     },
@@ -583,28 +707,58 @@ const docTemplate = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
-                        "description": "Return extended book information",
-                        "name": "extend",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "If extend=true - Author id",
-                        "name": "author.id",
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "If extend=true - Author first name",
-                        "name": "author.first_name",
+                        "description": "Comma-separated fields to return, e.g. id,title,year",
+                        "name": "fields",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "If extend=true - Author last name",
-                        "name": "author.last_name",
+                        "description": "Comma-separated relations to embed: author, genre, language",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return extended book information",
+                        "name": "extend",
                         "in": "query"
                     }
                 ],
@@ -616,91 +770,44 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/Book"
                             }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request - Invalid input",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized - Invalid or missing token",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Accepts a JSON body to create a new book. Responds with the created book and set ` + "`" + `Location` + "`" + ` header or an error message.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Books"
-                ],
-                "summary": "Create a new book",
-                "parameters": [
-                    {
-                        "description": "New Book",
-                        "name": "book",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Book"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created - Added new book",
-                        "schema": {
-                            "$ref": "#/definitions/Book"
                         },
                         "headers": {
-                            "Location": {
+                            "Link": {
                                 "type": "string",
-                                "description": "Path of the newly created book"
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
                             }
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid input or JSON",
+                        "description": "Bad Request - Invalid author id or input",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
+                    "404": {
+                        "description": "Not Found - No author found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -713,9 +820,18 @@ const docTemplate = `{
                 ],
                 "description": "Responds with an empty response body.",
                 "tags": [
-                    "Books"
+                    "Authors"
+                ],
+                "summary": "Return allowed operations for books of author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Author id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Return allowed operations for books",
                 "responses": {
                     "204": {
                         "description": "No Content - Successfully responded with available options",
@@ -729,195 +845,179 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/books/{id}": {
-            "get": {
+        "/authors:batch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with the queried book as JSON or an error message.",
+                "description": "Accepts a JSON array of authors to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid authors are created and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Get one book",
+                "summary": "Create authors in a batch",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Book id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "New authors",
+                        "name": "authors",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Author"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK - Fetched book",
+                    "201": {
+                        "description": "Created - Added all of the authors",
                         "schema": {
-                            "$ref": "#/definitions/Book"
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Added some of the authors",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid book id",
+                        "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found - No resource found",
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body to update a book. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "description": "Accepts a JSON array of ids of authors to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the authors which can be deleted are and the response is 207 if any item failed.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Update an existing book",
+                "summary": "Delete authors in a batch",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Existing Book id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated Book",
-                        "name": "book",
+                        "description": "Ids of the authors",
+                        "name": "ids",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Book"
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content - Updated the book"
+                    "200": {
+                        "description": "OK - Deleted all of the authors",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Deleted some of the authors",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found -  No resource found",
+                        "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Responds with a status code. When an error occurs the response body contains an error message.",
-                "tags": [
-                    "Books"
-                ],
-                "summary": "Delete an existing book",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Book id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content - Successfully deleted the book"
-                    },
-                    "400": {
-                        "description": "Bad Request - Invalid book id",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized - Invalid or missing token",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found -  No resource found",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -930,18 +1030,9 @@ const docTemplate = `{
                 ],
                 "description": "Responds with an empty response body.",
                 "tags": [
-                    "Books"
-                ],
-                "summary": "Return allowed operations for books",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Book id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Batch"
                 ],
+                "summary": "Return allowed operations for batches",
                 "responses": {
                     "204": {
                         "description": "No Content - Successfully responded with available options",
@@ -955,13 +1046,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
+                    "404": {
+                        "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -972,100 +1063,155 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body with patch data to a book. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "description": "Accepts a JSON array of patches to authors, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Books"
+                    "Authors"
                 ],
-                "summary": "Patch an existing book",
+                "summary": "Patch authors in a batch",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Existing Book id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patches to the book",
-                        "name": "book",
+                        "description": "Patches to the authors with their ids",
+                        "name": "authors",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Book"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Author"
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content - Successfully patched the book"
+                    "200": {
+                        "description": "OK - Patched all of the authors",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Patched some of the authors",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found -  No resource found",
+                        "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/genres": {
+        "/books": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with a list of all genres as JSON. Optional filtering, sorting and pagination is available through parameters.",
+                "description": "Responds with a list of all books as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Get a list of all genres",
+                "summary": "Get a list of all books",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Genre id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Genre name",
-                        "name": "name",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sorting by a column",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
                         "name": "sort_by",
                         "in": "query"
                     },
@@ -1080,34 +1226,126 @@ const docTemplate = `{
                         "description": "Offset returned resources",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,title,year",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to embed: author, genre, language",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return extended book information",
+                        "name": "extend",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "If extend=true - Author id",
+                        "name": "author.id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "If extend=true - Author first name",
+                        "name": "author.first_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "If extend=true - Author last name",
+                        "name": "author.last_name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK - Fetched genres",
+                        "description": "OK - Fetched books",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Genre"
+                                "$ref": "#/definitions/Book"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1118,63 +1356,69 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body to create a new genre. Responds with the created genre and set ` + "`" + `Location` + "`" + ` header or an error message.",
+                "description": "Accepts a JSON body to create a new book. Responds with the created book and set ` + "`" + `Location` + "`" + ` header or an error message.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Create a new genre",
+                "summary": "Create a new book",
                 "parameters": [
                     {
-                        "description": "New Genre",
-                        "name": "genre",
+                        "description": "New Book",
+                        "name": "book",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "$ref": "#/definitions/Book"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created - Added new genre",
+                        "description": "Created - Added new book",
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "$ref": "#/definitions/Book"
                         },
                         "headers": {
                             "Location": {
                                 "type": "string",
-                                "description": "Path of the newly created genre"
+                                "description": "Path of the newly created book"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1187,9 +1431,9 @@ const docTemplate = `{
                 ],
                 "description": "Responds with an empty response body.",
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Return allowed operations for genres",
+                "summary": "Return allowed operations for books",
                 "responses": {
                     "204": {
                         "description": "No Content - Successfully responded with available options",
@@ -1203,71 +1447,205 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/genres/{id}": {
-            "get": {
+        "/books/import": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with the queried genre as JSON or an error message.",
+                "description": "Accepts a CSV with a header row to create a book from each row, in a single transaction. The title, year, pages, author.first_name, author.last_name, genre.name and language.name columns are required; other columns (e.g. of an export of extended books) are ignored. The authors, genres and languages are looked up by their exact names, ignoring case. With create=true the missing ones are created, using the optional author.birth_year and author.death_year columns. Responds with the result of each row, indexed from the first row after the header. By default the import is all-or-nothing: if any row fails, no book is created and the other rows get 424. With atomic=false the valid rows are imported and the response is 207 if any row failed.",
+                "consumes": [
+                    "text/csv"
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Get one genre",
+                "summary": "Import books from CSV",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Genre id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK - Fetched genre",
+                        "description": "CSV with a header row",
+                        "name": "books",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request - Invalid genre id",
+                    {
+                        "type": "boolean",
+                        "description": "Create the authors, genres and languages which don't exist",
+                        "name": "create",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import the rows all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - Imported all of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Imported some of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid CSV or rows",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large - The CSV is larger than 10 MiB",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Return allowed operations for the import of books",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the queried book as JSON or an error message.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Get one book",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,title,year",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to embed: author, genre, language",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched book",
+                        "schema": {
+                            "$ref": "#/definitions/Book"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid book id",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1278,64 +1656,67 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body to update a genre. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "description": "Accepts a JSON body to update a book. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Update an existing genre",
+                "summary": "Update an existing book",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Existing Genre id",
+                        "description": "Existing Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Genre",
-                        "name": "genre",
+                        "description": "Updated Book",
+                        "name": "book",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "$ref": "#/definitions/Book"
                         }
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "No content - Updated the genre"
+                        "description": "No content - Updated the book"
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1348,13 +1729,13 @@ const docTemplate = `{
                 ],
                 "description": "Responds with a status code. When an error occurs the response body contains an error message.",
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Delete an existing genre",
+                "summary": "Delete an existing book",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Genre id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1362,36 +1743,36 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "No Content - Successfully deleted the genre"
+                        "description": "No Content - Successfully deleted the book"
                     },
                     "400": {
-                        "description": "Bad Request - Invalid genre id",
+                        "description": "Bad Request - Invalid book id",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1404,13 +1785,13 @@ const docTemplate = `{
                 ],
                 "description": "Responds with an empty response body.",
                 "tags": [
-                    "Genres"
+                    "Books"
                 ],
-                "summary": "Return allowed operations for genres",
+                "summary": "Return allowed operations for books",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Genre id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1420,6 +1801,10 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content - Successfully responded with available options",
                         "headers": {
+                            "Accept-Patch": {
+                                "type": "string",
+                                "description": "Formats of the bodies of PATCH requests"
+                            },
                             "Allow": {
                                 "type": "string",
                                 "description": "Allowed operations for the resource"
@@ -1429,173 +1814,291 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            }
-        },
-        "/languages": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with a list of all languages as JSON. Optional filtering, sorting and pagination is available through parameters.",
-                "produces": [
-                    "application/json"
+                "description": "Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial book, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "tags": [
-                    "Languages"
+                    "Books"
                 ],
-                "summary": "Get a list of all languages",
+                "summary": "Patch an existing book",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Language id",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Language name",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sorting by a column",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Limit returned number of resources",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Existing Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Offset returned resources",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Patches to the book",
+                        "name": "book",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Book"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK - Fetched languages",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Language"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content - Successfully patched the book"
                     },
                     "400": {
-                        "description": "Bad Request - Invalid input",
+                        "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - A test operation of the JSON patch failed",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - The body is in an unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity - An operation of the JSON patch can't be applied",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/books:batch": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body to create a new language. Responds with the created language and set ` + "`" + `Location` + "`" + ` header or an error message.",
+                "description": "Accepts a JSON array of books to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid books are created and the response is 207 if any item failed.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Languages"
+                    "Books"
                 ],
-                "summary": "Create a new language",
+                "summary": "Create books in a batch",
                 "parameters": [
                     {
-                        "description": "New Language",
-                        "name": "language",
+                        "description": "New books",
+                        "name": "books",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Language"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created - Added new language",
+                        "description": "Created - Added all of the books",
                         "schema": {
-                            "$ref": "#/definitions/Language"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "Path of the newly created language"
-                            }
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Added some of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             },
-            "options": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with an empty response body.",
-                "tags": [
-                    "Languages"
+                "description": "Accepts a JSON array of ids of books to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the books which can be deleted are and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
-                "summary": "Return allowed operations for languages",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Books"
+                ],
+                "summary": "Delete books in a batch",
+                "parameters": [
+                    {
+                        "description": "Ids of the books",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Deleted all of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Deleted some of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Return allowed operations for batches",
                 "responses": {
                     "204": {
                         "description": "No Content - Successfully responded with available options",
@@ -1609,139 +2112,504 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
+                    "404": {
+                        "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            }
-        },
-        "/languages/{id}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Responds with the queried language as JSON or an error message.",
+                "description": "Accepts a JSON array of patches to books, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Languages"
+                    "Books"
                 ],
-                "summary": "Get one language",
+                "summary": "Patch books in a batch",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Language id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Patches to the books with their ids",
+                        "name": "books",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK - Fetched language",
+                        "description": "OK - Patched all of the books",
                         "schema": {
-                            "$ref": "#/definitions/Language"
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Patched some of the books",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid language id",
+                        "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found - No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/BatchResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/genres": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a JSON body to update a language. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
-                "consumes": [
-                    "application/json"
+                "description": "Responds with a list of all genres as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "Languages"
+                    "Genres"
                 ],
-                "summary": "Update an existing language",
+                "summary": "Get a list of all genres",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Existing Language id",
+                        "type": "string",
+                        "description": "Genre id",
                         "name": "id",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Language",
-                        "name": "language",
+                        "type": "string",
+                        "description": "Genre name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit returned number of resources",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset returned resources",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (name:Dramat,name:Nowela)",
+                        "name": "or",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the records by, e.g. name; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched genres",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Genre"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON body to create a new genre. Responds with the created genre and set ` + "`" + `Location` + "`" + ` header or an error message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Create a new genre",
+                "parameters": [
+                    {
+                        "description": "New Genre",
+                        "name": "genre",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Language"
+                            "$ref": "#/definitions/Genre"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content - Updated the language"
+                    "201": {
+                        "description": "Created - Added new genre",
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the newly created genre"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request - Invalid input or JSON",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden - Insufficient permissions",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Return allowed operations for genres",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the queried genre as JSON or an error message.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Get one genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched genre",
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid genre id",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON body to update a genre. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Update an existing genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Existing Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Genre",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content - Updated the genre"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found -  No resource found",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1749,55 +2617,2652 @@ const docTemplate = `{
             "delete": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Responds with a status code. When an error occurs the response body contains an error message.",
-                "tags": [
-                    "Languages"
-                ],
-                "summary": "Delete an existing language",
-                "parameters": [
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with a status code. When an error occurs the response body contains an error message.",
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Delete an existing genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully deleted the genre"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid genre id",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Return allowed operations for genres",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Accept-Patch": {
+                                "type": "string",
+                                "description": "Formats of the bodies of PATCH requests"
+                            },
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial genre, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Patch an existing genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Existing Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patches to the genre",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully patched the genre"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - A test operation of the JSON patch failed",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - The body is in an unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity - An operation of the JSON patch can't be applied",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}/books": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with a list of the books of the genre as JSON or an error message if the genre doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Get a list of books of one genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit returned number of resources",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset returned resources",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,title,year",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to embed: author, genre, language",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return extended book information",
+                        "name": "extend",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched books",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid genre id or input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No genre found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Return allowed operations for books of genre",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres:batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of genres to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid genres are created and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Create genres in a batch",
+                "parameters": [
+                    {
+                        "description": "New genres",
+                        "name": "genres",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Genre"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - Added all of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Added some of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of ids of genres to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the genres which can be deleted are and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Delete genres in a batch",
+                "parameters": [
+                    {
+                        "description": "Ids of the genres",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Deleted all of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Deleted some of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Return allowed operations for batches",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of patches to genres, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Genres"
+                ],
+                "summary": "Patch genres in a batch",
+                "parameters": [
+                    {
+                        "description": "Patches to the genres with their ids",
+                        "name": "genres",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Genre"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Patched all of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Patched some of the genres",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with a list of all languages as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Get a list of all languages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit returned number of resources",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset returned resources",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (name:Polski,name:Angielski)",
+                        "name": "or",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the records by, e.g. name; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched languages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Language"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON body to create a new language. Responds with the created language and set ` + "`" + `Location` + "`" + ` header or an error message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Create a new language",
+                "parameters": [
+                    {
+                        "description": "New Language",
+                        "name": "language",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - Added new language",
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the newly created language"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Return allowed operations for languages",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the queried language as JSON or an error message.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Get one language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations to embed: books",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched language",
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid language id",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON body to update a language. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Update an existing language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Existing Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Language",
+                        "name": "language",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content - Updated the language"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with a status code. When an error occurs the response body contains an error message.",
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Delete an existing language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully deleted the language"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid language id",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Return allowed operations for languages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Accept-Patch": {
+                                "type": "string",
+                                "description": "Formats of the bodies of PATCH requests"
+                            },
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial language, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Patch an existing language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Existing Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patches to the language",
+                        "name": "language",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully patched the language"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found -  No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - A test operation of the JSON patch failed",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - The body is in an unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity - An operation of the JSON patch can't be applied",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{id}/books": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with a list of the books of the language as JSON or an error message if the language doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Get a list of books of one language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit returned number of resources",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset returned resources",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results in an object with the pagination metadata",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv, streamed with a header row",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, e.g. id,title,year",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to embed: author, genre, language",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default",
+                        "name": "agg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter on the aggregates of the groups, written having.\u003caggregate\u003e.\u003coperator\u003e, e.g. having.count.gte",
+                        "name": "having.count.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return extended book information",
+                        "name": "extend",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Fetched books",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages if limit is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor to the next page if the page is full"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of all matching resources"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid language id or input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No language found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Return allowed operations for books of language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages:batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of languages to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid languages are created and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Create languages in a batch",
+                "parameters": [
+                    {
+                        "description": "New languages",
+                        "name": "languages",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Language"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created - Added all of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Added some of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of ids of languages to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the languages which can be deleted are and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Delete languages in a batch",
+                "parameters": [
+                    {
+                        "description": "Ids of the languages",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Deleted all of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Deleted some of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Batch"
+                ],
+                "summary": "Return allowed operations for batches",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a JSON array of patches to languages, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Languages"
+                ],
+                "summary": "Patch languages in a batch",
+                "parameters": [
+                    {
+                        "description": "Patches to the languages with their ids",
+                        "name": "languages",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Language"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the items all-or-nothing (default) or best-effort when false",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Patched all of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status - Patched some of the languages",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input or JSON",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - No resource found",
+                        "schema": {
+                            "$ref": "#/definitions/BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Return a valid JWT token used for authentication and authorization. Token expires after 30 minutes.\nEndpoint requires a JSON request body with a ` + "`" + `return_admin_token` + "`" + ` boolean field. Setting it to ` + "`" + `true` + "`" + ` returns an admin access token.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get a JWT token",
+                "parameters": [
+                    {
+                        "description": "Return an admin token",
+                        "name": "admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Response body contains JWT token",
+                        "schema": {
+                            "$ref": "#/definitions/TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid parameter value",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to create JWT token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the resources matching any of the words of the query as JSON, the best matches first. Book hits include the extended book information.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Search books, authors, genres and languages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of hits (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Found hits",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/SearchHit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Return allowed operations for search",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with all of the statistics of the /stats endpoints as JSON. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get all book statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "$ref": "#/definitions/StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Return allowed operations for statistics",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/authors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the number of books of each author and the years their first and last books were published in as JSON, the most productive authors first. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get the productivity of authors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/AuthorStats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Return allowed operations for statistics",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/decades": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the number of books published in each decade as JSON, in chronological order. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get the number of books per decade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/DecadeCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Return allowed operations for statistics",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/genres": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the number of books of each genre as JSON, the most numerous genres first. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get the number of books per genre",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BookCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Return allowed operations for statistics",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/languages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the number of books in each language as JSON, the most numerous languages first. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get the number of books per language",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BookCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with an empty response body.",
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Return allowed operations for statistics",
+                "responses": {
+                    "204": {
+                        "description": "No Content - Successfully responded with available options",
+                        "headers": {
+                            "Allow": {
+                                "type": "string",
+                                "description": "Allowed operations for the resource"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/pages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with the minimum, maximum, average and median page count of books as JSON. Accepts the filtering parameters of /books, which scope the books that are counted.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get the page count statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year of publishing of the book",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of pages in the book",
+                        "name": "pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genre",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Language id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)",
+                        "name": "or",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content - Successfully deleted the language"
+                    "200": {
+                        "description": "OK - Computed statistics",
+                        "schema": {
+                            "$ref": "#/definitions/PageStats"
+                        }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid language id",
+                        "description": "Bad Request - Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found -  No resource found",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1810,18 +5275,9 @@ const docTemplate = `{
                 ],
                 "description": "Responds with an empty response body.",
                 "tags": [
-                    "Languages"
-                ],
-                "summary": "Return allowed operations for languages",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Language id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Stats"
                 ],
+                "summary": "Return allowed operations for statistics",
                 "responses": {
                     "204": {
                         "description": "No Content - Successfully responded with available options",
@@ -1835,53 +5291,7 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized - Invalid or missing token",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - Insufficient permissions",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "Return a valid JWT token used for authentication and authorization. Token expires after 30 minutes.\nEndpoint requires a JSON request body with a ` + "`" + `return_admin_token` + "`" + ` boolean field. Setting it to ` + "`" + `true` + "`" + ` returns an admin access token.",
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Get a JWT token",
-                "parameters": [
-                    {
-                        "description": "Return an admin token",
-                        "name": "admin",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/TokenRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK - Response body contains JWT token",
-                        "schema": {
-                            "$ref": "#/definitions/TokenResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request - Invalid parameter value",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error - Failed to create JWT token",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -1891,39 +5301,166 @@ const docTemplate = `{
     "definitions": {
         "Author": {
             "type": "object",
+            "required": [
+                "first_name",
+                "last_name"
+            ],
             "properties": {
                 "birth_year": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 99999,
+                    "minimum": -99999
                 },
                 "death_year": {
+                    "type": "integer",
+                    "maximum": 99999,
+                    "minimum": -99999
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "AuthorStats": {
+            "type": "object",
+            "properties": {
+                "books": {
                     "type": "integer"
                 },
                 "first_name": {
                     "type": "string"
                 },
+                "first_year": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "last_name": {
                     "type": "string"
+                },
+                "last_year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "BatchResponse": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BatchResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Violation"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
                 }
             }
         },
         "Book": {
             "type": "object",
+            "required": [
+                "title",
+                "year"
+            ],
             "properties": {
                 "author": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 1
                 },
                 "genre": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 1
                 },
                 "id": {
                     "type": "integer"
                 },
                 "language": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "pages": {
+                    "type": "integer",
+                    "maximum": 2147483647,
+                    "minimum": 1
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "year": {
+                    "type": "integer",
+                    "minimum": -99999
+                }
+            }
+        },
+        "BookCount": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "BookExtended": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/Author"
+                },
+                "genre": {
+                    "$ref": "#/definitions/Genre"
+                },
+                "id": {
                     "type": "integer"
                 },
+                "language": {
+                    "$ref": "#/definitions/Language"
+                },
                 "pages": {
                     "type": "integer"
                 },
@@ -1935,33 +5472,151 @@ const docTemplate = `{
                 }
             }
         },
-        "ErrorResponse": {
+        "DecadeCount": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "books": {
+                    "type": "integer"
+                },
+                "decade": {
+                    "type": "integer"
                 }
             }
         },
         "Genre": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "id": {
                     "type": "integer"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 128
                 }
             }
         },
         "Language": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "id": {
                     "type": "integer"
                 },
                 "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "PageStats": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "books": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
                     "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Violation"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "SearchHit": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/Author"
+                },
+                "book": {
+                    "$ref": "#/definitions/BookExtended"
+                },
+                "genre": {
+                    "$ref": "#/definitions/Genre"
+                },
+                "language": {
+                    "$ref": "#/definitions/Language"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "book",
+                        "author",
+                        "genre",
+                        "language"
+                    ]
+                }
+            }
+        },
+        "StatsResponse": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuthorStats"
+                    }
+                },
+                "decades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DecadeCount"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BookCount"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BookCount"
+                    }
+                },
+                "pages": {
+                    "$ref": "#/definitions/PageStats"
                 }
             }
         },
@@ -1986,6 +5641,20 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "Violation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2009,7 +5678,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Book managing API",
-	Description:      "Documentation of a book managing REST API.\n\n**How to use filtering:**\nTo use simple filtering put name of the column in the query parameter followed by the value.\nExamples: `last_name=Orwell`, `title=Dziady`\nTo filter extended response use filtering like this: `genre.name=Nowela`\n\nTo filter using comparison operators append the operator to the query parameter. Available operators:\n- less than = `.lt`\n- less than or equal = `.lte`\n- greater than = `.gt`\n- greater than or equal = `.gte`\n- equal = `.eq`\n- not equal = `.neq`\n\nExamples: `pages.lt=300`, `year.gte=1980`, `language.name.neq=Polski`.\n\n**How to use sorting:**\nTo sort, use `sort_by` query parameter followed by the column name.\nIf you want to sort in descending order, prefix the column name with a minus sign (`-`).\nExamples: `sort_by=pages` - ascending order, `sort_by=-pages` - descending order\n\n**How to use limit and offset:**\nTo use limit, use the `limit` query parameter, like this: `limit=10`\nTo use offset, you also need to provide a limit.\nThe order of the limit and offset parameters doesn't matter.\nExamples: `offset=10&limit=50`, `limit=50&offset=10`",
+	Description:      "Documentation of a book managing REST API.\n\n**How to read errors:**\nErrors are RFC 7807 problem details (`application/problem+json`) with the `status`, its `title`, a `detail` message\nand the `instance` path of the request. A body failing validation lists each `field` and `rule` it violates in `errors`.\nRules: `required`, `min`/`max` (numbers, or lengths of texts), `past`, `gtefield` (not less than another field),\n`afterbirth` (a book isn't older than its author) and `beforebooks` (an author isn't younger than their books).\n\n**How to use filtering:**\nTo use simple filtering put name of the column in the query parameter followed by the value.\nExamples: `last_name=Orwell`, `title=Dziady`\nTo filter extended response use filtering like this: `genre.name=Nowela`\n\nTo filter using comparison operators append the operator to the query parameter. Available operators:\n- less than = `.lt`\n- less than or equal = `.lte`\n- greater than = `.gt`\n- greater than or equal = `.gte`\n- equal = `.eq`\n- not equal = `.neq`\n\nExamples: `pages.lt=300`, `year.gte=1980`, `language.name.neq=Polski`.\n\n**How to use sorting:**\nTo sort, use `sort_by` query parameter followed by the column name.\nIf you want to sort in descending order, prefix the column name with a minus sign (`-`).\nExamples: `sort_by=pages` - ascending order, `sort_by=-pages` - descending order\nTo sort by more columns, separate them with commas: `sort_by=-year,title`\nNULL values come first in ascending and last in descending order.\nTo change it, suffix the column with `.nullsfirst` or `.nullslast`: `sort_by=death_year.nullslast`\n\n**How to use limit and offset:**\nTo use limit, use the `limit` query parameter, like this: `limit=10`\nTo use offset, you also need to provide a limit.\nThe order of the limit and offset parameters doesn't matter.\nExamples: `offset=10&limit=50`, `limit=50&offset=10`\n\n**How to use cursors:**\nFull pages of a limited list come with a cursor in the `X-Next-Cursor` header.\nPass it in the `after` parameter (instead of `offset`) with the same `sort_by` to get the next page.\nExample: `sort_by=-year&limit=50&after=<cursor>`\n\n**How to select fields:**\nTo get only some of the fields, list them in the `fields` query parameter.\nExamples: `fields=id,title`, `extend=true&fields=title,author.last_name`\n\n**How to expand relations:**\nTo embed related resources instead of their ids, list them in the `expand` query parameter.\nBooks can expand `author`, `genre` and `language`, while authors, genres and languages can expand `books`.\nExamples: `/books?expand=author,genre`, `/books/7?expand=language`, `/authors/5?expand=books`\n\n**How to group and aggregate:**\nTo get groups instead of the records, list the fields to group by in the `group_by` query parameter\nand the aggregates in the `agg` parameter: `count`, `sum(field)`, `avg(field)`, `min(field)`, `max(field)`.\nGroups can be filtered by the aggregates with `having.<aggregate>.<operator>` and sorted by them with `sort_by`.\nExample: `/books?group_by=genre.name&agg=count,avg(pages)&having.count.gte=2&sort_by=-count`\n\n**How to use batches:**\nTo create, update or delete many records at once, send an array of them (or of ids to delete) to `/<resource>:batch`.\nA batch is all-or-nothing, unless `atomic=false` is set, in which case the valid items are applied and the response is 207.\nExample: `POST /genres:batch?atomic=false` with `[{\"name\":\"Fraszka\"},{\"name\":\"\"}]`\n\n**How to patch:**\nSend a JSON merge patch (`Content-Type: application/merge-patch+json`), where `null` clears a field,\nor a JSON patch (`Content-Type: application/json-patch+json`), whose `test` operations respond with 409 when they fail.\nExample: `PATCH /authors/5` with `[{\"op\":\"test\",\"path\":\"/death_year\",\"value\":2006},{\"op\":\"remove\",\"path\":\"/death_year\"}]`\nOther formats are partial records, which only update the fields they set.\n\n**How to choose a format:**\nResponses are written as JSON, XML, YAML or MessagePack, as named by the `Accept` header, and 406 otherwise.\nRequest bodies are read in the format named by the `Content-Type` header, JSON when it's not set, and 415 otherwise.\nExample: `Accept: application/yaml` or `Content-Type: application/xml` with `<genre><name>Fraszka</name></genre>`\n\n**How to stream:**\nTo get a list as newline delimited JSON, written record by record, send the `Accept: application/x-ndjson` header.\nA stream which fails midway ends with an error line and sets the `X-Stream-Error` trailer.\n\n**How to use CSV:**\nTo export a list as CSV, add `format=csv` to its parameters: `/books?format=csv&extend=true`\nTo import books, send a CSV with a header row naming the columns like the parameters of extended books to `/books/import`.\nAuthors, genres and languages are looked up by name, and created if missing with `create=true`.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -11,7 +11,7 @@ import (
 
 // https://github.com/gin-gonic/gin/issues/814
 type AdminBody struct {
-	RetAdmin *bool `json:"return_admin_token" binding:"required"`
+	RetAdmin *bool `json:"return_admin_token" xml:"return_admin_token" binding:"required"`
 } // @Name TokenRequest
 
 func createToken(isAdmin bool, secret string) (string, error) {
@@ -42,15 +42,14 @@ func createToken(isAdmin bool, secret string) (string, error) {
 // @Tags			Auth
 // @Param			admin	body		AdminBody		true	"Return an admin token"
 // @Success		200		{object}	models.Token	"OK - Response body contains JWT token"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid parameter value"
-// @Failure		500		{object}	models.Error	"Internal Server Error - Failed to create JWT token"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid parameter value"
+// @Failure		500		{object}	models.Problem	"Internal Server Error - Failed to create JWT token"
 // @Router			/login [post]
 func ReturnToken(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var body AdminBody
 
-		if err := c.BindJSON(&body); err != nil {
-			c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+		if !bindBody(c, &body) {
 			return
 		}
 
@@ -58,10 +57,10 @@ func ReturnToken(secret string) gin.HandlerFunc {
 
 		token, err := createToken(boolAdmin, secret)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.Error{Error: "Failed to create token"})
+			problem(c, http.StatusInternalServerError, "Failed to create token")
 			return
 		}
 
-		c.JSON(http.StatusOK, models.Token{Admin: boolAdmin, Token: token})
+		respond(c, http.StatusOK, models.Token{Admin: boolAdmin, Token: token})
 	}
 }
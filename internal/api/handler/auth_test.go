@@ -60,7 +60,7 @@ func TestLoginToken_BadRequest(t *testing.T) {
 			w := execRequest("POST", "/api/v1/login", bytes.NewReader([]byte(tc)))
 			assert.Equal(t, http.StatusBadRequest, w.Code)
 
-			var rError models.Error
+			var rError models.Problem
 			decodeJSONBodyCheckEmpty(t, w, &rError)
 		})
 	}
@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"errors"
-	"log"
 	"net/http"
 	"strconv"
 
@@ -12,112 +10,200 @@ import (
 )
 
 // @Summary		Get a list of all authors
-// @Description	Responds with a list of all authors as JSON. Optional filtering, sorting and pagination is available through parameters.
+// @Description	Responds with a list of all authors as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.
 // @Tags			Authors
-// @Produce		json
-// @Param			id			query		string			false	"Author id"
-// @Param			first_name	query		string			false	"Author's first name"
-// @Param			last_name	query		string			false	"Author's last name"
-// @Param			birth_year	query		int				false	"Author's birth year"
-// @Param			death_year	query		string			false	"Author's death year"
-// @Param			sort_by		query		string			false	"Sorting by a column"
-// @Param			limit		query		int				false	"Limit returned number of resources"
-// @Param			offset		query		int				false	"Offset returned resources"
-// @Success		200			{array}		models.Author	"OK - Fetched authors"
-// @Failure		400			{object}	models.Error	"Bad Request - Invalid input"
-// @Failure		401			{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		500			{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				query		string			false	"Author id"
+// @Param			first_name		query		string			false	"Author's first name"
+// @Param			last_name		query		string			false	"Author's last name"
+// @Param			birth_year		query		int				false	"Author's birth year"
+// @Param			death_year		query		string			false	"Author's death year"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			or				query		string			false	"Conditions of which any must match, e.g. (last_name:Lem,birth_year.lt:1850)"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. id,last_name"
+// @Param			expand			query		string			false	"Relations to embed: books"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the records by, e.g. death_year; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Success		200				{array}		models.Author	"OK - Fetched authors"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
 // @Router			/authors [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetAuthors(c *gin.Context) {
-	params := c.Request.URL.Query()
+	params, ok := h.listParams(c)
+	if !ok {
+		return
+	}
+
+	expand, ok := expandParam(c, h.authorRelations())
+	if !ok {
+		return
+	}
+
+	format, ok := listFormat(c, params, expand)
+	if !ok {
+		return
+	}
+
+	if db.IsGrouping(params) {
+		h.listGroups(c, "authors", params, expand)
+		return
+	}
+
+	switch format {
+	case formatCSV:
+		respondCSV(c, "authors", params, h.DB.GetAuthors)
+		return
+	case formatNDJSON:
+		respondNDJSON(c, h.DB.StreamAuthors(c.Request.Context(), params))
+		return
+	}
 
-	authors, err := h.DB.GetAuthors(params)
-	if errors.Is(err, db.ErrParam) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	selectRefs(params, expand)
+
+	authors, err := h.DB.GetAuthors(c.Request.Context(), params)
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
+	total, err := h.DB.CountAuthors(c.Request.Context(), params)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, authors)
+	respondList(c, authors, total, h.CursorKey, expand...)
 }
 
 // @Summary		Get one author
 // @Description	Responds with the queried author as JSON or an error message.
 // @Tags			Authors
-// @Produce		json
-// @Param			id	path		int				true	"Author id"
-// @Success		200	{object}	models.Author	"OK - Fetched author"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid author id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		404	{object}	models.Error	"Not Found - No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			id		path		int				true	"Author id"
+// @Param			fields	query		string			false	"Comma-separated fields to return, e.g. id,last_name"
+// @Param			expand	query		string			false	"Relations to embed: books"
+// @Success		200		{object}	models.Author	"OK - Fetched author"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid author id"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404		{object}	models.Problem	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/authors/{id} [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetAuthor(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	expand, ok := expandParam(c, h.authorRelations())
+	if !ok {
+		return
+	}
+
+	if _, ok := c.GetQuery("fields"); ok {
+		respondFields(c, int64(id), h.DB.GetAuthors, expand...)
 		return
 	}
 
-	author, err := h.DB.GetAuthor(int64(id))
-	if errors.Is(err, db.ErrNotFound) {
-		c.JSON(http.StatusNotFound, models.Error{Error: err.Error()})
+	author, err := h.DB.GetAuthor(c.Request.Context(), int64(id))
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
+	respondRecord(c, author, expand)
+}
+
+// @Summary		Get a list of books of one author
+// @Description	Responds with a list of the books of the author as JSON or an error message if the author doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.
+// @Tags			Authors
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				path		int				true	"Author id"
+// @Param			title			query		string			false	"Book title"
+// @Param			year			query		int				false	"Year of publishing of the book"
+// @Param			pages			query		int				false	"Number of pages in the book"
+// @Param			genre			query		int				false	"Genre id"
+// @Param			language		query		int				false	"Language id"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. id,title,year"
+// @Param			expand			query		string			false	"Comma-separated relations to embed: author, genre, language"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Param			extend			query		bool			false	"Return extended book information"
+// @Success		200				{array}		models.Book		"OK - Fetched books"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid author id or input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404				{object}	models.Problem	"Not Found - No author found"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
+// @Router			/authors/{id}/books [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetAuthorBooks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	if _, err := h.DB.GetAuthor(c.Request.Context(), int64(id)); err != nil {
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, author)
+	h.listBooks(c, "author", int64(id))
 }
 
 // @Summary		Create a new author
 // @Description	Accepts a JSON body to create a new author. Responds with the created author and set `Location` header or an error message.
 // @Tags			Authors
-// @Accept			json
-// @Produce		json
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
 // @Param			author	body		models.Author	true	"New Author"
 // @Success		201		{object}	models.Author	"Created - Added new author"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Header			201		{string}	Location		"Path of the newly created author"
 // @Router			/authors [post]
 // @Security		ApiKeyAuth
 func (h *Handlers) PostAuthor(c *gin.Context) {
 	var newAuthor models.Author
 
-	if err := c.BindJSON(&newAuthor); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
-		return
-	}
-
-	if newAuthor.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
+	if !bindBody(c, &newAuthor) {
 		return
 	}
 
-	id, err := h.DB.InsertAuthor(newAuthor)
-	if errors.Is(err, db.ErrForeignKey) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	if v := newAuthor.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
+	id, err := h.DB.InsertAuthor(c.Request.Context(), newAuthor)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
@@ -126,44 +212,43 @@ func (h *Handlers) PostAuthor(c *gin.Context) {
 	location := c.FullPath() + "/" + strconv.FormatInt(newAuthor.ID, 10)
 	c.Header("Location", location)
 
-	c.JSON(http.StatusCreated, newAuthor)
+	respond(c, http.StatusCreated, newAuthor)
 }
 
 // @Summary		Update an existing author
 // @Description	Accepts a JSON body to update a author. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Authors
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack
 // @Param			id		path	int				true	"Existing Author id"
 // @Param			author	body	models.Author	true	"Updated Author"
 // @Success		204		"No content - Updated the author"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404		{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/authors/{id} [put]
 // @Security		ApiKeyAuth
 func (h *Handlers) PutAuthor(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
 	var newAuthor models.Author
 
-	if err := c.BindJSON(&newAuthor); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newAuthor) {
 		return
 	}
 
-	if newAuthor.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
+	if v := newAuthor.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
-	if err := h.DB.UpdateWholeAuthor(int64(id), newAuthor); err != nil {
+	if err := h.DB.UpdateWholeAuthor(c.Request.Context(), int64(id), newAuthor); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -172,35 +257,52 @@ func (h *Handlers) PutAuthor(c *gin.Context) {
 }
 
 // @Summary		Patch an existing author
-// @Description	Accepts a JSON body with patch data to a author. Responds with a status code. When an error occurs the response body contains JSON data with the message.
+// @Description	Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial author, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Authors
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
 // @Param			id		path	int				true	"Existing Author id"
 // @Param			author	body	models.Author	true	"Patches to the author"
 // @Success		204		"No Content - Successfully patched the author"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404		{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		409		{object}	models.Problem	"Conflict - A test operation of the JSON patch failed"
+// @Failure		415		{object}	models.Problem	"Unsupported Media Type - The body is in an unsupported format"
+// @Failure		422		{object}	models.Problem	"Unprocessable Entity - An operation of the JSON patch can't be applied"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/authors/{id} [patch]
 // @Security		ApiKeyAuth
 func (h *Handlers) PatchAuthor(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	if isPatchDocument(c) {
+		patchRecord[models.Author](c, h.DB, int64(id), db.DatabaseInterface.GetAuthor, db.DatabaseInterface.UpdateWholeAuthor)
 		return
 	}
 
 	var patchAuthor models.Author
 
-	if err := c.BindJSON(&patchAuthor); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &patchAuthor) {
+		return
+	}
+
+	if patchAuthor == (models.Author{ID: patchAuthor.ID}) {
+		problem(c, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	if v := patchAuthor.ValidatePatch(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more fields are invalid", v...)
 		return
 	}
 
-	if err := h.DB.UpdateAuthor(int64(id), patchAuthor); err != nil {
+	if err := h.DB.UpdateAuthor(c.Request.Context(), int64(id), patchAuthor); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -213,22 +315,22 @@ func (h *Handlers) PatchAuthor(c *gin.Context) {
 // @Tags			Authors
 // @Param			id	path	int	true	"Author id"
 // @Success		204	"No Content - Successfully deleted the author"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid author id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404	{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Failure		400	{object}	models.Problem	"Bad Request - Invalid author id"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404	{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500	{object}	models.Problem	"Internal Server Error"
 // @Router			/authors/{id} [delete]
 // @Security		ApiKeyAuth
 func (h *Handlers) DeleteAuthor(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if err := h.DB.DelAuthor(int64(id)); err != nil {
+	if err := h.DB.DelAuthor(c.Request.Context(), int64(id)); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -240,8 +342,8 @@ func (h *Handlers) DeleteAuthor(c *gin.Context) {
 // @Description	Responds with an empty response body.
 // @Tags			Authors
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
 // @Router			/authors [options]
 // @Security		ApiKeyAuth
@@ -255,12 +357,104 @@ func (h *Handlers) OptionsAuthors(c *gin.Context) {
 // @Tags			Authors
 // @Param			id	path	string	true	"Author id"
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Header			204	{string}	Accept-Patch	"Formats of the bodies of PATCH requests"
 // @Router			/authors/{id} [options]
 // @Security		ApiKeyAuth
 func (h *Handlers) OptionsAuthor(c *gin.Context) {
 	c.Header("Allow", "GET, PUT, PATCH, DELETE, OPTIONS")
+	c.Header("Accept-Patch", acceptPatch)
 	c.Status(http.StatusNoContent)
 }
+
+// @Summary		Return allowed operations for books of author
+// @Description	Responds with an empty response body.
+// @Tags			Authors
+// @Param			id	path	string	true	"Author id"
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/authors/{id}/books [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsAuthorBooks(c *gin.Context) {
+	c.Header("Allow", "GET, OPTIONS")
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Create authors in a batch
+// @Description	Accepts a JSON array of authors to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid authors are created and the response is 207 if any item failed.
+// @Tags			Authors
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			authors	body		[]models.Author	true	"New authors"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		201		{object}	models.Batch	"Created - Added all of the authors"
+// @Success		207		{object}	models.Batch	"Multi-Status - Added some of the authors"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/authors:batch [post]
+// @Security		ApiKeyAuth
+func (h *Handlers) PostAuthorsBatch(c *gin.Context) {
+	authors, b, ok := parseBatch(c, checkNew[models.Author])
+	if !ok {
+		return
+	}
+
+	postBatch(c, h.DB, b, authors, db.DatabaseInterface.InsertAuthors, db.DatabaseInterface.InsertAuthor)
+}
+
+// @Summary		Patch authors in a batch
+// @Description	Accepts a JSON array of patches to authors, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.
+// @Tags			Authors
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			authors	body		[]models.Author	true	"Patches to the authors with their ids"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Patched all of the authors"
+// @Success		207		{object}	models.Batch	"Multi-Status - Patched some of the authors"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/authors:batch [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchAuthorsBatch(c *gin.Context) {
+	authors, b, ok := parseBatch(c, func(a *models.Author) (string, models.Violations) {
+		return checkPatch(a.ID, *a == models.Author{ID: a.ID}, a.ValidatePatch())
+	})
+	if !ok {
+		return
+	}
+
+	patchBatch(c, h.DB, b, authors, func(a *models.Author) int64 { return a.ID }, db.DatabaseInterface.UpdateAuthor)
+}
+
+// @Summary		Delete authors in a batch
+// @Description	Accepts a JSON array of ids of authors to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the authors which can be deleted are and the response is 207 if any item failed.
+// @Tags			Authors
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			ids		body		[]int			true	"Ids of the authors"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Deleted all of the authors"
+// @Success		207		{object}	models.Batch	"Multi-Status - Deleted some of the authors"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/authors:batch [delete]
+// @Security		ApiKeyAuth
+func (h *Handlers) DeleteAuthorsBatch(c *gin.Context) {
+	ids, b, ok := parseBatch(c, checkID)
+	if !ok {
+		return
+	}
+
+	deleteBatch(c, h.DB, b, ids, db.DatabaseInterface.DelAuthor)
+}
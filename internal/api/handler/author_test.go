@@ -1,8 +1,12 @@
 package handler_test
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +24,29 @@ func TestListAuthors_BadRequest_UnknownParam(t *testing.T) {
 	execAndCheckError(t, "GET", "/api/v1/authors?foo=bar", nil, http.StatusBadRequest)
 }
 
+func TestListAuthors_SortNullsLast(t *testing.T) {
+	var rAuthors []models.Author
+	execAndCheck(t, "GET", "/api/v1/authors?sort_by=-death_year.nullslast,last_name", nil, http.StatusOK, &rAuthors)
+
+	assert.NotEmpty(t, rAuthors)
+	assert.True(t, slices.IsSortedFunc(rAuthors, func(a, b models.Author) int {
+		switch {
+		case a.DeathYear == nil && b.DeathYear == nil:
+			return 0
+		case a.DeathYear == nil:
+			return 1
+		case b.DeathYear == nil:
+			return -1
+		}
+
+		return cmp.Compare(*b.DeathYear, *a.DeathYear)
+	}), "Authors should be sorted by death year descending, with the living last")
+}
+
+func TestListAuthors_BadRequest_RepeatedSort(t *testing.T) {
+	execAndCheckError(t, "GET", "/api/v1/authors?sort_by=last_name,-last_name", nil, http.StatusBadRequest)
+}
+
 // GET /authors/id
 func TestGetAuthor_Success(t *testing.T) {
 	var rAuthor models.Author
@@ -47,6 +74,56 @@ func TestGetAuthor_Error(t *testing.T) {
 	runTestErrors(t, "GET", "authors", getTests)
 }
 
+// GET /authors/id/books
+func TestListAuthorBooks_Success(t *testing.T) {
+	var rBooks []models.Book
+	w := execAndCheck(t, "GET", "/api/v1/authors/5/books", nil, http.StatusOK, &rBooks)
+
+	assert.NotEmpty(t, rBooks)
+	assert.Equal(t, strconv.Itoa(len(rBooks)), w.Header().Get("X-Total-Count"))
+
+	for _, b := range rBooks {
+		assert.Equal(t, int64(5), b.Author, "Only the books of the author should be listed")
+	}
+}
+
+func TestListAuthorBooks_Params(t *testing.T) {
+	var rBooks []models.Book
+	w := execAndCheck(t, "GET", "/api/v1/authors/5/books?sort_by=-year&limit=1&author=1", nil, http.StatusOK, &rBooks)
+
+	if assert.Len(t, rBooks, 1) {
+		assert.Equal(t, int64(5), rBooks[0].Author, "The author in the path should take precedence")
+	}
+
+	assert.Contains(t, w.Header().Get("Link"), "</api/v1/authors/5/books?", "Links should point at the nested route")
+
+	var rExt []models.BookExt
+	execAndCheck(t, "GET", "/api/v1/authors/5/books?extend=true&title=Solaris", nil, http.StatusOK, &rExt)
+
+	if assert.Len(t, rExt, 1) {
+		assert.Equal(t, "Lem", rExt[0].Author.LastName)
+	}
+}
+
+func TestListAuthorBooks_Error(t *testing.T) {
+	listTests := map[string]ErrorTests{
+		"NotFound_BigPathID": {
+			query:  "/9999/books",
+			status: http.StatusNotFound,
+		},
+		"BadRequest_StringPathID": {
+			query:  "/string/books",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_UnknownParam": {
+			query:  "/5/books?foo=bar",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "authors", listTests)
+}
+
 // POST /authors
 func TestPostAuthor_Success(t *testing.T) {
 	testAuthor := models.Author{
@@ -59,7 +136,7 @@ func TestPostAuthor_Success(t *testing.T) {
 	var rAuthor models.Author
 	jsonAuthor := marshalCheckNoError(t, testAuthor)
 	w := execAndCheck(t, "POST", "/api/v1/authors", jsonAuthor, http.StatusCreated, &rAuthor)
-	defer database.DelAuthor(rAuthor.ID)
+	defer database.DelAuthor(context.Background(), rAuthor.ID)
 
 	expLoc := fmt.Sprintf("/api/v1/authors/%v", rAuthor.ID)
 	assert.Equal(t, expLoc, w.Result().Header.Get("Location"))
@@ -108,14 +185,14 @@ func TestPutAuthor_Success(t *testing.T) {
 	testAuthor := models.Author{
 		FirstName: "Put",
 		LastName:  "test",
-		BirthYear: 2000,
+		BirthYear: 1790,
 		DeathYear: nil,
 	}
 
 	jsonAuthor := marshalCheckNoError(t, testAuthor)
 	execAndCheck(t, "PUT", "/api/v1/authors/1", jsonAuthor, http.StatusNoContent, nil)
 
-	author, _ := database.GetAuthor(1)
+	author, _ := database.GetAuthor(context.Background(), 1)
 	assert.Equal(t, testAuthor.FirstName, author.FirstName)
 	assert.Equal(t, testAuthor.LastName, author.LastName)
 	assert.Equal(t, testAuthor.BirthYear, author.BirthYear)
@@ -166,7 +243,7 @@ func TestPatchAuthor_Success(t *testing.T) {
 	jsonBytes := []byte(`{"first_name":"Patch test", "death_year": 2025}`)
 	execAndCheck(t, "PATCH", "/api/v1/authors/1", jsonBytes, http.StatusNoContent, nil)
 
-	author, _ := database.GetAuthor(1)
+	author, _ := database.GetAuthor(context.Background(), 1)
 	assert.Equal(t, "Patch test", author.FirstName)
 	assert.NotEmpty(t, author.LastName)
 	assert.NotEmpty(t, author.BirthYear)
@@ -190,6 +267,11 @@ func TestPatchAuthor_Error(t *testing.T) {
 			query:  "/1",
 			status: http.StatusBadRequest,
 		},
+		"BadRequest_DeathBeforeStoredBirth": {
+			body:   []byte(`{"death_year":1800}`),
+			query:  "/5",
+			status: http.StatusBadRequest,
+		},
 	}
 
 	runTestErrors(t, "PATCH", "authors", patchTests)
@@ -197,7 +279,7 @@ func TestPatchAuthor_Error(t *testing.T) {
 
 // DELETE /authors/id
 func TestDeleteAuthor_Success(t *testing.T) {
-	newID, err := database.InsertAuthor(models.Author{
+	newID, err := database.InsertAuthor(context.Background(), models.Author{
 		FirstName: "Delete",
 		LastName:  "tester",
 		BirthYear: 1900,
@@ -208,7 +290,7 @@ func TestDeleteAuthor_Success(t *testing.T) {
 	newAuthorLoc := fmt.Sprintf("/api/v1/authors/%v", newID)
 	execAndCheck(t, "DELETE", newAuthorLoc, nil, http.StatusNoContent, nil)
 
-	_, err = database.GetAuthor(newID)
+	_, err = database.GetAuthor(context.Background(), newID)
 	assert.ErrorIs(t, err, db.ErrNotFound)
 }
 
@@ -231,6 +313,7 @@ func TestDeleteAuthor_Error(t *testing.T) {
 
 // OPTIONS /authors
 // OPTIONS /authors/id
+// OPTIONS /authors/id/books
 func TestOptionsAuthors_Success(t *testing.T) {
 	optionsTests := map[string]struct {
 		query   string
@@ -244,6 +327,10 @@ func TestOptionsAuthors_Success(t *testing.T) {
 			"/1",
 			[]string{"GET", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		},
+		"Books": {
+			"/1/books",
+			[]string{"GET", "OPTIONS"},
+		},
 	}
 
 	runTestOptionsSuccess(t, "authors", optionsTests)
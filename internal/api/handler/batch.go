@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// maxBatchSize limits the number of items of a single batch request.
+const maxBatchSize = 1000
+
+// errBatchFailed rolls back an all-or-nothing batch when one of its items fails.
+var errBatchFailed = errors.New("batch failed")
+
+// batch collects the results of the items of a batch request. Items
+// are processed all-or-nothing unless the request sets atomic=false,
+// in which case the items that can be processed are committed and
+// the failed ones are reported.
+type batch struct {
+	atomic  bool
+	results []models.BatchResult
+}
+
+// newBatch returns a batch of n items, all-or-nothing unless
+// the request sets atomic=false.
+func newBatch(c *gin.Context, n int) *batch {
+	b := &batch{
+		atomic:  c.DefaultQuery("atomic", "true") != "false",
+		results: make([]models.BatchResult, n),
+	}
+
+	for i := range b.results {
+		b.results[i].Index = i
+	}
+
+	return b
+}
+
+// parseBatch decodes the array in the request body into items, which in XML
+// is a list of item elements. The items which can't be decoded or for which
+// check returns an error message fail with 400. It responds with 400 and
+// returns false when the body isn't an array of 1 to maxBatchSize items.
+func parseBatch[T any](c *gin.Context, check func(item *T) (string, models.Violations)) ([]T, *batch, bool) {
+	const expected = ", expected an array"
+
+	data, format, ok := readBody(c, &xmlList[T]{}, expected)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		problem(c, http.StatusBadRequest, invalidBody(format, expected))
+		return nil, nil, false
+	}
+
+	if len(raw) == 0 || len(raw) > maxBatchSize {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("A batch must contain from 1 to %d items", maxBatchSize))
+		return nil, nil, false
+	}
+
+	b := newBatch(c, len(raw))
+	items := make([]T, len(raw))
+
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &items[i]); err != nil {
+			b.results[i].Status = http.StatusBadRequest
+			b.results[i].Error = "Invalid JSON"
+		} else if msg, v := check(&items[i]); msg != "" {
+			b.results[i].Status = http.StatusBadRequest
+			b.results[i].Error = msg
+			b.results[i].Errors = v
+		}
+	}
+
+	return items, b, true
+}
+
+// checkNew checks a record to be created.
+func checkNew[T any, P interface {
+	*T
+	Validate() models.Violations
+}](item *T) (string, models.Violations) {
+	if v := P(item).Validate(); v != nil {
+		return "One or more required fields are missing or invalid", v
+	}
+
+	return "", nil
+}
+
+// checkPatch checks a patch of the record with id, which is empty
+// when it doesn't set any of the fields of the record, and violates v.
+func checkPatch(id int64, empty bool, v models.Violations) (string, models.Violations) {
+	switch {
+	case id <= 0:
+		return "Provided incorrect identifier", nil
+	case empty:
+		return "No fields to update", nil
+	case v != nil:
+		return "One or more fields are invalid", v
+	}
+
+	return "", nil
+}
+
+// checkID checks an id of a record to be deleted.
+func checkID(id *int64) (string, models.Violations) {
+	return checkPatch(*id, false, nil)
+}
+
+// run processes the items which passed the checks in a single transaction
+// and responds with their results, using status for the successful items.
+// bulk, if not nil, processes all of the items with a single statement, in
+// which case op is only used to find the failing items when it fails. Each
+// item is processed in its own savepoint, so that a failed one doesn't
+// affect the others. Errors other than the ones caused by the items abort
+// the whole batch.
+func (b *batch) run(
+	c *gin.Context,
+	d db.DatabaseInterface,
+	status int,
+	bulk func(tx db.DatabaseInterface, items []int) ([]int64, error),
+	op func(tx db.DatabaseInterface, i int) (int64, error),
+) {
+	ctx := c.Request.Context()
+
+	var pending []int
+	for i, r := range b.results {
+		if r.Status == 0 {
+			pending = append(pending, i)
+		}
+	}
+
+	if b.atomic && len(pending) < len(b.results) {
+		b.rollback()
+		b.respond(c, status)
+		return
+	}
+
+	err := d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		if bulk != nil && len(pending) > 0 {
+			var ids []int64
+
+			err := tx.WithTx(ctx, func(tx db.DatabaseInterface) (err error) {
+				ids, err = bulk(tx, pending)
+				return err
+			})
+			if err == nil {
+				for j, i := range pending {
+					b.results[i].Status = status
+					b.results[i].ID = ids[j]
+				}
+
+				return nil
+			}
+
+			if _, ok := itemStatus(err); !ok {
+				return err
+			}
+		}
+
+		for _, i := range pending {
+			var id int64
+
+			err := tx.WithTx(ctx, func(tx db.DatabaseInterface) (err error) {
+				id, err = op(tx, i)
+				return err
+			})
+			if err != nil {
+				code, ok := itemStatus(err)
+				if !ok {
+					return err
+				}
+
+				b.results[i].Status = code
+				b.results[i].Error = err.Error()
+
+				var v models.Violations
+				if errors.As(err, &v) {
+					b.results[i].Errors = v
+				}
+
+				if b.atomic {
+					return errBatchFailed
+				}
+
+				continue
+			}
+
+			b.results[i].Status = status
+			b.results[i].ID = id
+		}
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errBatchFailed):
+		b.rollback()
+	case err != nil:
+		handleDBError(c, err)
+		return
+	}
+
+	b.respond(c, status)
+}
+
+// postBatch creates the items of b with insertMany, or one by one
+// with insert when it fails.
+func postBatch[T any](
+	c *gin.Context,
+	d db.DatabaseInterface,
+	b *batch,
+	items []T,
+	insertMany func(tx db.DatabaseInterface, ctx context.Context, records []T) ([]int64, error),
+	insert func(tx db.DatabaseInterface, ctx context.Context, record T) (int64, error),
+) {
+	ctx := c.Request.Context()
+
+	bulk := func(tx db.DatabaseInterface, pending []int) ([]int64, error) {
+		records := make([]T, len(pending))
+		for j, i := range pending {
+			records[j] = items[i]
+		}
+
+		return insertMany(tx, ctx, records)
+	}
+
+	b.run(c, d, http.StatusCreated, bulk, func(tx db.DatabaseInterface, i int) (int64, error) {
+		return insert(tx, ctx, items[i])
+	})
+}
+
+// patchBatch updates the records with the ids of the items of b.
+func patchBatch[T any](
+	c *gin.Context,
+	d db.DatabaseInterface,
+	b *batch,
+	items []T,
+	id func(record *T) int64,
+	update func(tx db.DatabaseInterface, ctx context.Context, id int64, record T) error,
+) {
+	b.run(c, d, http.StatusNoContent, nil, func(tx db.DatabaseInterface, i int) (int64, error) {
+		return id(&items[i]), update(tx, c.Request.Context(), id(&items[i]), items[i])
+	})
+}
+
+// deleteBatch deletes the records with the ids of b.
+func deleteBatch(
+	c *gin.Context,
+	d db.DatabaseInterface,
+	b *batch,
+	ids []int64,
+	del func(tx db.DatabaseInterface, ctx context.Context, id int64) error,
+) {
+	b.run(c, d, http.StatusNoContent, nil, func(tx db.DatabaseInterface, i int) (int64, error) {
+		return ids[i], del(tx, c.Request.Context(), ids[i])
+	})
+}
+
+// itemStatus returns the status of an item which failed with err,
+// or false if err wasn't caused by the item.
+func itemStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, db.ErrParam), errors.Is(err, db.ErrForeignKey):
+		return http.StatusBadRequest, true
+	case errors.Is(err, db.ErrNotFound):
+		return http.StatusNotFound, true
+	}
+
+	return 0, false
+}
+
+// rollback marks the items which didn't fail as not applied,
+// as an all-or-nothing batch was rolled back.
+func (b *batch) rollback() {
+	for i, r := range b.results {
+		if r.Status < http.StatusBadRequest {
+			b.results[i] = models.BatchResult{
+				Index:  r.Index,
+				Status: http.StatusFailedDependency,
+				Error:  "Not applied, because another item of the batch failed",
+			}
+		}
+	}
+}
+
+// respond writes the results of the batch. A fully successful batch responds
+// with 201 if its items were created and 200 otherwise, a rolled back one
+// with the status of its first failed item and a partially successful one
+// with 207.
+func (b *batch) respond(c *gin.Context, status int) {
+	resp := models.Batch{Atomic: b.atomic, Results: b.results}
+
+	code := 0
+
+	for _, r := range b.results {
+		if r.Status >= http.StatusBadRequest {
+			resp.Failed++
+
+			if code == 0 && r.Status != http.StatusFailedDependency {
+				code = r.Status
+			}
+		} else {
+			resp.Succeeded++
+		}
+	}
+
+	switch {
+	case resp.Failed == 0 && status == http.StatusCreated:
+		code = http.StatusCreated
+	case resp.Failed == 0:
+		code = http.StatusOK
+	case !b.atomic:
+		code = http.StatusMultiStatus
+	}
+
+	respond(c, code, resp)
+}
+
+// @Summary		Return allowed operations for batches
+// @Description	Responds with an empty response body.
+// @Tags			Batch
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404	{object}	models.Problem	"Not Found - No resource found"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/books:batch [options]
+// @Router			/authors:batch [options]
+// @Router			/genres:batch [options]
+// @Router			/languages:batch [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsBatch(c *gin.Context) {
+	c.Header("Allow", "POST, PATCH, DELETE, OPTIONS")
+	c.Status(http.StatusNoContent)
+}
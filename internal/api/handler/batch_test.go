@@ -0,0 +1,262 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+// POST /books:batch
+func TestPostBooksBatch_Success(t *testing.T) {
+	testBooks := []models.Book{
+		{Title: "Batch test 1", Year: 1965, Pages: 280, Author: 5, Genre: 6, Language: 2},
+		{Title: "Batch test 2", Year: 1964, Pages: 180, Author: 5, Genre: 6, Language: 2},
+	}
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books:batch", marshalCheckNoError(t, testBooks), http.StatusCreated, &rBatch)
+
+	assert.True(t, rBatch.Atomic)
+	assert.Equal(t, 2, rBatch.Succeeded)
+	assert.Zero(t, rBatch.Failed)
+
+	for i, r := range rBatch.Results {
+		defer database.DelBook(context.Background(), r.ID)
+
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, http.StatusCreated, r.Status)
+
+		book, err := database.GetBook(context.Background(), r.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, testBooks[i].Title, book.Title)
+	}
+}
+
+func TestPostBooksBatch_Atomic(t *testing.T) {
+	body := []byte(`[
+		{"title":"Batch atomic","year":1965,"pages":280,"author":5,"genre":6,"language":2},
+		{"title":"Batch atomic","year":1965,"pages":280,"author":5,"genre":6,"language":999}
+	]`)
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books:batch", body, http.StatusBadRequest, &rBatch)
+
+	assert.Zero(t, rBatch.Succeeded)
+	assert.Equal(t, 2, rBatch.Failed)
+
+	if assert.Len(t, rBatch.Results, 2) {
+		assert.Equal(t, http.StatusFailedDependency, rBatch.Results[0].Status)
+		assert.Zero(t, rBatch.Results[0].ID, "Rolled back items have no id")
+		assert.Equal(t, http.StatusBadRequest, rBatch.Results[1].Status)
+		assert.NotEmpty(t, rBatch.Results[1].Error)
+	}
+
+	books, err := database.GetBooks(context.Background(), url.Values{"title": {"Batch atomic"}})
+	assert.NoError(t, err)
+	assert.Empty(t, books, "No book should be created")
+}
+
+func TestPostBooksBatch_BestEffort(t *testing.T) {
+	body := []byte(`[
+		{"title":"Batch best effort","year":1965,"pages":280,"author":5,"genre":6,"language":2},
+		{"title":"Batch best effort","year":1965,"pages":280,"author":5,"genre":6,"language":999},
+		{"title":"Batch best effort","pages":"many"},
+		{"title":"Batch best effort"}
+	]`)
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books:batch?atomic=false", body, http.StatusMultiStatus, &rBatch)
+
+	assert.False(t, rBatch.Atomic)
+	assert.Equal(t, 1, rBatch.Succeeded)
+	assert.Equal(t, 3, rBatch.Failed)
+
+	if assert.Len(t, rBatch.Results, 4) {
+		defer database.DelBook(context.Background(), rBatch.Results[0].ID)
+
+		assert.Equal(t, http.StatusCreated, rBatch.Results[0].Status)
+		assert.NotZero(t, rBatch.Results[0].ID)
+
+		for _, r := range rBatch.Results[1:] {
+			assert.Equal(t, http.StatusBadRequest, r.Status, "Item %d", r.Index)
+			assert.Zero(t, r.ID)
+		}
+	}
+
+	books, err := database.GetBooks(context.Background(), url.Values{"title": {"Batch best effort"}})
+	assert.NoError(t, err)
+	assert.Len(t, books, 1)
+}
+
+// PATCH /authors:batch
+func TestPatchAuthorsBatch_Success(t *testing.T) {
+	ids, err := database.InsertAuthors(context.Background(), []models.Author{
+		{FirstName: "Batch", LastName: "Patch", BirthYear: 1900},
+		{FirstName: "Batch", LastName: "Patch", BirthYear: 1910},
+	})
+	assert.NoError(t, err)
+
+	for _, id := range ids {
+		defer database.DelAuthor(context.Background(), id)
+	}
+
+	patches := []models.Author{
+		{ID: ids[0], DeathYear: models.I64Ptr(1980)},
+		{ID: ids[1], FirstName: "Patched"},
+	}
+
+	var rBatch models.Batch
+	execAndCheck(t, "PATCH", "/api/v1/authors:batch", marshalCheckNoError(t, patches), http.StatusOK, &rBatch)
+
+	assert.Equal(t, 2, rBatch.Succeeded)
+
+	first, err := database.GetAuthor(context.Background(), ids[0])
+	assert.NoError(t, err)
+	assert.Equal(t, models.I64Ptr(1980), first.DeathYear)
+
+	second, err := database.GetAuthor(context.Background(), ids[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "Patched", second.FirstName)
+	assert.Equal(t, "Patch", second.LastName)
+}
+
+func TestPatchAuthorsBatch_NotFound(t *testing.T) {
+	id, err := database.InsertAuthor(context.Background(), models.Author{FirstName: "Batch", LastName: "Not found"})
+	assert.NoError(t, err)
+	defer database.DelAuthor(context.Background(), id)
+
+	body := marshalCheckNoError(t, []models.Author{
+		{ID: id, FirstName: "Patched"},
+		{ID: 999, FirstName: "Patched"},
+	})
+
+	var rBatch models.Batch
+	execAndCheck(t, "PATCH", "/api/v1/authors:batch", body, http.StatusNotFound, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 2) {
+		assert.Equal(t, http.StatusFailedDependency, rBatch.Results[0].Status)
+		assert.Equal(t, http.StatusNotFound, rBatch.Results[1].Status)
+	}
+
+	author, err := database.GetAuthor(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Batch", author.FirstName, "The patch should be rolled back")
+}
+
+// DELETE /genres:batch
+func TestDeleteGenresBatch_BestEffort(t *testing.T) {
+	ids, err := database.InsertGenres(context.Background(), []models.Genre{{Name: "Batch delete 1"}, {Name: "Batch delete 2"}})
+	assert.NoError(t, err)
+
+	body := marshalCheckNoError(t, []int64{ids[0], 999, ids[1], 6})
+
+	var rBatch models.Batch
+	execAndCheck(t, "DELETE", "/api/v1/genres:batch?atomic=false", body, http.StatusMultiStatus, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 4) {
+		assert.Equal(t, models.BatchResult{Index: 0, Status: http.StatusNoContent, ID: ids[0]}, rBatch.Results[0])
+		assert.Equal(t, http.StatusNotFound, rBatch.Results[1].Status)
+		assert.Equal(t, models.BatchResult{Index: 2, Status: http.StatusNoContent, ID: ids[1]}, rBatch.Results[2])
+		assert.Equal(t, http.StatusBadRequest, rBatch.Results[3].Status, "Genre 6 has books")
+	}
+
+	for _, id := range ids {
+		_, err := database.GetGenre(context.Background(), id)
+		assert.Error(t, err, "Genre %d should be deleted", id)
+	}
+}
+
+// POST /languages:batch, PATCH /languages:batch
+func TestLanguagesBatch_Success(t *testing.T) {
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/languages:batch", []byte(`[{"name":"Batch language"}]`), http.StatusCreated, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 1) {
+		id := rBatch.Results[0].ID
+		defer database.DelLanguage(context.Background(), id)
+
+		body := marshalCheckNoError(t, []models.Language{{ID: id, Name: "Batch language patched"}})
+		execAndCheck(t, "PATCH", "/api/v1/languages:batch", body, http.StatusOK, &rBatch)
+
+		language, err := database.GetLanguage(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Batch language patched", language.Name)
+	}
+}
+
+func TestBatch_Error(t *testing.T) {
+	batchTests := map[string]ErrorTests{
+		"BadRequest_NotArray": {
+			body:   []byte(`{"title":"Batch"}`),
+			query:  "books:batch",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Empty": {
+			body:   []byte(`[]`),
+			query:  "authors:batch",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_TooLarge": {
+			body:   []byte("[" + strings.Repeat(`{"name":"Batch"},`, 1000) + `{"name":"Batch"}]`),
+			query:  "genres:batch",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_Path": {
+			body:   []byte(`[{"name":"Batch"}]`),
+			query:  "languages:other",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "POST", "", batchTests)
+}
+
+func TestBatch_Validation(t *testing.T) {
+	validationTests := map[string]struct {
+		method string
+		query  string
+		body   []byte
+	}{
+		"PostInvalid":     {"POST", "genres:batch", []byte(`[{"name":""}]`)},
+		"PatchNoID":       {"PATCH", "books:batch", []byte(`[{"title":"Batch"}]`)},
+		"PatchNoFields":   {"PATCH", "authors:batch", []byte(`[{"id":1}]`)},
+		"DeleteBadID":     {"DELETE", "languages:batch", []byte(`[0]`)},
+		"DeleteMalformed": {"DELETE", "books:batch", []byte(`["one"]`)},
+	}
+
+	for name, tt := range validationTests {
+		t.Run(name, func(t *testing.T) {
+			var rBatch models.Batch
+			execAndCheck(t, tt.method, "/api/v1/"+tt.query, tt.body, http.StatusBadRequest, &rBatch)
+
+			if assert.Len(t, rBatch.Results, 1) {
+				assert.Equal(t, http.StatusBadRequest, rBatch.Results[0].Status)
+				assert.NotEmpty(t, rBatch.Results[0].Error)
+			}
+		})
+	}
+}
+
+// OPTIONS /:resource:batch
+func TestOptionsBatch_Success(t *testing.T) {
+	optionsTests := map[string]struct {
+		query   string
+		methods []string
+	}{
+		"Books": {
+			"books:batch",
+			[]string{"POST", "PATCH", "DELETE", "OPTIONS"},
+		},
+		"Genres": {
+			"genres:batch",
+			[]string{"POST", "PATCH", "DELETE", "OPTIONS"},
+		},
+	}
+
+	runTestOptionsSuccess(t, "", optionsTests)
+}
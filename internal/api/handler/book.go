@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"errors"
-	"log"
 	"net/http"
 	"strconv"
 
@@ -12,9 +10,9 @@ import (
 )
 
 // @Summary		Get a list of all books
-// @Description	Responds with a list of all books as JSON. Optional filtering, sorting and pagination is available through parameters.
+// @Description	Responds with a list of all books as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.
 // @Tags			Books
-// @Produce		json
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
 // @Param			id					query		string			false	"Book id"
 // @Param			title				query		string			false	"Book title"
 // @Param			year				query		int				false	"Year of publishing of the book"
@@ -22,119 +20,199 @@ import (
 // @Param			author				query		int				false	"Author id"
 // @Param			genre				query		int				false	"Genre id"
 // @Param			language			query		int				false	"Language id"
-// @Param			sort_by				query		string			false	"Sorting by a column"
+// @Param			sort_by				query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
 // @Param			limit				query		int				false	"Limit returned number of resources"
 // @Param			offset				query		int				false	"Offset returned resources"
+// @Param			or					query		string			false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Param			after				query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope			query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format				query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields				query		string			false	"Comma-separated fields to return, e.g. id,title,year"
+// @Param			expand				query		string			false	"Comma-separated relations to embed: author, genre, language"
+// @Param			group_by			query		string			false	"Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead"
+// @Param			agg					query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt		query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
 // @Param			extend				query		bool			false	"Return extended book information"
 // @Param			author.id			query		int				false	"If extend=true - Author id"
 // @Param			author.first_name	query		string			false	"If extend=true - Author first name"
 // @Param			author.last_name	query		string			false	"If extend=true - Author last name"
 // @Success		200					{array}		models.Book		"OK - Fetched books"
-// @Failure		400					{object}	models.Error	"Bad Request - Invalid input"
-// @Failure		401					{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		500					{object}	models.Error	"Internal Server Error"
+// @Header			200					{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200					{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200					{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400					{object}	models.Problem	"Bad Request - Invalid input"
+// @Failure		401					{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		500					{object}	models.Problem	"Internal Server Error"
 // @Router			/books [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetBooks(c *gin.Context) {
-	params := c.Request.URL.Query()
-	extend := c.DefaultQuery("extend", "false")
+	h.listBooks(c, "", 0)
+}
+
+// listBooks responds with the books matching the query parameters, extended
+// if extend=true. The nested routes of the authors, genres and languages
+// pass the field referencing them in parent, which limits the books to
+// the ones whose parent field is parentID.
+func (h *Handlers) listBooks(c *gin.Context, parent string, parentID int64) {
+	params, ok := h.listParams(c)
+	if !ok {
+		return
+	}
+
+	expand, ok := expandParam(c, h.bookRelations())
+	if !ok {
+		return
+	}
+
+	format, ok := listFormat(c, params, expand)
+	if !ok {
+		return
+	}
 
-	var (
-		books any
-		err   error
-	)
+	if db.IsGrouping(params) {
+		if parent != "" {
+			params.Set(parent, strconv.FormatInt(parentID, 10))
+		}
 
-	if extend == "true" {
-		books, err = h.DB.GetBooksExt(params)
-	} else {
-		books, err = h.DB.GetBooks(params)
+		h.listGroups(c, "books", params, expand)
+		return
+	}
+
+	if c.DefaultQuery("extend", "false") == "true" {
+		if len(expand) > 0 {
+			problem(c, http.StatusBadRequest, "Expanding relations of extended books is not supported")
+			return
+		}
+
+		if parent != "" {
+			params.Set(parent+".id", strconv.FormatInt(parentID, 10))
+		}
+
+		switch format {
+		case formatCSV:
+			respondCSV(c, "books", params, h.DB.GetBooksExt)
+			return
+		case formatNDJSON:
+			respondNDJSON(c, h.DB.StreamBooksExt(c.Request.Context(), params))
+			return
+		}
+
+		books, err := h.DB.GetBooksExt(c.Request.Context(), params)
+		if err != nil {
+			handleDBError(c, err)
+			return
+		}
+
+		total, err := h.DB.CountBooksExt(c.Request.Context(), params)
+		if err != nil {
+			handleDBError(c, err)
+			return
+		}
+
+		respondList(c, books, total, h.CursorKey)
+		return
+	}
+
+	if parent != "" {
+		params.Set(parent, strconv.FormatInt(parentID, 10))
 	}
 
-	if errors.Is(err, db.ErrParam) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	switch format {
+	case formatCSV:
+		respondCSV(c, "books", params, h.DB.GetBooks)
+		return
+	case formatNDJSON:
+		respondNDJSON(c, h.DB.StreamBooks(c.Request.Context(), params))
 		return
 	}
 
+	selectRefs(params, expand)
+
+	books, err := h.DB.GetBooks(c.Request.Context(), params)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, books)
+	total, err := h.DB.CountBooks(c.Request.Context(), params)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	respondList(c, books, total, h.CursorKey, expand...)
 }
 
 // @Summary		Get one book
 // @Description	Responds with the queried book as JSON or an error message.
 // @Tags			Books
-// @Produce		json
-// @Param			id	path		int				true	"Book id"
-// @Success		200	{object}	models.Book		"OK - Fetched book"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid book id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		404	{object}	models.Error	"Not Found - No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			id		path		int				true	"Book id"
+// @Param			fields	query		string			false	"Comma-separated fields to return, e.g. id,title,year"
+// @Param			expand	query		string			false	"Comma-separated relations to embed: author, genre, language"
+// @Success		200		{object}	models.Book		"OK - Fetched book"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid book id"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404		{object}	models.Problem	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/books/{id} [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetBook(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	expand, ok := expandParam(c, h.bookRelations())
+	if !ok {
 		return
 	}
 
-	book, err := h.DB.GetBook(int64(id))
-	if errors.Is(err, db.ErrNotFound) {
-		c.JSON(http.StatusNotFound, models.Error{Error: err.Error()})
+	if _, ok := c.GetQuery("fields"); ok {
+		respondFields(c, int64(id), h.DB.GetBooks, expand...)
 		return
 	}
 
+	book, err := h.DB.GetBook(c.Request.Context(), int64(id))
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, book)
+	respondRecord(c, book, expand)
 }
 
 // @Summary		Create a new book
 // @Description	Accepts a JSON body to create a new book. Responds with the created book and set `Location` header or an error message.
 // @Tags			Books
-// @Accept			json
-// @Produce		json
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
 // @Param			book	body		models.Book		true	"New Book"
 // @Success		201		{object}	models.Book		"Created - Added new book"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Header			201		{string}	Location		"Path of the newly created book"
 // @Router			/books [post]
 // @Security		ApiKeyAuth
 func (h *Handlers) PostBook(c *gin.Context) {
 	var newBook models.Book
 
-	if err := c.BindJSON(&newBook); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newBook) {
 		return
 	}
 
-	if newBook.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
-		return
-	}
-
-	id, err := h.DB.InsertBook(newBook)
-	if errors.Is(err, db.ErrForeignKey) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	if v := newBook.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
+	id, err := h.DB.InsertBook(c.Request.Context(), newBook)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
@@ -143,44 +221,43 @@ func (h *Handlers) PostBook(c *gin.Context) {
 	location := c.FullPath() + "/" + strconv.FormatInt(newBook.ID, 10)
 	c.Header("Location", location)
 
-	c.JSON(http.StatusCreated, newBook)
+	respond(c, http.StatusCreated, newBook)
 }
 
 // @Summary		Update an existing book
 // @Description	Accepts a JSON body to update a book. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Books
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack
 // @Param			id		path	int			true	"Existing Book id"
 // @Param			book	body	models.Book	true	"Updated Book"
 // @Success		204		"No content - Updated the book"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404		{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/books/{id} [put]
 // @Security		ApiKeyAuth
 func (h *Handlers) PutBook(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
 	var newBook models.Book
 
-	if err := c.BindJSON(&newBook); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newBook) {
 		return
 	}
 
-	if newBook.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
+	if v := newBook.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
-	if err := h.DB.UpdateWholeBook(int64(id), newBook); err != nil {
+	if err := h.DB.UpdateWholeBook(c.Request.Context(), int64(id), newBook); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -189,35 +266,52 @@ func (h *Handlers) PutBook(c *gin.Context) {
 }
 
 // @Summary		Patch an existing book
-// @Description	Accepts a JSON body with patch data to a book. Responds with a status code. When an error occurs the response body contains JSON data with the message.
+// @Description	Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial book, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Books
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
 // @Param			id		path	int			true	"Existing Book id"
 // @Param			book	body	models.Book	true	"Patches to the book"
 // @Success		204		"No Content - Successfully patched the book"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404		{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		409		{object}	models.Problem	"Conflict - A test operation of the JSON patch failed"
+// @Failure		415		{object}	models.Problem	"Unsupported Media Type - The body is in an unsupported format"
+// @Failure		422		{object}	models.Problem	"Unprocessable Entity - An operation of the JSON patch can't be applied"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/books/{id} [patch]
 // @Security		ApiKeyAuth
 func (h *Handlers) PatchBook(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	if isPatchDocument(c) {
+		patchRecord[models.Book](c, h.DB, int64(id), db.DatabaseInterface.GetBook, db.DatabaseInterface.UpdateWholeBook)
 		return
 	}
 
 	var patchBook models.Book
 
-	if err := c.BindJSON(&patchBook); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &patchBook) {
+		return
+	}
+
+	if patchBook == (models.Book{ID: patchBook.ID}) {
+		problem(c, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	if v := patchBook.ValidatePatch(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more fields are invalid", v...)
 		return
 	}
 
-	if err := h.DB.UpdateBook(int64(id), patchBook); err != nil {
+	if err := h.DB.UpdateBook(c.Request.Context(), int64(id), patchBook); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -230,22 +324,22 @@ func (h *Handlers) PatchBook(c *gin.Context) {
 // @Tags			Books
 // @Param			id	path	int	true	"Book id"
 // @Success		204	"No Content - Successfully deleted the book"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid book id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404	{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Failure		400	{object}	models.Problem	"Bad Request - Invalid book id"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404	{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500	{object}	models.Problem	"Internal Server Error"
 // @Router			/books/{id} [delete]
 // @Security		ApiKeyAuth
 func (h *Handlers) DeleteBook(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if err := h.DB.DelBook(int64(id)); err != nil {
+	if err := h.DB.DelBook(c.Request.Context(), int64(id)); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -257,8 +351,8 @@ func (h *Handlers) DeleteBook(c *gin.Context) {
 // @Description	Responds with an empty response body.
 // @Tags			Books
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
 // @Router			/books [options]
 // @Security		ApiKeyAuth
@@ -272,12 +366,133 @@ func (h *Handlers) OptionsBooks(c *gin.Context) {
 // @Tags			Books
 // @Param			id	path	string	true	"Book id"
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Header			204	{string}	Accept-Patch	"Formats of the bodies of PATCH requests"
 // @Router			/books/{id} [options]
 // @Security		ApiKeyAuth
 func (h *Handlers) OptionsBook(c *gin.Context) {
 	c.Header("Allow", "GET, PUT, PATCH, DELETE, OPTIONS")
+	c.Header("Accept-Patch", acceptPatch)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Create books in a batch
+// @Description	Accepts a JSON array of books to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid books are created and the response is 207 if any item failed.
+// @Tags			Books
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			books	body		[]models.Book	true	"New books"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		201		{object}	models.Batch	"Created - Added all of the books"
+// @Success		207		{object}	models.Batch	"Multi-Status - Added some of the books"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/books:batch [post]
+// @Security		ApiKeyAuth
+func (h *Handlers) PostBooksBatch(c *gin.Context) {
+	books, b, ok := parseBatch(c, checkNew[models.Book])
+	if !ok {
+		return
+	}
+
+	postBatch(c, h.DB, b, books, db.DatabaseInterface.InsertBooks, db.DatabaseInterface.InsertBook)
+}
+
+// @Summary		Patch books in a batch
+// @Description	Accepts a JSON array of patches to books, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.
+// @Tags			Books
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			books	body		[]models.Book	true	"Patches to the books with their ids"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Patched all of the books"
+// @Success		207		{object}	models.Batch	"Multi-Status - Patched some of the books"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/books:batch [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchBooksBatch(c *gin.Context) {
+	books, b, ok := parseBatch(c, func(b *models.Book) (string, models.Violations) {
+		return checkPatch(b.ID, *b == models.Book{ID: b.ID}, b.ValidatePatch())
+	})
+	if !ok {
+		return
+	}
+
+	patchBatch(c, h.DB, b, books, func(b *models.Book) int64 { return b.ID }, db.DatabaseInterface.UpdateBook)
+}
+
+// @Summary		Delete books in a batch
+// @Description	Accepts a JSON array of ids of books to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the books which can be deleted are and the response is 207 if any item failed.
+// @Tags			Books
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			ids		body		[]int			true	"Ids of the books"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Deleted all of the books"
+// @Success		207		{object}	models.Batch	"Multi-Status - Deleted some of the books"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/books:batch [delete]
+// @Security		ApiKeyAuth
+func (h *Handlers) DeleteBooksBatch(c *gin.Context) {
+	ids, b, ok := parseBatch(c, checkID)
+	if !ok {
+		return
+	}
+
+	deleteBatch(c, h.DB, b, ids, db.DatabaseInterface.DelBook)
+}
+
+// @Summary		Import books from CSV
+// @Description	Accepts a CSV with a header row to create a book from each row, in a single transaction. The title, year, pages, author.first_name, author.last_name, genre.name and language.name columns are required; other columns (e.g. of an export of extended books) are ignored. The authors, genres and languages are looked up by their exact names, ignoring case. With create=true the missing ones are created, using the optional author.birth_year and author.death_year columns. Responds with the result of each row, indexed from the first row after the header. By default the import is all-or-nothing: if any row fails, no book is created and the other rows get 424. With atomic=false the valid rows are imported and the response is 207 if any row failed.
+// @Tags			Books
+// @Accept			text/csv
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			books	body		string			true	"CSV with a header row"
+// @Param			create	query		bool			false	"Create the authors, genres and languages which don't exist"
+// @Param			atomic	query		bool			false	"Import the rows all-or-nothing (default) or best-effort when false"
+// @Success		201		{object}	models.Batch	"Created - Imported all of the books"
+// @Success		207		{object}	models.Batch	"Multi-Status - Imported some of the books"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid CSV or rows"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		413		{object}	models.Problem	"Request Entity Too Large - The CSV is larger than 10 MiB"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/books/import [post]
+// @Security		ApiKeyAuth
+func (h *Handlers) ImportBooks(c *gin.Context) {
+	rows, b, ok := parseImport(c)
+	if !ok {
+		return
+	}
+
+	create := c.Query("create") == "true"
+
+	b.run(c, h.DB, http.StatusCreated, nil, func(tx db.DatabaseInterface, i int) (int64, error) {
+		return importBook(c.Request.Context(), tx, rows[i], create)
+	})
+}
+
+// @Summary		Return allowed operations for the import of books
+// @Description	Responds with an empty response body.
+// @Tags			Books
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/books/import [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsBooksImport(c *gin.Context) {
+	c.Header("Allow", "POST, OPTIONS")
 	c.Status(http.StatusNoContent)
 }
@@ -1,9 +1,11 @@
 package handler_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"pawrest/internal/db"
@@ -25,6 +27,35 @@ func TestListBooks_BadRequest_UnknownParam(t *testing.T) {
 	execAndCheckError(t, "GET", "/api/v1/books?foo=bar", nil, http.StatusBadRequest)
 }
 
+func TestListBooks_ContextError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := map[string]struct {
+		ctx    context.Context
+		status int
+	}{
+		"ClientClosedRequest": {
+			ctx:    cancelled,
+			status: 499,
+		},
+		"GatewayTimeout": {
+			ctx:    expired,
+			status: http.StatusGatewayTimeout,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := execRequestWithContext(tt.ctx, "GET", "/api/v1/books", nil)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
 // GET /books/id
 func TestGetBook_Success(t *testing.T) {
 	var rBook models.Book
@@ -70,7 +101,7 @@ func TestPostBook_Success(t *testing.T) {
 	var rBook models.Book
 	jsonBook := marshalCheckNoError(t, testBook)
 	w := execAndCheck(t, "POST", "/api/v1/books", jsonBook, http.StatusCreated, &rBook)
-	defer database.DelBook(rBook.ID)
+	defer database.DelBook(context.Background(), rBook.ID)
 
 	expLoc := fmt.Sprintf("/api/v1/books/%v", rBook.ID)
 	assert.Equal(t, expLoc, w.Result().Header.Get("Location"))
@@ -134,7 +165,7 @@ func TestPutBook_Success(t *testing.T) {
 	jsonBook := marshalCheckNoError(t, testBook)
 	execAndCheck(t, "PUT", "/api/v1/books/1", jsonBook, http.StatusNoContent, nil)
 
-	book, _ := database.GetBook(1)
+	book, _ := database.GetBook(context.Background(), 1)
 	assert.Equal(t, testBook.Title, book.Title)
 	assert.Equal(t, testBook.Year, book.Year)
 	assert.Equal(t, testBook.Pages, book.Pages)
@@ -205,7 +236,7 @@ func TestPatchBook_Success(t *testing.T) {
 	jsonBytes := []byte(`{"title":"Patch book test", "pages":999}`)
 	execAndCheck(t, "PATCH", "/api/v1/books/1", jsonBytes, http.StatusNoContent, nil)
 
-	book, _ := database.GetBook(1)
+	book, _ := database.GetBook(context.Background(), 1)
 	assert.Equal(t, "Patch book test", book.Title)
 	assert.Equal(t, int64(999), book.Pages)
 }
@@ -236,7 +267,7 @@ func TestPatchBook_Error(t *testing.T) {
 func TestDeleteBook_Success(t *testing.T) {
 	execAndCheck(t, "DELETE", "/api/v1/books/2", nil, http.StatusNoContent, nil)
 
-	_, err := database.GetBook(2)
+	_, err := database.GetBook(context.Background(), 2)
 	assert.ErrorIs(t, err, db.ErrNotFound)
 }
 
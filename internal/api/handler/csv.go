@@ -0,0 +1,403 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+const (
+	// csvPageSize is the number of records fetched at a time
+	// when a list is exported as CSV.
+	csvPageSize = 500
+	// maxImportRows limits the number of rows of an imported CSV.
+	maxImportRows = 10000
+	// maxImportSize limits the size of an imported CSV in bytes.
+	maxImportSize = 10 << 20
+)
+
+// importColumns are the columns required in the header of an imported CSV,
+// named like the query parameters of extended books, so that an export of
+// extended books can be imported back.
+var importColumns = []string{"title", "year", "pages", "author.first_name", "author.last_name", "genre.name", "language.name"}
+
+// formulaPrefixes are the first characters which make spreadsheets read a
+// cell as a formula. Exported text starting with them, or with the quote
+// escaping them, is prefixed with a quote, and imports drop the quote.
+const formulaPrefixes = "=+-@\t\r'"
+
+// respondCSV writes the records matching params as CSV with a header row.
+// Unless the request is paginated, the records are fetched by list a page at
+// a time, following the cursor of each page, and every page is flushed before
+// the next one is fetched, so that the whole list is never held in memory.
+// The write deadline is extended with every page.
+// If fetching a page fails, the CSV is cut short and the X-Stream-Error
+// trailer is set.
+// The columns are the ones listed in the fields parameter or all fields of T,
+// with nested records (e.g. author of extended books) flattened into dotted
+// columns (author.last_name).
+func respondCSV[T any](c *gin.Context, name string, params url.Values, list func(context.Context, url.Values) ([]T, error)) {
+	ctx := c.Request.Context()
+
+	columns := requestedFields(c)
+	if columns == nil {
+		columns = csvColumns(reflect.TypeFor[T](), "")
+	}
+
+	paged := !params.Has("limit") && !params.Has("offset")
+	if paged {
+		params.Set("limit", strconv.Itoa(csvPageSize))
+
+		// The cursor is read from the id and the sorted fields of the last record.
+		if params.Has("fields") {
+			keys, _ := db.ParseSort(params.Get("sort_by"))
+
+			fields := append(slices.Clone(columns), "id")
+			for _, k := range keys {
+				fields = append(fields, k.Param)
+			}
+
+			params.Set("fields", strings.Join(fields, ","))
+		}
+	}
+
+	records, err := list(ctx, params)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	c.Header("Trailer", streamErrorTrailer)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write(columns)
+
+	for {
+		for _, r := range records {
+			w.Write(csvRow(r, columns))
+		}
+
+		w.Flush()
+		c.Writer.Flush()
+		extendWriteDeadline(c)
+
+		if !paged || len(records) < csvPageSize {
+			return
+		}
+
+		cursor, ok := nextCursor(records[len(records)-1], params.Get("sort_by"))
+		if !ok {
+			return
+		}
+
+		params.Set("after", cursor.Encode())
+
+		if records, err = list(ctx, params); err != nil {
+			streamFailed(c, err)
+			return
+		}
+	}
+}
+
+// csvColumns returns the JSON names of the fields of the struct t, prefixed
+// with prefix. The fields of nested structs are returned as dotted names.
+func csvColumns(t reflect.Type, prefix string) []string {
+	var columns []string
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			columns = append(columns, csvColumns(f.Type, prefix+name+".")...)
+		} else {
+			columns = append(columns, prefix+name)
+		}
+	}
+
+	return columns
+}
+
+// csvRow returns the values of the columns of record. Null values are
+// written as empty strings and nested objects as JSON. Text which would be
+// read as a formula is escaped, see formulaPrefixes.
+func csvRow(record any, columns []string) []string {
+	obj, _ := jsonObject(record)
+	row := make([]string, len(columns))
+
+	for i, column := range columns {
+		value, _ := lookupField(obj, column)
+
+		switch v := value.(type) {
+		case nil:
+		case string:
+			if v != "" && strings.ContainsRune(formulaPrefixes, rune(v[0])) {
+				v = "'" + v
+			}
+
+			row[i] = v
+		case float64:
+			row[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			row[i] = strconv.FormatBool(v)
+		default:
+			data, _ := json.Marshal(v)
+			row[i] = string(data)
+		}
+	}
+
+	return row
+}
+
+// bookRow is a row of an imported CSV: a book whose author, genre
+// and language are given by their names instead of their ids.
+type bookRow struct {
+	book     models.Book
+	author   models.Author
+	genre    models.Genre
+	language models.Language
+}
+
+// parseImport reads the CSV in the request body into rows, a row at a time,
+// stopping as soon as there are too many of them. The rows whose numbers
+// can't be parsed or which miss a name fail with 400. It responds with 400
+// and returns false when the body isn't CSV with a header of importColumns or
+// it has no rows or more than maxImportRows, and with 413 when it's larger
+// than maxImportSize.
+func parseImport(c *gin.Context) ([]bookRow, *batch, bool) {
+	r := csv.NewReader(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	// invalid responds to the error of reading a record, other than io.EOF.
+	invalid := func(err error) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("The CSV must be at most %d MiB", maxImportSize>>20))
+			return
+		}
+
+		problem(c, http.StatusBadRequest, "Invalid CSV in request body")
+	}
+
+	columns, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		problem(c, http.StatusBadRequest, "The CSV must start with a header row")
+		return nil, nil, false
+	}
+
+	if err != nil {
+		invalid(err)
+		return nil, nil, false
+	}
+
+	header := map[string]int{}
+	for i, column := range columns {
+		// Spreadsheets tend to start UTF-8 files with a byte order mark.
+		column = strings.TrimPrefix(column, "\uFEFF")
+		header[strings.TrimSpace(column)] = i
+	}
+
+	var missing []string
+	for _, column := range importColumns {
+		if _, ok := header[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+
+	if len(missing) > 0 {
+		problem(c, http.StatusBadRequest, "The CSV header is missing columns: "+strings.Join(missing, ", "))
+		return nil, nil, false
+	}
+
+	var records [][]string
+
+	for len(records) <= maxImportRows {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			invalid(err)
+			return nil, nil, false
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) == 0 || len(records) > maxImportRows {
+		problem(c, http.StatusBadRequest, fmt.Sprintf("The CSV must contain from 1 to %d rows", maxImportRows))
+		return nil, nil, false
+	}
+
+	b := newBatch(c, len(records))
+	rows := make([]bookRow, len(records))
+
+	for i, record := range records {
+		if msg := parseBookRow(record, header, &rows[i]); msg != "" {
+			b.results[i].Status = http.StatusBadRequest
+			b.results[i].Error = msg
+		}
+	}
+
+	return rows, b, true
+}
+
+// parseBookRow parses the record of an imported CSV with the column
+// indexes in header into row, dropping the quotes of the escaped formulas
+// of exports. It returns an error message if it fails.
+func parseBookRow(record []string, header map[string]int, row *bookRow) string {
+	value := func(column string) string {
+		i, ok := header[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		v := strings.TrimSpace(record[i])
+		if len(v) > 1 && v[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(v[1])) {
+			v = v[1:]
+		}
+
+		return v
+	}
+
+	row.book.Title = value("title")
+	row.author.FirstName = value("author.first_name")
+	row.author.LastName = value("author.last_name")
+	row.genre.Name = value("genre.name")
+	row.language.Name = value("language.name")
+
+	numbers := []struct {
+		column   string
+		optional bool
+		dst      *int64
+	}{
+		{"year", false, &row.book.Year},
+		{"pages", false, &row.book.Pages},
+		{"author.birth_year", true, &row.author.BirthYear},
+	}
+
+	for _, n := range numbers {
+		v := value(n.column)
+		if v == "" && n.optional {
+			continue
+		}
+
+		var err error
+		if *n.dst, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return "Invalid number in the " + n.column + " column"
+		}
+	}
+
+	if v := value("author.death_year"); v != "" {
+		death, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "Invalid number in the author.death_year column"
+		}
+
+		row.author.DeathYear = &death
+	}
+
+	if row.author.FirstName == "" || row.author.LastName == "" || row.genre.Name == "" || row.language.Name == "" {
+		return "The author, genre and language names are required"
+	}
+
+	return ""
+}
+
+// importBook inserts the book of row, resolving the names of its author,
+// genre and language to ids. The missing ones are created if create is set.
+func importBook(ctx context.Context, tx db.DatabaseInterface, row bookRow, create bool) (int64, error) {
+	book := row.book
+
+	var err error
+
+	book.Author, err = resolveName(ctx, "author", row.author.FirstName+" "+row.author.LastName, row.author, create,
+		func(ctx context.Context) (models.Author, error) {
+			return tx.GetAuthorByName(ctx, row.author.FirstName, row.author.LastName)
+		},
+		tx.InsertAuthor, func(a models.Author) int64 { return a.ID })
+	if err != nil {
+		return 0, err
+	}
+
+	book.Genre, err = resolveName(ctx, "genre", row.genre.Name, row.genre, create,
+		func(ctx context.Context) (models.Genre, error) {
+			return tx.GetGenreByName(ctx, row.genre.Name)
+		},
+		tx.InsertGenre, func(g models.Genre) int64 { return g.ID })
+	if err != nil {
+		return 0, err
+	}
+
+	book.Language, err = resolveName(ctx, "language", row.language.Name, row.language, create,
+		func(ctx context.Context) (models.Language, error) {
+			return tx.GetLanguageByName(ctx, row.language.Name)
+		},
+		tx.InsertLanguage, func(l models.Language) int64 { return l.ID })
+	if err != nil {
+		return 0, err
+	}
+
+	if v := book.Validate(); v != nil {
+		return 0, fmt.Errorf("%w: %w", db.ErrParam, v)
+	}
+
+	return tx.InsertBook(ctx, book)
+}
+
+// resolveName returns the id of the record named name, fetched by find.
+// If there's none and create is set, record is inserted with insert.
+func resolveName[T any, P interface {
+	*T
+	Validate() models.Violations
+}](
+	ctx context.Context,
+	what string,
+	name string,
+	record T,
+	create bool,
+	find func(context.Context) (T, error),
+	insert func(context.Context, T) (int64, error),
+	id func(T) int64,
+) (int64, error) {
+	found, err := find(ctx)
+	if err == nil {
+		return id(found), nil
+	}
+
+	if !errors.Is(err, db.ErrNotFound) {
+		return 0, err
+	}
+
+	if !create {
+		return 0, fmt.Errorf("%w: no %s named %q, set create=true to create it", db.ErrForeignKey, what, name)
+	}
+
+	if v := P(&record).Validate(); v != nil {
+		return 0, fmt.Errorf("%w: the %s %q is invalid: %w", db.ErrParam, what, name, v)
+	}
+
+	return insert(ctx, record)
+}
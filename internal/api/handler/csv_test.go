@@ -0,0 +1,376 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+func readCSV(t *testing.T, body *bytes.Buffer) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(body).ReadAll()
+	assert.NoError(t, err, "Error decoding CSV response:", err)
+
+	return records
+}
+
+// GET /books?format=csv
+func TestListBooks_CSV(t *testing.T) {
+	var rBooks []models.Book
+	execAndCheck(t, "GET", "/api/v1/books?sort_by=id", nil, http.StatusOK, &rBooks)
+
+	w := execAndCheck(t, "GET", "/api/v1/books?sort_by=id&format=csv", nil, http.StatusOK, nil)
+
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="books.csv"`)
+
+	records := readCSV(t, w.Body)
+
+	if assert.Len(t, records, len(rBooks)+1, "The header and a row per book") {
+		assert.Equal(t, []string{"id", "title", "year", "pages", "author", "genre", "language"}, records[0])
+		assert.Equal(t, fmt.Sprint(rBooks[0].ID), records[1][0])
+		assert.Equal(t, rBooks[0].Title, records[1][1])
+	}
+}
+
+func TestListBooks_CSVExtended(t *testing.T) {
+	query := url.Values{
+		"format":           {"csv"},
+		"extend":           {"true"},
+		"fields":           {"title,author.last_name,language.name"},
+		"author.last_name": {"Lem"},
+		"sort_by":          {"-year,title"},
+	}
+
+	w := execAndCheck(t, "GET", "/api/v1/books?"+query.Encode(), nil, http.StatusOK, nil)
+	records := readCSV(t, w.Body)
+
+	if assert.Len(t, records, 4) {
+		assert.Equal(t, []string{"title", "author.last_name", "language.name"}, records[0])
+		assert.Equal(t, []string{"Pokój na Ziemi", "Lem", "Polski"}, records[1])
+	}
+}
+
+// GET /authors/:id/books?format=csv
+func TestListAuthorBooks_CSV(t *testing.T) {
+	w := execAndCheck(t, "GET", "/api/v1/authors/5/books?format=csv&fields=year", nil, http.StatusOK, nil)
+	records := readCSV(t, w.Body)
+
+	assert.ElementsMatch(t, [][]string{{"year"}, {"1961"}, {"1961"}, {"1987"}}, records)
+}
+
+// GET /authors?format=csv
+func TestListAuthors_CSVNull(t *testing.T) {
+	id, err := database.InsertAuthor(context.Background(), models.Author{FirstName: "CSV", LastName: "Bezdatny"})
+	assert.NoError(t, err)
+	defer database.DelAuthor(context.Background(), id)
+
+	w := execAndCheck(t, "GET", "/api/v1/authors?format=csv&last_name=Bezdatny", nil, http.StatusOK, nil)
+	records := readCSV(t, w.Body)
+
+	assert.Equal(t, [][]string{
+		{"id", "first_name", "last_name", "birth_year", "death_year"},
+		{fmt.Sprint(id), "CSV", "Bezdatny", "0", ""},
+	}, records)
+}
+
+// GET /genres?format=csv over more than one page of records
+func TestListGenres_CSVPages(t *testing.T) {
+	genres := make([]models.Genre, 1001)
+	for i := range genres {
+		genres[i].Name = fmt.Sprintf("CSV page %04d", i)
+	}
+
+	ids, err := database.InsertGenres(context.Background(), genres)
+	assert.NoError(t, err)
+
+	for _, id := range ids {
+		defer database.DelGenre(context.Background(), id)
+	}
+
+	w := execAndCheck(t, "GET", "/api/v1/genres?format=csv&fields=name&sort_by=-name&name.startswith=CSV%20page", nil, http.StatusOK, nil)
+	records := readCSV(t, w.Body)
+
+	if assert.Len(t, records, 1002) {
+		assert.Equal(t, []string{"name"}, records[0])
+
+		for i, r := range records[1:] {
+			assert.Equal(t, []string{genres[len(genres)-1-i].Name}, r)
+		}
+	}
+}
+
+func TestListBooks_CSVFormula(t *testing.T) {
+	titles := []string{"=HYPERLINK(\"http://example.com\")", "-1+1", "@SUM(A1)", "'=1", "'Quoted", "Plain = text"}
+
+	books := make([]models.Book, len(titles))
+	for i, title := range titles {
+		books[i] = models.Book{Title: title, Year: 1990, Pages: 100, Author: 1, Genre: 1, Language: 1}
+	}
+
+	ids, err := database.InsertBooks(context.Background(), books)
+	assert.NoError(t, err)
+
+	for _, id := range ids {
+		defer database.DelBook(context.Background(), id)
+	}
+
+	w := execAndCheck(t, "GET", "/api/v1/books?format=csv&fields=title&year=1990&pages=100&sort_by=id", nil, http.StatusOK, nil)
+	exported := w.Body.String()
+
+	assert.Equal(t, [][]string{
+		{"title"},
+		{"'=HYPERLINK(\"http://example.com\")"},
+		{"'-1+1"},
+		{"'@SUM(A1)"},
+		{"''=1"},
+		{"''Quoted"},
+		{"Plain = text"},
+	}, readCSV(t, w.Body))
+
+	// The exported titles are imported back without the added quotes.
+	w = execAndCheck(t, "GET", "/api/v1/books?format=csv&extend=true&year=1990&pages=100&sort_by=id", nil, http.StatusOK, nil)
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import", w.Body.Bytes(), http.StatusCreated, &rBatch)
+	defer deleteImported(rBatch)
+
+	if assert.Len(t, rBatch.Results, len(titles), exported) {
+		for i, title := range titles {
+			book, err := database.GetBook(context.Background(), rBatch.Results[i].ID)
+			assert.NoError(t, err)
+			assert.Equal(t, title, book.Title)
+		}
+	}
+}
+
+func TestListCSV_Error(t *testing.T) {
+	csvTests := map[string]ErrorTests{
+		"BadRequest_UnknownFormat": {
+			query:  "books?format=xls",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Expand": {
+			query:  "books?format=csv&expand=author",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Group": {
+			query:  "languages?format=csv&group_by=name",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Envelope": {
+			query:  "genres?format=csv&envelope=true",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Filter": {
+			query:  "authors?format=csv&isbn=1",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_Parent": {
+			query:  "genres/999/books?format=csv",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "GET", "", csvTests)
+}
+
+const importHeader = "title,year,pages,author.first_name,author.last_name,genre.name,language.name\n"
+
+// deleteImported deletes the books created by an import.
+func deleteImported(rBatch models.Batch) {
+	for _, r := range rBatch.Results {
+		if r.Status == http.StatusCreated {
+			database.DelBook(context.Background(), r.ID)
+		}
+	}
+}
+
+// POST /books/import
+func TestImportBooks_Success(t *testing.T) {
+	body := []byte(importHeader +
+		"CSV import 1,1959,220,Stanisław,Lem,Powieść,Polski\n" +
+		"\"CSV import, 2\",1964,300, Stanisław , Lem ,Powieść,Polski\n")
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import", body, http.StatusCreated, &rBatch)
+	defer deleteImported(rBatch)
+
+	assert.Equal(t, 2, rBatch.Succeeded)
+
+	for i, title := range []string{"CSV import 1", "CSV import, 2"} {
+		book, err := database.GetBook(context.Background(), rBatch.Results[i].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, title, book.Title)
+		assert.Equal(t, int64(5), book.Author)
+		assert.Equal(t, int64(6), book.Genre)
+		assert.Equal(t, int64(2), book.Language)
+	}
+}
+
+func TestImportBooks_Create(t *testing.T) {
+	body := []byte("author.birth_year," + strings.TrimSuffix(importHeader, "\n") + ",id\n" +
+		"1940,CSV create,2001,120,Importowany,Autor,Gatunek importu,Język importu,77\n" +
+		",CSV create,2002,130,Importowany,Autor,Gatunek importu,Język importu,\n")
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import?create=true", body, http.StatusCreated, &rBatch)
+
+	if !assert.Len(t, rBatch.Results, 2) {
+		return
+	}
+
+	first, err := database.GetBook(context.Background(), rBatch.Results[0].ID)
+	assert.NoError(t, err)
+	second, err := database.GetBook(context.Background(), rBatch.Results[1].ID)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.Author, second.Author, "The created author should be reused")
+	assert.Equal(t, first.Genre, second.Genre, "The created genre should be reused")
+	assert.Equal(t, first.Language, second.Language, "The created language should be reused")
+
+	author, err := database.GetAuthor(context.Background(), first.Author)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Author{ID: first.Author, FirstName: "Importowany", LastName: "Autor", BirthYear: 1940}, author)
+
+	deleteImported(rBatch)
+	assert.NoError(t, database.DelAuthor(context.Background(), first.Author))
+	assert.NoError(t, database.DelGenre(context.Background(), first.Genre))
+	assert.NoError(t, database.DelLanguage(context.Background(), first.Language))
+}
+
+func TestImportBooks_CreateNamedNull(t *testing.T) {
+	row := "CSV null,2001,120,Stanisław,Lem,Powieść,null\n"
+
+	var first, second models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import?create=true", []byte(importHeader+row), http.StatusCreated, &first)
+	execAndCheck(t, "POST", "/api/v1/books/import", []byte(importHeader+row), http.StatusCreated, &second)
+
+	if !assert.Len(t, first.Results, 1) || !assert.Len(t, second.Results, 1) {
+		return
+	}
+
+	book, err := database.GetBook(context.Background(), second.Results[0].ID)
+	assert.NoError(t, err)
+
+	language, err := database.GetLanguage(context.Background(), book.Language)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", language.Name, "The language named null should be matched by its name")
+
+	deleteImported(first)
+	deleteImported(second)
+	assert.NoError(t, database.DelLanguage(context.Background(), book.Language))
+}
+
+func TestImportBooks_Atomic(t *testing.T) {
+	body := []byte(importHeader +
+		"CSV atomic,1959,220,Stanisław,Lem,Powieść,Polski\n" +
+		"CSV atomic,1959,220,Nieznany,Autor,Powieść,Polski\n")
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import", body, http.StatusBadRequest, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 2) {
+		assert.Equal(t, http.StatusFailedDependency, rBatch.Results[0].Status)
+		assert.Equal(t, http.StatusBadRequest, rBatch.Results[1].Status)
+		assert.Contains(t, rBatch.Results[1].Error, "Nieznany Autor")
+	}
+
+	books, err := database.GetBooks(context.Background(), url.Values{"title": {"CSV atomic"}})
+	assert.NoError(t, err)
+	assert.Empty(t, books, "No book should be imported")
+}
+
+func TestImportBooks_BestEffort(t *testing.T) {
+	body := []byte(importHeader +
+		"CSV best effort,1959,220,Stanisław,Lem,Powieść,Polski\n" +
+		"CSV best effort,rok,220,Stanisław,Lem,Powieść,Polski\n" +
+		"CSV best effort,1959,220,Stanisław,Lem,,Polski\n" +
+		",1959,220,Stanisław,Lem,Powieść,Polski\n" +
+		"CSV best effort,1959,220,Stanisław,Lem,Powieść,Nieznany\n")
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import?atomic=false", body, http.StatusMultiStatus, &rBatch)
+	defer deleteImported(rBatch)
+
+	assert.Equal(t, 1, rBatch.Succeeded)
+	assert.Equal(t, 4, rBatch.Failed)
+
+	if assert.Len(t, rBatch.Results, 5) {
+		assert.Equal(t, http.StatusCreated, rBatch.Results[0].Status)
+		assert.Equal(t, "Invalid number in the year column", rBatch.Results[1].Error)
+
+		for _, r := range rBatch.Results[1:] {
+			assert.Equal(t, http.StatusBadRequest, r.Status, "Row %d", r.Index)
+		}
+	}
+}
+
+func TestImportBooks_RoundTrip(t *testing.T) {
+	w := execAndCheck(t, "GET", "/api/v1/authors/5/books?format=csv&extend=true", nil, http.StatusOK, nil)
+	exported := w.Body.Bytes()
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import", exported, http.StatusCreated, &rBatch)
+	defer deleteImported(rBatch)
+
+	assert.Equal(t, 3, rBatch.Succeeded)
+
+	var rBooks []models.Book
+	execAndCheck(t, "GET", "/api/v1/authors/5/books", nil, http.StatusOK, &rBooks)
+	assert.Len(t, rBooks, 6, "The books should be imported again")
+}
+
+func TestImportBooks_Error(t *testing.T) {
+	importTests := map[string]ErrorTests{
+		"BadRequest_Empty": {
+			body:   []byte(""),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_MissingColumns": {
+			body:   []byte("title,year,pages\nCSV,2000,10\n"),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_NoRows": {
+			body:   []byte(importHeader),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_InvalidCSV": {
+			body:   []byte(importHeader + "\"CSV,2000,10,A,B,C,D\n"),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_TooManyRows": {
+			body:   []byte(importHeader + strings.Repeat("CSV,2000,10,A,B,C,D\n", 10001)),
+			status: http.StatusBadRequest,
+		},
+		"RequestEntityTooLarge_Size": {
+			body:   []byte(importHeader + "CSV," + strings.Repeat("1", 10<<20) + ",10,A,B,C,D\n"),
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	runTestErrors(t, "POST", "books/import", importTests)
+}
+
+// OPTIONS /books/import
+func TestOptionsBooksImport_Success(t *testing.T) {
+	optionsTests := map[string]struct {
+		query   string
+		methods []string
+	}{
+		"Import": {
+			"",
+			[]string{"POST", "OPTIONS"},
+		},
+	}
+
+	runTestOptionsSuccess(t, "books/import", optionsTests)
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/models"
+)
+
+// relation is a resource which the expand parameter embeds
+// in the records of another one.
+type relation struct {
+	// name is the value of the expand parameter and the field
+	// the related records are put in.
+	name string
+	// ref is the field holding the id the related records are loaded by:
+	// a foreign key (e.g. author of a book) or, for reverse relations,
+	// the id of the record (e.g. the books of an author).
+	ref string
+	// load returns the related records mapped to the ids they're loaded by.
+	load func(ctx context.Context, ids []int64) (map[int64]any, error)
+}
+
+// loadOne returns a loader of the records with the given ids, fetched
+// by list and mapped to the ids returned by id.
+func loadOne[T any](list func(context.Context, url.Values) ([]T, error), id func(T) int64) func(context.Context, []int64) (map[int64]any, error) {
+	return func(ctx context.Context, ids []int64) (map[int64]any, error) {
+		records, err := list(ctx, url.Values{"id.in": {joinIDs(ids)}})
+		if err != nil {
+			return nil, err
+		}
+
+		out := map[int64]any{}
+		for _, r := range records {
+			out[id(r)] = r
+		}
+
+		return out, nil
+	}
+}
+
+// loadMany returns a loader of the records whose param field holds one of
+// the given ids, fetched by list and grouped by the ids returned by ref.
+func loadMany[T any](list func(context.Context, url.Values) ([]T, error), param string, ref func(T) int64) func(context.Context, []int64) (map[int64]any, error) {
+	return func(ctx context.Context, ids []int64) (map[int64]any, error) {
+		records, err := list(ctx, url.Values{param + ".in": {joinIDs(ids)}, "sort_by": {"id"}})
+		if err != nil {
+			return nil, err
+		}
+
+		groups := map[int64][]T{}
+		for _, id := range ids {
+			groups[id] = []T{}
+		}
+
+		for _, r := range records {
+			groups[ref(r)] = append(groups[ref(r)], r)
+		}
+
+		out := map[int64]any{}
+		for id, group := range groups {
+			out[id] = group
+		}
+
+		return out, nil
+	}
+}
+
+func (h *Handlers) bookRelations() []relation {
+	return []relation{
+		{"author", "author", loadOne(h.DB.GetAuthors, func(a models.Author) int64 { return a.ID })},
+		{"genre", "genre", loadOne(h.DB.GetGenres, func(g models.Genre) int64 { return g.ID })},
+		{"language", "language", loadOne(h.DB.GetLanguages, func(l models.Language) int64 { return l.ID })},
+	}
+}
+
+func (h *Handlers) authorRelations() []relation {
+	return []relation{
+		{"books", "id", loadMany(h.DB.GetBooks, "author", func(b models.Book) int64 { return b.Author })},
+	}
+}
+
+func (h *Handlers) genreRelations() []relation {
+	return []relation{
+		{"books", "id", loadMany(h.DB.GetBooks, "genre", func(b models.Book) int64 { return b.Genre })},
+	}
+}
+
+func (h *Handlers) languageRelations() []relation {
+	return []relation{
+		{"books", "id", loadMany(h.DB.GetBooks, "language", func(b models.Book) int64 { return b.Language })},
+	}
+}
+
+// expandParam returns the relations listed in the expand parameter,
+// out of the relations of the requested resource. It responds with 400
+// and returns false when an unknown relation is listed.
+func expandParam(c *gin.Context, relations []relation) ([]relation, bool) {
+	list, ok := c.GetQuery("expand")
+	if !ok {
+		return nil, true
+	}
+
+	var expand []relation
+
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+
+		i := slices.IndexFunc(relations, func(r relation) bool { return r.name == name })
+		if i < 0 {
+			problem(c, http.StatusBadRequest, "Provided unknown relation to expand: "+strconv.Quote(name))
+			return nil, false
+		}
+
+		if !slices.ContainsFunc(expand, func(r relation) bool { return r.name == name }) {
+			expand = append(expand, relations[i])
+		}
+	}
+
+	return expand, true
+}
+
+// selectRefs adds the ref fields of the relations in expand to the fields
+// parameter, so that the columns the related records are loaded by are
+// selected. The response is still narrowed down to the requested fields.
+func selectRefs(params url.Values, expand []relation) {
+	if len(params["fields"]) != 1 {
+		return
+	}
+
+	fields := params.Get("fields")
+	for _, r := range expand {
+		fields += "," + r.ref
+	}
+
+	params.Set("fields", fields)
+}
+
+// present returns the records the way they're written to the response:
+// with the expanded relations embedded and narrowed down to the fields
+// parameter. Expanded relations are kept even if they aren't among the
+// fields. Records are returned unchanged when there's nothing to do.
+func present[T any](c *gin.Context, records []T, expand []relation) ([]any, error) {
+	out := make([]any, len(records))
+
+	fields := requestedFields(c)
+	if fields == nil && len(expand) == 0 {
+		for i := range records {
+			out[i] = records[i]
+		}
+
+		return out, nil
+	}
+
+	objects := make([]map[string]any, len(records))
+	for i := range records {
+		obj, err := jsonObject(records[i])
+		if err != nil {
+			return nil, err
+		}
+
+		objects[i] = obj
+	}
+
+	for _, r := range expand {
+		if err := expandRelation(c.Request.Context(), objects, r); err != nil {
+			return nil, err
+		}
+
+		if fields != nil && !slices.Contains(fields, r.name) {
+			fields = append(fields, r.name)
+		}
+	}
+
+	for i, obj := range objects {
+		if fields != nil {
+			out[i] = pickFields(obj, fields)
+		} else {
+			out[i] = obj
+		}
+	}
+
+	return out, nil
+}
+
+// expandRelation loads the records related to objects by r
+// and puts them in the r.name field of each object.
+func expandRelation(ctx context.Context, objects []map[string]any, r relation) error {
+	var ids []int64
+
+	for _, obj := range objects {
+		if id, ok := obj[r.ref].(float64); ok && !slices.Contains(ids, int64(id)) {
+			ids = append(ids, int64(id))
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	related, err := r.load(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		id, ok := obj[r.ref].(float64)
+		if !ok {
+			continue
+		}
+
+		if rec, ok := related[int64(id)]; ok {
+			obj[r.name] = rec
+		}
+	}
+
+	return nil
+}
+
+// respondRecord writes a single record with the expanded relations embedded.
+func respondRecord[T any](c *gin.Context, record T, expand []relation) {
+	out, err := present(c, []T{record}, expand)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, out[0])
+}
+
+func joinIDs(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+	"pawrest/internal/yamlconfig"
+)
+
+func TestExpand_Book(t *testing.T) {
+	var rBook models.BookExt
+	execAndCheck(t, "GET", "/api/v1/books/7?expand=author,genre,language", nil, http.StatusOK, &rBook)
+
+	assert.Equal(t, "Solaris", rBook.Title)
+	assert.Equal(t, "Lem", rBook.Author.LastName)
+	assert.NotEmpty(t, rBook.Genre.Name, "Genre should be expanded")
+	assert.NotEmpty(t, rBook.Language.Name, "Language should be expanded")
+}
+
+func TestExpand_Books(t *testing.T) {
+	var rBooks []map[string]any
+	execAndCheck(t, "GET", "/api/v1/books?expand=author&limit=5", nil, http.StatusOK, &rBooks)
+
+	assert.Len(t, rBooks, 5)
+
+	for _, b := range rBooks {
+		author, ok := b["author"].(map[string]any)
+		if assert.True(t, ok, "Author should be expanded") {
+			assert.NotEmpty(t, author["last_name"])
+		}
+
+		_, ok = b["genre"].(float64)
+		assert.True(t, ok, "Genre should stay an id")
+	}
+}
+
+func TestExpand_ReverseBooks(t *testing.T) {
+	var rAuthor struct {
+		models.Author
+		Books []models.Book `json:"books"`
+	}
+	execAndCheck(t, "GET", "/api/v1/authors/5?expand=books", nil, http.StatusOK, &rAuthor)
+
+	assert.Equal(t, "Lem", rAuthor.LastName)
+	assert.NotEmpty(t, rAuthor.Books)
+
+	for _, b := range rAuthor.Books {
+		assert.Equal(t, int64(5), b.Author)
+	}
+}
+
+func TestExpand_Fields(t *testing.T) {
+	var rBooks []map[string]any
+	execAndCheck(t, "GET", "/api/v1/books?fields=title&expand=language&id=7", nil, http.StatusOK, &rBooks)
+
+	assert.Equal(t, []map[string]any{{
+		"title":    "Solaris",
+		"language": map[string]any{"id": 2.0, "name": "Polski"},
+	}}, rBooks)
+
+	var rGenre map[string]any
+	execAndCheck(t, "GET", "/api/v1/genres/5?fields=name&expand=books", nil, http.StatusOK, &rGenre)
+
+	assert.Len(t, rGenre, 2, "Only the field and the expanded relation should be returned")
+	assert.IsType(t, []any{}, rGenre["books"])
+}
+
+// TestExpand_FieldsSQLite checks that the columns the relations are loaded by
+// are selected along with the requested fields, which the memory store
+// doesn't show, as it always returns every field of the records.
+func TestExpand_FieldsSQLite(t *testing.T) {
+	d, err := db.Connect(&yamlconfig.Config{DBDriver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	defer d.CloseDB()
+
+	if _, err := d.MigrateUp(context.Background()); err != nil {
+		t.Fatalf("Failed to migrate SQLite database: %v", err)
+	}
+
+	router := setupTestRouter(d)
+
+	expandTests := map[string]struct {
+		target string
+		want   any
+	}{
+		"List": {
+			target: "/api/v1/books?fields=title&expand=language&id=7",
+			want: []any{map[string]any{
+				"title":    "Solaris",
+				"language": map[string]any{"id": 2.0, "name": "Polski"},
+			}},
+		},
+		"Single": {
+			target: "/api/v1/books/7?fields=title&expand=author,genre",
+			want: map[string]any{
+				"title":  "Solaris",
+				"author": map[string]any{"id": 5.0, "first_name": "Stanisław", "last_name": "Lem", "birth_year": 1921.0, "death_year": 2006.0},
+				"genre":  map[string]any{"id": 6.0, "name": "Powieść"},
+			},
+		},
+	}
+
+	for name, tt := range expandTests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var got any
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_Error(t *testing.T) {
+	expandTests := map[string]ErrorTests{
+		"BadRequest_UnknownRelation": {
+			query:  "?expand=publisher",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_ReverseOnBooks": {
+			query:  "/1?expand=books",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Extend": {
+			query:  "?extend=true&expand=author",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_Single": {
+			query:  "/9999?expand=author",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "GET", "books", expandTests)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+)
+
+// requestedFields returns the fields listed in the fields parameter,
+// or nil when every field was requested.
+func requestedFields(c *gin.Context) []string {
+	list, ok := c.GetQuery("fields")
+	if !ok {
+		return nil
+	}
+
+	var fields []string
+	for field := range strings.SplitSeq(list, ",") {
+		fields = append(fields, strings.TrimSpace(field))
+	}
+
+	return fields
+}
+
+// respondFields responds with the requested fields of the record with id,
+// fetched by list so that only the columns of these fields are selected.
+// The relations in expand are embedded in it.
+func respondFields[T any](c *gin.Context, id int64, list func(context.Context, url.Values) ([]T, error), expand ...relation) {
+	params := url.Values{
+		"id":     {strconv.FormatInt(id, 10)},
+		"fields": {c.Query("fields")},
+	}
+
+	selectRefs(params, expand)
+
+	records, err := list(c.Request.Context(), params)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	if len(records) == 0 {
+		handleDBError(c, fmt.Errorf("%w with id %v", db.ErrNotFound, id))
+		return
+	}
+
+	respondRecord(c, records[0], expand)
+}
+
+// pickFields returns the JSON form of record narrowed down to fields.
+func pickFields(record any, fields []string) map[string]any {
+	obj, err := jsonObject(record)
+	if err != nil {
+		return nil
+	}
+
+	out := map[string]any{}
+
+	for _, field := range fields {
+		value, ok := lookupField(obj, field)
+		if !ok {
+			continue
+		}
+
+		keys := strings.Split(field, ".")
+		parent := out
+
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[key] = child
+			}
+
+			parent = child
+		}
+
+		parent[keys[len(keys)-1]] = value
+	}
+
+	return out
+}
+
+// jsonObject returns the JSON form of record as a map.
+func jsonObject(record any) (map[string]any, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]any
+	err = json.Unmarshal(data, &obj)
+
+	return obj, err
+}
+
+// lookupField returns the value of field in the JSON object obj. The dotted
+// query parameter names (e.g. author.last_name) are paths to nested fields.
+func lookupField(obj map[string]any, field string) (any, bool) {
+	var value any = obj
+
+	for key := range strings.SplitSeq(field, ".") {
+		o, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = o[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields_List(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  map[string]any
+	}{
+		"Books": {
+			query: "/books?fields=id,title&id=7",
+			want:  map[string]any{"id": 7.0, "title": "Solaris"},
+		},
+		"BooksExtended": {
+			query: "/books?extend=true&fields=title,author.last_name&sort_by=year&id=7",
+			want:  map[string]any{"title": "Solaris", "author": map[string]any{"last_name": "Lem"}},
+		},
+		"AuthorsNull": {
+			query: "/authors?fields=death_year&last_name=Lem",
+			want:  map[string]any{"death_year": 2006.0},
+		},
+		"Genres": {
+			query: "/genres?fields=name&name=Dramat",
+			want:  map[string]any{"name": "Dramat"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rList []map[string]any
+			execAndCheck(t, "GET", "/api/v1"+tt.query, nil, http.StatusOK, &rList)
+
+			assert.Equal(t, []map[string]any{tt.want}, rList)
+		})
+	}
+}
+
+func TestFields_Single(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  map[string]any
+	}{
+		"Book": {
+			query: "/books/7?fields=title,year",
+			want:  map[string]any{"title": "Solaris", "year": 1961.0},
+		},
+		"Author": {
+			query: "/authors/5?fields=last_name",
+			want:  map[string]any{"last_name": "Lem"},
+		},
+		"Language": {
+			query: "/languages/2?fields=id",
+			want:  map[string]any{"id": 2.0},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rObj map[string]any
+			execAndCheck(t, "GET", "/api/v1"+tt.query, nil, http.StatusOK, &rObj)
+
+			assert.Equal(t, tt.want, rObj)
+		})
+	}
+}
+
+func TestFields_Error(t *testing.T) {
+	fieldsTests := map[string]ErrorTests{
+		"BadRequest_UnknownField": {
+			query:  "?fields=title,isbn",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_EmptyField": {
+			query:  "?fields=",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_UnknownFieldSingle": {
+			query:  "/1?fields=author.last_name",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_Single": {
+			query:  "/9999?fields=title",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "GET", "books", fieldsTests)
+}
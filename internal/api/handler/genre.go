@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"errors"
-	"log"
 	"net/http"
 	"strconv"
 
@@ -12,109 +10,197 @@ import (
 )
 
 // @Summary		Get a list of all genres
-// @Description	Responds with a list of all genres as JSON. Optional filtering, sorting and pagination is available through parameters.
+// @Description	Responds with a list of all genres as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.
 // @Tags			Genres
-// @Produce		json
-// @Param			id		query		string			false	"Genre id"
-// @Param			name	query		string			false	"Genre name"
-// @Param			sort_by	query		string			false	"Sorting by a column"
-// @Param			limit	query		int				false	"Limit returned number of resources"
-// @Param			offset	query		int				false	"Offset returned resources"
-// @Success		200		{array}		models.Genre	"OK - Fetched genres"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				query		string			false	"Genre id"
+// @Param			name			query		string			false	"Genre name"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			or				query		string			false	"Conditions of which any must match, e.g. (name:Dramat,name:Nowela)"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. name"
+// @Param			expand			query		string			false	"Relations to embed: books"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the records by, e.g. name; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Success		200				{array}		models.Genre	"OK - Fetched genres"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
 // @Router			/genres [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetGenres(c *gin.Context) {
-	params := c.Request.URL.Query()
+	params, ok := h.listParams(c)
+	if !ok {
+		return
+	}
 
-	genres, err := h.DB.GetGenres(params)
-	if errors.Is(err, db.ErrParam) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	expand, ok := expandParam(c, h.genreRelations())
+	if !ok {
 		return
 	}
 
+	format, ok := listFormat(c, params, expand)
+	if !ok {
+		return
+	}
+
+	if db.IsGrouping(params) {
+		h.listGroups(c, "genres", params, expand)
+		return
+	}
+
+	switch format {
+	case formatCSV:
+		respondCSV(c, "genres", params, h.DB.GetGenres)
+		return
+	case formatNDJSON:
+		respondNDJSON(c, h.DB.StreamGenres(c.Request.Context(), params))
+		return
+	}
+
+	selectRefs(params, expand)
+
+	genres, err := h.DB.GetGenres(c.Request.Context(), params)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
+		return
+	}
+
+	total, err := h.DB.CountGenres(c.Request.Context(), params)
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, genres)
+	respondList(c, genres, total, h.CursorKey, expand...)
 }
 
 // @Summary		Get one genre
 // @Description	Responds with the queried genre as JSON or an error message.
 // @Tags			Genres
-// @Produce		json
-// @Param			id	path		int				true	"Genre id"
-// @Success		200	{object}	models.Genre	"OK - Fetched genre"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid genre id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		404	{object}	models.Error	"Not Found - No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			id		path		int				true	"Genre id"
+// @Param			fields	query		string			false	"Comma-separated fields to return, e.g. name"
+// @Param			expand	query		string			false	"Relations to embed: books"
+// @Success		200		{object}	models.Genre	"OK - Fetched genre"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid genre id"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404		{object}	models.Problem	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/genres/{id} [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetGenre(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	expand, ok := expandParam(c, h.genreRelations())
+	if !ok {
+		return
+	}
+
+	if _, ok := c.GetQuery("fields"); ok {
+		respondFields(c, int64(id), h.DB.GetGenres, expand...)
 		return
 	}
 
-	genre, err := h.DB.GetGenre(int64(id))
-	if errors.Is(err, db.ErrNotFound) {
-		c.JSON(http.StatusNotFound, models.Error{Error: err.Error()})
+	genre, err := h.DB.GetGenre(c.Request.Context(), int64(id))
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
+	respondRecord(c, genre, expand)
+}
+
+// @Summary		Get a list of books of one genre
+// @Description	Responds with a list of the books of the genre as JSON or an error message if the genre doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.
+// @Tags			Genres
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				path		int				true	"Genre id"
+// @Param			title			query		string			false	"Book title"
+// @Param			year			query		int				false	"Year of publishing of the book"
+// @Param			pages			query		int				false	"Number of pages in the book"
+// @Param			author			query		int				false	"Author id"
+// @Param			language		query		int				false	"Language id"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. id,title,year"
+// @Param			expand			query		string			false	"Comma-separated relations to embed: author, genre, language"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Param			extend			query		bool			false	"Return extended book information"
+// @Success		200				{array}		models.Book		"OK - Fetched books"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid genre id or input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404				{object}	models.Problem	"Not Found - No genre found"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
+// @Router			/genres/{id}/books [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetGenreBooks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	if _, err := h.DB.GetGenre(c.Request.Context(), int64(id)); err != nil {
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, genre)
+	h.listBooks(c, "genre", int64(id))
 }
 
 // @Summary		Create a new genre
 // @Description	Accepts a JSON body to create a new genre. Responds with the created genre and set `Location` header or an error message.
 // @Tags			Genres
-// @Accept			json
-// @Produce		json
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
 // @Param			genre	body		models.Genre	true	"New Genre"
 // @Success		201		{object}	models.Genre	"Created - Added new genre"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Header			201		{string}	Location		"Path of the newly created genre"
 // @Router			/genres [post]
 // @Security		ApiKeyAuth
 func (h *Handlers) PostGenre(c *gin.Context) {
 	var newGenre models.Genre
 
-	if err := c.BindJSON(&newGenre); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newGenre) {
 		return
 	}
 
-	if newGenre.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
-		return
-	}
-
-	id, err := h.DB.InsertGenre(newGenre)
-	if errors.Is(err, db.ErrForeignKey) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	if v := newGenre.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
+	id, err := h.DB.InsertGenre(c.Request.Context(), newGenre)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
@@ -123,44 +209,97 @@ func (h *Handlers) PostGenre(c *gin.Context) {
 	location := c.FullPath() + "/" + strconv.FormatInt(newGenre.ID, 10)
 	c.Header("Location", location)
 
-	c.JSON(http.StatusCreated, newGenre)
+	respond(c, http.StatusCreated, newGenre)
 }
 
 // @Summary		Update an existing genre
 // @Description	Accepts a JSON body to update a genre. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Genres
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack
 // @Param			id		path	int				true	"Existing Genre id"
 // @Param			genre	body	models.Genre	true	"Updated Genre"
 // @Success		204		"No content - Updated the genre"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403		{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404		{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/genres/{id} [put]
 // @Security		ApiKeyAuth
 func (h *Handlers) PutGenre(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
 	var newGenre models.Genre
 
-	if err := c.BindJSON(&newGenre); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newGenre) {
+		return
+	}
+
+	if v := newGenre.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
+		return
+	}
+
+	if err := h.DB.UpdateWholeGenre(c.Request.Context(), int64(id), newGenre); err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Patch an existing genre
+// @Description	Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial genre, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.
+// @Tags			Genres
+// @Accept			json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
+// @Param			id		path	int				true	"Existing Genre id"
+// @Param			genre	body	models.Genre	true	"Patches to the genre"
+// @Success		204		"No Content - Successfully patched the genre"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		409		{object}	models.Problem	"Conflict - A test operation of the JSON patch failed"
+// @Failure		415		{object}	models.Problem	"Unsupported Media Type - The body is in an unsupported format"
+// @Failure		422		{object}	models.Problem	"Unprocessable Entity - An operation of the JSON patch can't be applied"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/genres/{id} [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchGenre(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if newGenre.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
+	if isPatchDocument(c) {
+		patchRecord[models.Genre](c, h.DB, int64(id), db.DatabaseInterface.GetGenre, db.DatabaseInterface.UpdateWholeGenre)
 		return
 	}
 
-	if err := h.DB.UpdateWholeGenre(int64(id), newGenre); err != nil {
+	var patchGenre models.Genre
+
+	if !bindBody(c, &patchGenre) {
+		return
+	}
+
+	if patchGenre == (models.Genre{ID: patchGenre.ID}) {
+		problem(c, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	if v := patchGenre.ValidatePatch(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more fields are invalid", v...)
+		return
+	}
+
+	if err := h.DB.UpdateWholeGenre(c.Request.Context(), int64(id), patchGenre); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -173,22 +312,22 @@ func (h *Handlers) PutGenre(c *gin.Context) {
 // @Tags			Genres
 // @Param			id	path	int	true	"Genre id"
 // @Success		204	"No Content - Successfully deleted the genre"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid genre id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404	{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Failure		400	{object}	models.Problem	"Bad Request - Invalid genre id"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404	{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500	{object}	models.Problem	"Internal Server Error"
 // @Router			/genres/{id} [delete]
 // @Security		ApiKeyAuth
 func (h *Handlers) DeleteGenre(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if err := h.DB.DelGenre(int64(id)); err != nil {
+	if err := h.DB.DelGenre(c.Request.Context(), int64(id)); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -200,8 +339,8 @@ func (h *Handlers) DeleteGenre(c *gin.Context) {
 // @Description	Responds with an empty response body.
 // @Tags			Genres
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
 // @Router			/genres [options]
 // @Security		ApiKeyAuth
@@ -215,12 +354,104 @@ func (h *Handlers) OptionsGenres(c *gin.Context) {
 // @Tags			Genres
 // @Param			id	path	string	true	"Genre id"
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Header			204	{string}	Accept-Patch	"Formats of the bodies of PATCH requests"
 // @Router			/genres/{id} [options]
 // @Security		ApiKeyAuth
 func (h *Handlers) OptionsGenre(c *gin.Context) {
-	c.Header("Allow", "GET, PUT, DELETE, OPTIONS")
+	c.Header("Allow", "GET, PUT, PATCH, DELETE, OPTIONS")
+	c.Header("Accept-Patch", acceptPatch)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Return allowed operations for books of genre
+// @Description	Responds with an empty response body.
+// @Tags			Genres
+// @Param			id	path	string	true	"Genre id"
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/genres/{id}/books [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsGenreBooks(c *gin.Context) {
+	c.Header("Allow", "GET, OPTIONS")
 	c.Status(http.StatusNoContent)
 }
+
+// @Summary		Create genres in a batch
+// @Description	Accepts a JSON array of genres to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid genres are created and the response is 207 if any item failed.
+// @Tags			Genres
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			genres	body		[]models.Genre	true	"New genres"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		201		{object}	models.Batch	"Created - Added all of the genres"
+// @Success		207		{object}	models.Batch	"Multi-Status - Added some of the genres"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/genres:batch [post]
+// @Security		ApiKeyAuth
+func (h *Handlers) PostGenresBatch(c *gin.Context) {
+	genres, b, ok := parseBatch(c, checkNew[models.Genre])
+	if !ok {
+		return
+	}
+
+	postBatch(c, h.DB, b, genres, db.DatabaseInterface.InsertGenres, db.DatabaseInterface.InsertGenre)
+}
+
+// @Summary		Patch genres in a batch
+// @Description	Accepts a JSON array of patches to genres, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.
+// @Tags			Genres
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			genres	body		[]models.Genre	true	"Patches to the genres with their ids"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Patched all of the genres"
+// @Success		207		{object}	models.Batch	"Multi-Status - Patched some of the genres"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/genres:batch [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchGenresBatch(c *gin.Context) {
+	genres, b, ok := parseBatch(c, func(g *models.Genre) (string, models.Violations) {
+		return checkPatch(g.ID, *g == models.Genre{ID: g.ID}, g.ValidatePatch())
+	})
+	if !ok {
+		return
+	}
+
+	patchBatch(c, h.DB, b, genres, func(g *models.Genre) int64 { return g.ID }, db.DatabaseInterface.UpdateWholeGenre)
+}
+
+// @Summary		Delete genres in a batch
+// @Description	Accepts a JSON array of ids of genres to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the genres which can be deleted are and the response is 207 if any item failed.
+// @Tags			Genres
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			ids		body		[]int			true	"Ids of the genres"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Deleted all of the genres"
+// @Success		207		{object}	models.Batch	"Multi-Status - Deleted some of the genres"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/genres:batch [delete]
+// @Security		ApiKeyAuth
+func (h *Handlers) DeleteGenresBatch(c *gin.Context) {
+	ids, b, ok := parseBatch(c, checkID)
+	if !ok {
+		return
+	}
+
+	deleteBatch(c, h.DB, b, ids, db.DatabaseInterface.DelGenre)
+}
@@ -1,8 +1,10 @@
 package handler_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,6 +48,38 @@ func TestGetGenre_Error(t *testing.T) {
 	runTestErrors(t, "GET", "genres", getTests)
 }
 
+// GET /genres/id/books
+func TestListGenreBooks_Success(t *testing.T) {
+	var rBooks []models.Book
+	w := execAndCheck(t, "GET", "/api/v1/genres/6/books", nil, http.StatusOK, &rBooks)
+
+	assert.NotEmpty(t, rBooks)
+	assert.Equal(t, strconv.Itoa(len(rBooks)), w.Header().Get("X-Total-Count"))
+
+	for _, b := range rBooks {
+		assert.Equal(t, int64(6), b.Genre, "Only the books of the genre should be listed")
+	}
+}
+
+func TestListGenreBooks_Error(t *testing.T) {
+	listTests := map[string]ErrorTests{
+		"NotFound_BigPathID": {
+			query:  "/9999/books",
+			status: http.StatusNotFound,
+		},
+		"BadRequest_StringPathID": {
+			query:  "/string/books",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_UnknownParam": {
+			query:  "/6/books?foo=bar",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "genres", listTests)
+}
+
 // POST /genres
 func TestPostGenre_Success(t *testing.T) {
 	testGenre := models.Genre{
@@ -55,7 +89,7 @@ func TestPostGenre_Success(t *testing.T) {
 	var rGenre models.Genre
 	jsonGenre := marshalCheckNoError(t, testGenre)
 	w := execAndCheck(t, "POST", "/api/v1/genres", jsonGenre, http.StatusCreated, &rGenre)
-	defer database.DelGenre(rGenre.ID)
+	defer database.DelGenre(context.Background(), rGenre.ID)
 
 	expLoc := fmt.Sprintf("/api/v1/genres/%v", rGenre.ID)
 	assert.Equal(t, expLoc, w.Result().Header.Get("Location"))
@@ -92,7 +126,7 @@ func TestPutGenre_Success(t *testing.T) {
 	jsonGenre := marshalCheckNoError(t, testGenre)
 	execAndCheck(t, "PUT", "/api/v1/genres/1", jsonGenre, http.StatusNoContent, nil)
 
-	genre, _ := database.GetGenre(1)
+	genre, _ := database.GetGenre(context.Background(), 1)
 	assert.Equal(t, testGenre.Name, genre.Name)
 }
 
@@ -129,7 +163,7 @@ func TestPutGenre_Error(t *testing.T) {
 
 // DELETE /genres/id
 func TestDeleteGenre_Success(t *testing.T) {
-	newID, err := database.InsertGenre(models.Genre{
+	newID, err := database.InsertGenre(context.Background(), models.Genre{
 		Name: "Delete tester",
 	})
 	assert.NoError(t, err)
@@ -137,7 +171,7 @@ func TestDeleteGenre_Success(t *testing.T) {
 	newGenreLoc := fmt.Sprintf("/api/v1/genres/%v", newID)
 	execAndCheck(t, "DELETE", newGenreLoc, nil, http.StatusNoContent, nil)
 
-	_, err = database.GetGenre(newID)
+	_, err = database.GetGenre(context.Background(), newID)
 	assert.ErrorIs(t, err, db.ErrNotFound)
 }
 
@@ -160,6 +194,7 @@ func TestDeleteGenre_Error(t *testing.T) {
 
 // OPTIONS /genres
 // OPTIONS /genres/id
+// OPTIONS /genres/id/books
 func TestOptionsGenres_Success(t *testing.T) {
 	optionsTests := map[string]struct {
 		query   string
@@ -171,7 +206,11 @@ func TestOptionsGenres_Success(t *testing.T) {
 		},
 		"PathID": {
 			"/1",
-			[]string{"GET", "PUT", "DELETE", "OPTIONS"},
+			[]string{"GET", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		},
+		"Books": {
+			"/1/books",
+			[]string{"GET", "OPTIONS"},
 		},
 	}
 
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listGroups responds with the groups of resource requested by the
+// group_by, agg and having.* parameters in params instead of the records,
+// along with the number of all of the groups in the X-Total-Count header.
+// Groups have no relations, so expanding them is rejected.
+func (h *Handlers) listGroups(c *gin.Context, resource string, params url.Values, expand []relation) {
+	if len(expand) > 0 {
+		problem(c, http.StatusBadRequest, "Expanding relations of groups is not supported")
+		return
+	}
+
+	groups, err := h.DB.Group(c.Request.Context(), resource, params)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	total, err := h.DB.CountGroups(c.Request.Context(), resource, params)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	respondList(c, groups, total, h.CursorKey)
+}
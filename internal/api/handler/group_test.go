@@ -0,0 +1,90 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+// GET /books?group_by=...
+func TestListBooks_Group(t *testing.T) {
+	query := url.Values{
+		"group_by":         {"genre.name"},
+		"agg":              {"count,avg(pages),max(year)"},
+		"having.count.gte": {"2"},
+		"sort_by":          {"-count"},
+	}
+
+	var rGroups []models.Group
+	w := execAndCheck(t, "GET", "/api/v1/books?"+query.Encode(), nil, http.StatusOK, &rGroups)
+
+	assert.Equal(t, strconv.Itoa(len(rGroups)), w.Header().Get("X-Total-Count"))
+
+	for i, g := range rGroups {
+		assert.Contains(t, g.Group, "genre.name")
+		assert.GreaterOrEqual(t, g.Aggregates["count"], float64(2))
+		assert.Contains(t, g.Aggregates, "avg(pages)")
+		assert.Contains(t, g.Aggregates, "max(year)")
+
+		if i > 0 {
+			assert.LessOrEqual(t, g.Aggregates["count"], rGroups[i-1].Aggregates["count"], "Groups should be sorted by count")
+		}
+	}
+}
+
+// GET /authors/:id/books?group_by=...
+func TestListAuthorBooks_Group(t *testing.T) {
+	var rGroups []models.Group
+	execAndCheck(t, "GET", "/api/v1/authors/5/books?group_by=year&agg=count,min(title)", nil, http.StatusOK, &rGroups)
+
+	assert.Equal(t, []models.Group{
+		{Group: map[string]any{"year": float64(1961)}, Aggregates: map[string]any{"count": float64(2), "min(title)": "Powrót z gwiazd"}},
+		{Group: map[string]any{"year": float64(1987)}, Aggregates: map[string]any{"count": float64(1), "min(title)": "Pokój na Ziemi"}},
+	}, rGroups)
+}
+
+// GET /authors?agg=...
+func TestListAuthors_Aggregate(t *testing.T) {
+	var rGroups []models.Group
+	execAndCheck(t, "GET", "/api/v1/authors?agg=count,min(birth_year)&birth_year.gt=1900", nil, http.StatusOK, &rGroups)
+
+	if assert.Len(t, rGroups, 1, "All of the records should form a single group") {
+		assert.Empty(t, rGroups[0].Group)
+		assert.Greater(t, rGroups[0].Aggregates["min(birth_year)"], float64(1900))
+	}
+}
+
+func TestListGroups_Error(t *testing.T) {
+	groupTests := map[string]ErrorTests{
+		"BadRequest_UnknownField": {
+			query:  "books?group_by=isbn",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_InvalidAggregate": {
+			query:  "genres?agg=avg(name)",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_HavingUnrequested": {
+			query:  "languages?group_by=name&having.max(id).gt=3",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Fields": {
+			query:  "authors?group_by=death_year&fields=death_year",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Expand": {
+			query:  "books?group_by=author&expand=author",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_Parent": {
+			query:  "genres/999/books?group_by=year",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "GET", "", groupTests)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/http"
@@ -10,18 +11,36 @@ import (
 	"pawrest/internal/models"
 )
 
+// Non-standard status code (popularized by nginx) used when the client
+// closed the connection before the response could be sent.
+const statusClientClosedRequest = 499
+
 type Handlers struct {
 	DB db.DatabaseInterface
+	// CursorKey signs the pagination cursors handed out to clients.
+	CursorKey []byte
 }
 
+// handleDBError responds with the problem details of err, listing the
+// violations it wraps.
 func handleDBError(c *gin.Context, err error) {
+	var v models.Violations
+	errors.As(err, &v)
+
 	switch {
+	case errors.Is(err, db.ErrParam):
+		problem(c, http.StatusBadRequest, err.Error(), v...)
 	case errors.Is(err, db.ErrNotFound):
-		c.JSON(http.StatusNotFound, models.Error{Error: err.Error()})
+		problem(c, http.StatusNotFound, err.Error())
 	case errors.Is(err, db.ErrForeignKey):
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+		problem(c, http.StatusBadRequest, err.Error(), v...)
+	case errors.Is(err, context.Canceled):
+		c.AbortWithStatus(statusClientClosedRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Println(err.Error())
+		problem(c, http.StatusGatewayTimeout, "The database did not respond in time")
 	default:
 		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		problem(c, http.StatusInternalServerError, "An Internal Server Error occurred")
 	}
 }
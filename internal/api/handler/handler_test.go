@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"io"
@@ -15,8 +16,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"pawrest/internal/api/handler"
+	"pawrest/internal/api/middleware"
 	"pawrest/internal/db"
-	"pawrest/internal/db/mock"
+	"pawrest/internal/db/memory"
 	"pawrest/internal/models"
 	"pawrest/internal/testutil"
 	"pawrest/internal/yamlconfig"
@@ -34,7 +36,7 @@ func runMain(m *testing.M) (int, error) {
 	flag.Parse()
 
 	if testing.Short() {
-		database = mock.NewMockDatabase()
+		database = memory.NewSeeded()
 	} else {
 		cfg := &yamlconfig.Config{
 			DBUser: "user_test",
@@ -51,7 +53,7 @@ func runMain(m *testing.M) (int, error) {
 		}
 		defer database.(*db.Database).CloseDB()
 
-		if err := testutil.SetupDatabase(database.(*db.Database).Pool()); err != nil {
+		if err := testutil.SetupDatabase(database.(*db.Database)); err != nil {
 			return 0, err
 		}
 	}
@@ -81,7 +83,7 @@ func execAndCheck(t *testing.T, method, url string, body []byte, status int, o a
 }
 
 func execAndCheckError(t *testing.T, method, url string, body []byte, status int) {
-	var rError models.Error
+	var rError models.Problem
 	execAndCheck(t, method, url, body, status, &rError)
 }
 
@@ -90,9 +92,9 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
-	h := handler.Handlers{DB: db}
+	h := handler.Handlers{DB: db, CursorKey: handler.CursorKey(secret)}
 
-	apiv1 := router.Group("/api/v1")
+	apiv1 := router.Group("/api/v1", handler.Negotiate)
 	{
 		books := apiv1.Group("/books")
 		{
@@ -100,7 +102,9 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 			books.GET("/:id", h.GetBook)
 			books.OPTIONS("", h.OptionsBooks)
 			books.OPTIONS("/:id", h.OptionsBook)
+			books.OPTIONS("/import", h.OptionsBooksImport)
 			books.POST("", h.PostBook)
+			books.POST("/import", h.ImportBooks)
 			books.PUT("/:id", h.PutBook)
 			books.PATCH("/:id", h.PatchBook)
 			books.DELETE("/:id", h.DeleteBook)
@@ -112,6 +116,8 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 			authors.GET("/:id", h.GetAuthor)
 			authors.OPTIONS("", h.OptionsAuthors)
 			authors.OPTIONS("/:id", h.OptionsAuthor)
+			authors.GET("/:id/books", h.GetAuthorBooks)
+			authors.OPTIONS("/:id/books", h.OptionsAuthorBooks)
 			authors.POST("", h.PostAuthor)
 			authors.PUT("/:id", h.PutAuthor)
 			authors.PATCH("/:id", h.PatchAuthor)
@@ -124,8 +130,11 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 			genres.GET("/:id", h.GetGenre)
 			genres.OPTIONS("", h.OptionsGenres)
 			genres.OPTIONS("/:id", h.OptionsGenre)
+			genres.GET("/:id/books", h.GetGenreBooks)
+			genres.OPTIONS("/:id/books", h.OptionsGenreBooks)
 			genres.POST("", h.PostGenre)
 			genres.PUT("/:id", h.PutGenre)
+			genres.PATCH("/:id", h.PatchGenre)
 			genres.DELETE("/:id", h.DeleteGenre)
 		}
 
@@ -135,11 +144,57 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 			languages.GET("/:id", h.GetLanguage)
 			languages.OPTIONS("", h.OptionsLanguages)
 			languages.OPTIONS("/:id", h.OptionsLanguage)
+			languages.GET("/:id/books", h.GetLanguageBooks)
+			languages.OPTIONS("/:id/books", h.OptionsLanguageBooks)
 			languages.POST("", h.PostLanguage)
 			languages.PUT("/:id", h.PutLanguage)
+			languages.PATCH("/:id", h.PatchLanguage)
 			languages.DELETE("/:id", h.DeleteLanguage)
 		}
 
+		search := apiv1.Group("/search")
+		{
+			search.GET("", h.Search)
+			search.OPTIONS("", h.OptionsSearch)
+		}
+
+		batch := apiv1.Group("", middleware.LiteralColon("batch"))
+		{
+			batch.OPTIONS("/books:batch", h.OptionsBatch)
+			batch.POST("/books:batch", h.PostBooksBatch)
+			batch.PATCH("/books:batch", h.PatchBooksBatch)
+			batch.DELETE("/books:batch", h.DeleteBooksBatch)
+			batch.OPTIONS("/authors:batch", h.OptionsBatch)
+			batch.POST("/authors:batch", h.PostAuthorsBatch)
+			batch.PATCH("/authors:batch", h.PatchAuthorsBatch)
+			batch.DELETE("/authors:batch", h.DeleteAuthorsBatch)
+			batch.OPTIONS("/genres:batch", h.OptionsBatch)
+			batch.POST("/genres:batch", h.PostGenresBatch)
+			batch.PATCH("/genres:batch", h.PatchGenresBatch)
+			batch.DELETE("/genres:batch", h.DeleteGenresBatch)
+			batch.OPTIONS("/languages:batch", h.OptionsBatch)
+			batch.POST("/languages:batch", h.PostLanguagesBatch)
+			batch.PATCH("/languages:batch", h.PatchLanguagesBatch)
+			batch.DELETE("/languages:batch", h.DeleteLanguagesBatch)
+		}
+
+		stats := apiv1.Group("/stats")
+		{
+			stats.GET("", h.GetStats)
+			stats.GET("/genres", h.GetStatsGenres)
+			stats.GET("/languages", h.GetStatsLanguages)
+			stats.GET("/authors", h.GetStatsAuthors)
+			stats.GET("/decades", h.GetStatsDecades)
+			stats.GET("/pages", h.GetStatsPages)
+
+			stats.OPTIONS("", h.OptionsStats)
+			stats.OPTIONS("/genres", h.OptionsStats)
+			stats.OPTIONS("/languages", h.OptionsStats)
+			stats.OPTIONS("/authors", h.OptionsStats)
+			stats.OPTIONS("/decades", h.OptionsStats)
+			stats.OPTIONS("/pages", h.OptionsStats)
+		}
+
 		apiv1.POST("login", handler.ReturnToken(secret))
 	}
 
@@ -147,9 +202,13 @@ func setupTestRouter(db db.DatabaseInterface) *gin.Engine {
 }
 
 func execRequest(method, target string, body io.Reader) *httptest.ResponseRecorder {
+	return execRequestWithContext(context.Background(), method, target, body)
+}
+
+func execRequestWithContext(ctx context.Context, method, target string, body io.Reader) *httptest.ResponseRecorder {
 	router := setupTestRouter(database)
 	w := httptest.NewRecorder()
-	req := httptest.NewRequest(method, target, body)
+	req := httptest.NewRequestWithContext(ctx, method, target, body)
 
 	router.ServeHTTP(w, req)
 	return w
@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"errors"
-	"log"
 	"net/http"
 	"strconv"
 
@@ -12,109 +10,197 @@ import (
 )
 
 // @Summary		Get a list of all languages
-// @Description	Responds with a list of all languages as JSON. Optional filtering, sorting and pagination is available through parameters.
+// @Description	Responds with a list of all languages as JSON. Optional filtering (including .in, .between, text matching and or groups), sorting and pagination is available through parameters. With group_by or agg the records are aggregated into groups (models.Group) instead.
 // @Tags			Languages
-// @Produce		json
-// @Param			id		query		string			false	"Language id"
-// @Param			name	query		string			false	"Language name"
-// @Param			sort_by	query		string			false	"Sorting by a column"
-// @Param			limit	query		int				false	"Limit returned number of resources"
-// @Param			offset	query		int				false	"Offset returned resources"
-// @Success		200		{array}		models.Language	"OK - Fetched languages"
-// @Failure		400		{object}	models.Error	"Bad Request - Invalid input"
-// @Failure		401		{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		500		{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				query		string			false	"Language id"
+// @Param			name			query		string			false	"Language name"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			or				query		string			false	"Conditions of which any must match, e.g. (name:Polski,name:Angielski)"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. name"
+// @Param			expand			query		string			false	"Relations to embed: books"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the records by, e.g. name; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Success		200				{array}		models.Language	"OK - Fetched languages"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
 // @Router			/languages [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetLanguages(c *gin.Context) {
-	params := c.Request.URL.Query()
+	params, ok := h.listParams(c)
+	if !ok {
+		return
+	}
 
-	languages, err := h.DB.GetLanguages(params)
-	if errors.Is(err, db.ErrParam) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	expand, ok := expandParam(c, h.languageRelations())
+	if !ok {
 		return
 	}
 
+	format, ok := listFormat(c, params, expand)
+	if !ok {
+		return
+	}
+
+	if db.IsGrouping(params) {
+		h.listGroups(c, "languages", params, expand)
+		return
+	}
+
+	switch format {
+	case formatCSV:
+		respondCSV(c, "languages", params, h.DB.GetLanguages)
+		return
+	case formatNDJSON:
+		respondNDJSON(c, h.DB.StreamLanguages(c.Request.Context(), params))
+		return
+	}
+
+	selectRefs(params, expand)
+
+	languages, err := h.DB.GetLanguages(c.Request.Context(), params)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
+		return
+	}
+
+	total, err := h.DB.CountLanguages(c.Request.Context(), params)
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, languages)
+	respondList(c, languages, total, h.CursorKey, expand...)
 }
 
 // @Summary		Get one language
 // @Description	Responds with the queried language as JSON or an error message.
 // @Tags			Languages
-// @Produce		json
-// @Param			id	path		int				true	"Language id"
-// @Success		200	{object}	models.Language	"OK - Fetched language"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid language id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		404	{object}	models.Error	"Not Found - No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			id		path		int				true	"Language id"
+// @Param			fields	query		string			false	"Comma-separated fields to return, e.g. name"
+// @Param			expand	query		string			false	"Relations to embed: books"
+// @Success		200		{object}	models.Language	"OK - Fetched language"
+// @Failure		400		{object}	models.Problem	"Bad Request - Invalid language id"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404		{object}	models.Problem	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
 // @Router			/languages/{id} [get]
 // @Security		ApiKeyAuth
 func (h *Handlers) GetLanguage(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	expand, ok := expandParam(c, h.languageRelations())
+	if !ok {
+		return
+	}
+
+	if _, ok := c.GetQuery("fields"); ok {
+		respondFields(c, int64(id), h.DB.GetLanguages, expand...)
 		return
 	}
 
-	language, err := h.DB.GetLanguage(int64(id))
-	if errors.Is(err, db.ErrNotFound) {
-		c.JSON(http.StatusNotFound, models.Error{Error: err.Error()})
+	language, err := h.DB.GetLanguage(c.Request.Context(), int64(id))
+	if err != nil {
+		handleDBError(c, err)
 		return
 	}
 
+	respondRecord(c, language, expand)
+}
+
+// @Summary		Get a list of books of one language
+// @Description	Responds with a list of the books of the language as JSON or an error message if the language doesn't exist. Filtering, sorting and pagination work like on /books. With group_by or agg the records are aggregated into groups (models.Group) instead.
+// @Tags			Languages
+// @Produce		json,xml,application/yaml,application/msgpack,text/csv,application/x-ndjson
+// @Param			id				path		int				true	"Language id"
+// @Param			title			query		string			false	"Book title"
+// @Param			year			query		int				false	"Year of publishing of the book"
+// @Param			pages			query		int				false	"Number of pages in the book"
+// @Param			author			query		int				false	"Author id"
+// @Param			genre			query		int				false	"Genre id"
+// @Param			sort_by			query		string			false	"Sorting by comma-separated columns, prefixed with - for descending order and suffixed with .nullsfirst or .nullslast"
+// @Param			limit			query		int				false	"Limit returned number of resources"
+// @Param			offset			query		int				false	"Offset returned resources"
+// @Param			after			query		string			false	"Cursor from X-Next-Cursor continuing the pagination after the previous page, used instead of offset"
+// @Param			envelope		query		bool			false	"Wrap the results in an object with the pagination metadata"
+// @Param			format			query		string			false	"Response format: json (default) or csv, streamed with a header row"
+// @Param			fields			query		string			false	"Comma-separated fields to return, e.g. id,title,year"
+// @Param			expand			query		string			false	"Comma-separated relations to embed: author, genre, language"
+// @Param			group_by		query		string			false	"Comma-separated fields to group the books by, e.g. genre.name,language; responds with groups instead"
+// @Param			agg				query		string			false	"Comma-separated aggregates of the groups: count, sum(field), avg(field), min(field), max(field); count by default"
+// @Param			having.count.gt	query		int				false	"Filter on the aggregates of the groups, written having.<aggregate>.<operator>, e.g. having.count.gte"
+// @Param			extend			query		bool			false	"Return extended book information"
+// @Success		200				{array}		models.Book		"OK - Fetched books"
+// @Header			200				{integer}	X-Total-Count	"Number of all matching resources"
+// @Header			200				{string}	X-Next-Cursor	"Cursor to the next page if the page is full"
+// @Header			200				{string}	Link			"Links to the first, previous, next and last pages if limit is set"
+// @Failure		400				{object}	models.Problem	"Bad Request - Invalid language id or input"
+// @Failure		401				{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		404				{object}	models.Problem	"Not Found - No language found"
+// @Failure		500				{object}	models.Problem	"Internal Server Error"
+// @Router			/languages/{id}/books [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetLanguageBooks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
+		return
+	}
+
+	if _, err := h.DB.GetLanguage(c.Request.Context(), int64(id)); err != nil {
+		handleDBError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, language)
+	h.listBooks(c, "language", int64(id))
 }
 
 // @Summary		Create a new language
 // @Description	Accepts a JSON body to create a new language. Responds with the created language and set `Location` header or an error message.
 // @Tags			Languages
-// @Accept			json
-// @Produce		json
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
 // @Param			language	body		models.Language	true	"New Language"
 // @Success		201			{object}	models.Language	"Created - Added new language"
-// @Failure		400			{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401			{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403			{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		500			{object}	models.Error	"Internal Server Error"
+// @Failure		400			{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401			{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403			{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		500			{object}	models.Problem	"Internal Server Error"
 // @Header			201			{string}	Location		"Path of the newly created language"
 // @Router			/languages [post]
 // @Security		ApiKeyAuth
 func (h *Handlers) PostLanguage(c *gin.Context) {
 	var newLanguage models.Language
 
-	if err := c.BindJSON(&newLanguage); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newLanguage) {
 		return
 	}
 
-	if newLanguage.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
-		return
-	}
-
-	id, err := h.DB.InsertLanguage(newLanguage)
-	if errors.Is(err, db.ErrForeignKey) {
-		c.JSON(http.StatusBadRequest, models.Error{Error: err.Error()})
+	if v := newLanguage.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
 		return
 	}
 
+	id, err := h.DB.InsertLanguage(c.Request.Context(), newLanguage)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.Error{Error: "An Internal Server Error occurred"})
+		handleDBError(c, err)
 		return
 	}
 
@@ -123,44 +209,97 @@ func (h *Handlers) PostLanguage(c *gin.Context) {
 	location := c.FullPath() + "/" + strconv.FormatInt(newLanguage.ID, 10)
 	c.Header("Location", location)
 
-	c.JSON(http.StatusCreated, newLanguage)
+	respond(c, http.StatusCreated, newLanguage)
 }
 
 // @Summary		Update an existing language
 // @Description	Accepts a JSON body to update a language. Responds with a status code. When an error occurs the response body contains JSON data with the message.
 // @Tags			Languages
-// @Accept			json
+// @Accept			json,xml,application/yaml,application/msgpack
 // @Param			id			path	int				true	"Existing Language id"
 // @Param			language	body	models.Language	true	"Updated Language"
 // @Success		204			"No content - Updated the language"
-// @Failure		400			{object}	models.Error	"Bad Request - Invalid input or JSON"
-// @Failure		401			{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403			{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404			{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500			{object}	models.Error	"Internal Server Error"
+// @Failure		400			{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401			{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403			{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404			{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500			{object}	models.Problem	"Internal Server Error"
 // @Router			/languages/{id} [put]
 // @Security		ApiKeyAuth
 func (h *Handlers) PutLanguage(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
 	var newLanguage models.Language
 
-	if err := c.BindJSON(&newLanguage); err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Invalid JSON in request body"})
+	if !bindBody(c, &newLanguage) {
+		return
+	}
+
+	if v := newLanguage.Validate(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more required fields are missing or invalid", v...)
+		return
+	}
+
+	if err := h.DB.UpdateWholeLanguage(c.Request.Context(), int64(id), newLanguage); err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Patch an existing language
+// @Description	Accepts a JSON merge patch (RFC 7396), which plain JSON is applied as, a JSON patch (RFC 6902) or a partial language, which only updates the fields it sets, in XML, YAML or MessagePack. Responds with a status code. When an error occurs the response body contains JSON data with the message.
+// @Tags			Languages
+// @Accept			json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
+// @Param			id			path	int				true	"Existing Language id"
+// @Param			language	body	models.Language	true	"Patches to the language"
+// @Success		204			"No Content - Successfully patched the language"
+// @Failure		400			{object}	models.Problem	"Bad Request - Invalid input or JSON"
+// @Failure		401			{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403			{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404			{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		409			{object}	models.Problem	"Conflict - A test operation of the JSON patch failed"
+// @Failure		415			{object}	models.Problem	"Unsupported Media Type - The body is in an unsupported format"
+// @Failure		422			{object}	models.Problem	"Unprocessable Entity - An operation of the JSON patch can't be applied"
+// @Failure		500			{object}	models.Problem	"Internal Server Error"
+// @Router			/languages/{id} [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchLanguage(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if newLanguage.IsNotValid() {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "One or more required fields are missing or invalid"})
+	if isPatchDocument(c) {
+		patchRecord[models.Language](c, h.DB, int64(id), db.DatabaseInterface.GetLanguage, db.DatabaseInterface.UpdateWholeLanguage)
 		return
 	}
 
-	if err := h.DB.UpdateWholeLanguage(int64(id), newLanguage); err != nil {
+	var patchLanguage models.Language
+
+	if !bindBody(c, &patchLanguage) {
+		return
+	}
+
+	if patchLanguage == (models.Language{ID: patchLanguage.ID}) {
+		problem(c, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	if v := patchLanguage.ValidatePatch(); v != nil {
+		problem(c, http.StatusBadRequest, "One or more fields are invalid", v...)
+		return
+	}
+
+	if err := h.DB.UpdateWholeLanguage(c.Request.Context(), int64(id), patchLanguage); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -173,22 +312,22 @@ func (h *Handlers) PutLanguage(c *gin.Context) {
 // @Tags			Languages
 // @Param			id	path	int	true	"Language id"
 // @Success		204	"No Content - Successfully deleted the language"
-// @Failure		400	{object}	models.Error	"Bad Request - Invalid language id"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
-// @Failure		404	{object}	models.Error	"Not Found -  No resource found"
-// @Failure		500	{object}	models.Error	"Internal Server Error"
+// @Failure		400	{object}	models.Problem	"Bad Request - Invalid language id"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404	{object}	models.Problem	"Not Found -  No resource found"
+// @Failure		500	{object}	models.Problem	"Internal Server Error"
 // @Router			/languages/{id} [delete]
 // @Security		ApiKeyAuth
 func (h *Handlers) DeleteLanguage(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.Error{Error: "Provided incorrect identifier"})
+		problem(c, http.StatusBadRequest, "Provided incorrect identifier")
 		return
 	}
 
-	if err := h.DB.DelLanguage(int64(id)); err != nil {
+	if err := h.DB.DelLanguage(c.Request.Context(), int64(id)); err != nil {
 		handleDBError(c, err)
 		return
 	}
@@ -200,8 +339,8 @@ func (h *Handlers) DeleteLanguage(c *gin.Context) {
 // @Description	Responds with an empty response body.
 // @Tags			Languages
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
 // @Router			/languages [options]
 // @Security		ApiKeyAuth
@@ -215,12 +354,104 @@ func (h *Handlers) OptionsLanguages(c *gin.Context) {
 // @Tags			Languages
 // @Param			id	path	string	true	"Language id"
 // @Success		204	"No Content - Successfully responded with available options"
-// @Failure		401	{object}	models.Error	"Unauthorized - Invalid or missing token"
-// @Failure		403	{object}	models.Error	"Forbidden - Insufficient permissions"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403	{object}	models.Problem	"Forbidden - Insufficient permissions"
 // @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Header			204	{string}	Accept-Patch	"Formats of the bodies of PATCH requests"
 // @Router			/languages/{id} [options]
 // @Security		ApiKeyAuth
 func (h *Handlers) OptionsLanguage(c *gin.Context) {
-	c.Header("Allow", "GET, PUT, DELETE, OPTIONS")
+	c.Header("Allow", "GET, PUT, PATCH, DELETE, OPTIONS")
+	c.Header("Accept-Patch", acceptPatch)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary		Return allowed operations for books of language
+// @Description	Responds with an empty response body.
+// @Tags			Languages
+// @Param			id	path	string	true	"Language id"
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/languages/{id}/books [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsLanguageBooks(c *gin.Context) {
+	c.Header("Allow", "GET, OPTIONS")
 	c.Status(http.StatusNoContent)
 }
+
+// @Summary		Create languages in a batch
+// @Description	Accepts a JSON array of languages to create in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is created and the other items get 424. With atomic=false the valid languages are created and the response is 207 if any item failed.
+// @Tags			Languages
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			languages	body		[]models.Language	true	"New languages"
+// @Param			atomic		query		bool				false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		201			{object}	models.Batch		"Created - Added all of the languages"
+// @Success		207			{object}	models.Batch		"Multi-Status - Added some of the languages"
+// @Failure		400			{object}	models.Batch		"Bad Request - Invalid input or JSON"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		403			{object}	models.Problem		"Forbidden - Insufficient permissions"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/languages:batch [post]
+// @Security		ApiKeyAuth
+func (h *Handlers) PostLanguagesBatch(c *gin.Context) {
+	languages, b, ok := parseBatch(c, checkNew[models.Language])
+	if !ok {
+		return
+	}
+
+	postBatch(c, h.DB, b, languages, db.DatabaseInterface.InsertLanguages, db.DatabaseInterface.InsertLanguage)
+}
+
+// @Summary		Patch languages in a batch
+// @Description	Accepts a JSON array of patches to languages, identified by their id, to apply in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is applied and the other items get 424. With atomic=false the valid patches are applied and the response is 207 if any item failed.
+// @Tags			Languages
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			languages	body		[]models.Language	true	"Patches to the languages with their ids"
+// @Param			atomic		query		bool				false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200			{object}	models.Batch		"OK - Patched all of the languages"
+// @Success		207			{object}	models.Batch		"Multi-Status - Patched some of the languages"
+// @Failure		400			{object}	models.Batch		"Bad Request - Invalid input or JSON"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		403			{object}	models.Problem		"Forbidden - Insufficient permissions"
+// @Failure		404			{object}	models.Batch		"Not Found - No resource found"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/languages:batch [patch]
+// @Security		ApiKeyAuth
+func (h *Handlers) PatchLanguagesBatch(c *gin.Context) {
+	languages, b, ok := parseBatch(c, func(l *models.Language) (string, models.Violations) {
+		return checkPatch(l.ID, *l == models.Language{ID: l.ID}, l.ValidatePatch())
+	})
+	if !ok {
+		return
+	}
+
+	patchBatch(c, h.DB, b, languages, func(l *models.Language) int64 { return l.ID }, db.DatabaseInterface.UpdateWholeLanguage)
+}
+
+// @Summary		Delete languages in a batch
+// @Description	Accepts a JSON array of ids of languages to delete in a single transaction. Responds with the result of each item. By default the batch is all-or-nothing: if any item fails, none is deleted and the other items get 424. With atomic=false the languages which can be deleted are and the response is 207 if any item failed.
+// @Tags			Languages
+// @Accept			json,xml,application/yaml,application/msgpack
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			ids		body		[]int			true	"Ids of the languages"
+// @Param			atomic	query		bool			false	"Process the items all-or-nothing (default) or best-effort when false"
+// @Success		200		{object}	models.Batch	"OK - Deleted all of the languages"
+// @Success		207		{object}	models.Batch	"Multi-Status - Deleted some of the languages"
+// @Failure		400		{object}	models.Batch	"Bad Request - Invalid input or JSON"
+// @Failure		401		{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		403		{object}	models.Problem	"Forbidden - Insufficient permissions"
+// @Failure		404		{object}	models.Batch	"Not Found - No resource found"
+// @Failure		500		{object}	models.Problem	"Internal Server Error"
+// @Router			/languages:batch [delete]
+// @Security		ApiKeyAuth
+func (h *Handlers) DeleteLanguagesBatch(c *gin.Context) {
+	ids, b, ok := parseBatch(c, checkID)
+	if !ok {
+		return
+	}
+
+	deleteBatch(c, h.DB, b, ids, db.DatabaseInterface.DelLanguage)
+}
@@ -1,8 +1,10 @@
 package handler_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,6 +48,38 @@ func TestGetLanguage_Error(t *testing.T) {
 	runTestErrors(t, "GET", "languages", getTests)
 }
 
+// GET /languages/id/books
+func TestListLanguageBooks_Success(t *testing.T) {
+	var rBooks []models.Book
+	w := execAndCheck(t, "GET", "/api/v1/languages/2/books", nil, http.StatusOK, &rBooks)
+
+	assert.NotEmpty(t, rBooks)
+	assert.Equal(t, strconv.Itoa(len(rBooks)), w.Header().Get("X-Total-Count"))
+
+	for _, b := range rBooks {
+		assert.Equal(t, int64(2), b.Language, "Only the books of the language should be listed")
+	}
+}
+
+func TestListLanguageBooks_Error(t *testing.T) {
+	listTests := map[string]ErrorTests{
+		"NotFound_BigPathID": {
+			query:  "/9999/books",
+			status: http.StatusNotFound,
+		},
+		"BadRequest_StringPathID": {
+			query:  "/string/books",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_UnknownParam": {
+			query:  "/2/books?foo=bar",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "languages", listTests)
+}
+
 // POST /languages
 func TestPostLanguage_Success(t *testing.T) {
 	testLanguage := models.Language{
@@ -55,7 +89,7 @@ func TestPostLanguage_Success(t *testing.T) {
 	var rLanguage models.Language
 	jsonLanguage := marshalCheckNoError(t, testLanguage)
 	w := execAndCheck(t, "POST", "/api/v1/languages", jsonLanguage, http.StatusCreated, &rLanguage)
-	defer database.DelLanguage(rLanguage.ID)
+	defer database.DelLanguage(context.Background(), rLanguage.ID)
 
 	expLoc := fmt.Sprintf("/api/v1/languages/%v", rLanguage.ID)
 	assert.Equal(t, expLoc, w.Result().Header.Get("Location"))
@@ -92,7 +126,7 @@ func TestPutLanguage_Success(t *testing.T) {
 	jsonLanguage := marshalCheckNoError(t, testLanguage)
 	execAndCheck(t, "PUT", "/api/v1/languages/1", jsonLanguage, http.StatusNoContent, nil)
 
-	language, _ := database.GetLanguage(1)
+	language, _ := database.GetLanguage(context.Background(), 1)
 	assert.Equal(t, testLanguage.Name, language.Name)
 }
 
@@ -129,7 +163,7 @@ func TestPutLanguage_Error(t *testing.T) {
 
 // DELETE /languages/id
 func TestDeleteLanguage_Success(t *testing.T) {
-	newID, err := database.InsertLanguage(models.Language{
+	newID, err := database.InsertLanguage(context.Background(), models.Language{
 		Name: "Delete tester",
 	})
 	assert.NoError(t, err)
@@ -137,7 +171,7 @@ func TestDeleteLanguage_Success(t *testing.T) {
 	newLanguageLoc := fmt.Sprintf("/api/v1/languages/%v", newID)
 	execAndCheck(t, "DELETE", newLanguageLoc, nil, http.StatusNoContent, nil)
 
-	_, err = database.GetLanguage(newID)
+	_, err = database.GetLanguage(context.Background(), newID)
 	assert.ErrorIs(t, err, db.ErrNotFound)
 }
 
@@ -160,6 +194,7 @@ func TestDeleteLanguage_Error(t *testing.T) {
 
 // OPTIONS /languages
 // OPTIONS /languages/id
+// OPTIONS /languages/id/books
 func TestOptionsLanguages_Success(t *testing.T) {
 	optionsTests := map[string]struct {
 		query   string
@@ -171,7 +206,11 @@ func TestOptionsLanguages_Success(t *testing.T) {
 		},
 		"PathID": {
 			"/1",
-			[]string{"GET", "PUT", "DELETE", "OPTIONS"},
+			[]string{"GET", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		},
+		"Books": {
+			"/1/books",
+			[]string{"GET", "OPTIONS"},
 		},
 	}
 
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// listParams returns the query parameters of a list request with the signed
+// cursor in the after parameter replaced by the encoded db.Cursor it carries.
+// It responds with 400 and returns false when the signature doesn't match.
+func (h *Handlers) listParams(c *gin.Context) (url.Values, bool) {
+	params := c.Request.URL.Query()
+
+	if token, ok := params["after"]; ok {
+		cursor, valid := verifyCursor(h.CursorKey, token[0])
+		if !valid {
+			problem(c, http.StatusBadRequest, "Provided invalid cursor")
+			return nil, false
+		}
+
+		params.Set("after", cursor)
+	}
+
+	return params, true
+}
+
+// respondList writes the records of a list request along with the number of
+// all records matching its filters in the X-Total-Count header. Full pages
+// of a limited request come with a signed cursor to the next page in the
+// X-Next-Cursor header, and a Link header (RFC 8288) pointing at the first,
+// previous, next and last pages. Keyset pagination (after=<cursor>) only
+// links the first and next pages. With envelope=true the records are
+// wrapped in a models.Page instead of being returned as a bare array.
+// The records are narrowed down to the fields parameter if it's set,
+// and the relations in expand are embedded in them.
+func respondList[T any](c *gin.Context, records []T, total int64, cursorKey []byte, expand ...relation) {
+	query := c.Request.URL.Query()
+
+	data, err := present(c, records, expand)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	var limit *int64
+
+	if l := query.Get("limit"); l != "" {
+		n, _ := strconv.ParseInt(l, 10, 64)
+		limit = &n
+	}
+
+	offset, _ := strconv.ParseInt(query.Get("offset"), 10, 64)
+
+	next := ""
+
+	if limit != nil && *limit > 0 && int64(len(records)) == *limit {
+		if cursor, ok := nextCursor(records[len(records)-1], query.Get("sort_by")); ok {
+			next = signCursor(cursorKey, cursor.Encode())
+			c.Header("X-Next-Cursor", next)
+		}
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	if limit != nil && *limit > 0 {
+		if query.Has("after") {
+			c.Header("Link", cursorLinks(c, next))
+		} else {
+			c.Header("Link", pageLinks(c, *limit, offset, total))
+		}
+	}
+
+	if c.Query("envelope") == "true" {
+		respond(c, http.StatusOK, models.Page{Data: data, Total: total, Limit: limit, Offset: offset, NextCursor: next})
+		return
+	}
+
+	respond(c, http.StatusOK, data)
+}
+
+// pageLinks builds the value of the Link header of a page of limit
+// records starting at offset, out of total records.
+func pageLinks(c *gin.Context, limit, offset, total int64) string {
+	link := func(offset int64, rel string) string {
+		query := c.Request.URL.Query()
+		query.Set("offset", strconv.FormatInt(offset, 10))
+
+		return "<" + c.Request.URL.Path + "?" + query.Encode() + `>; rel="` + rel + `"`
+	}
+
+	last := int64(0)
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+
+	links := []string{link(0, "first")}
+
+	if offset > 0 {
+		links = append(links, link(max(offset-limit, 0), "prev"))
+	}
+
+	if offset+limit < total {
+		links = append(links, link(offset+limit, "next"))
+	}
+
+	links = append(links, link(last, "last"))
+
+	return strings.Join(links, ", ")
+}
+
+// cursorLinks builds the value of the Link header of a page requested
+// with a cursor. next is empty on the last page.
+func cursorLinks(c *gin.Context, next string) string {
+	link := func(after, rel string) string {
+		query := c.Request.URL.Query()
+		query.Del("after")
+
+		if after != "" {
+			query.Set("after", after)
+		}
+
+		return "<" + c.Request.URL.Path + "?" + query.Encode() + `>; rel="` + rel + `"`
+	}
+
+	links := []string{link("", "first")}
+
+	if next != "" {
+		links = append(links, link(next, "next"))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// nextCursor returns the cursor pointing after the record last,
+// looking the sorted fields up in its JSON form.
+func nextCursor(last any, sortBy string) (db.Cursor, bool) {
+	record, err := jsonObject(last)
+	if err != nil {
+		return db.Cursor{}, false
+	}
+
+	id, ok := record["id"].(float64)
+	if !ok {
+		return db.Cursor{}, false
+	}
+
+	cursor := db.Cursor{ID: int64(id)}
+
+	if sortBy == "" {
+		return cursor, true
+	}
+
+	keys, err := db.ParseSort(sortBy)
+	if err != nil {
+		return db.Cursor{}, false
+	}
+
+	cursor.Sort = db.FormatSort(keys)
+
+	for _, k := range keys {
+		value, ok := lookupField(record, k.Param)
+		if !ok {
+			return db.Cursor{}, false
+		}
+
+		switch v := value.(type) {
+		case nil:
+			cursor.Values = append(cursor.Values, nil)
+		case float64:
+			s := strconv.FormatFloat(v, 'f', -1, 64)
+			cursor.Values = append(cursor.Values, &s)
+		case string:
+			cursor.Values = append(cursor.Values, &v)
+		default:
+			return db.Cursor{}, false
+		}
+	}
+
+	return cursor, true
+}
+
+// CursorKey derives the key signing the pagination cursors from the secret
+// signing the JWTs, so that the secret itself never signs values chosen
+// by the clients.
+func CursorKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("cursor"))
+
+	return mac.Sum(nil)
+}
+
+// signCursor appends an HMAC of the encoded cursor to it,
+// so that clients can't forge or modify cursors.
+func signCursor(key []byte, cursor string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(cursor))
+
+	return cursor + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyCursor checks the signature of token and returns the encoded cursor.
+func verifyCursor(key []byte, token string) (string, bool) {
+	cursor, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", false
+	}
+
+	return cursor, hmac.Equal([]byte(signCursor(key, cursor)), []byte(token))
+}
@@ -0,0 +1,165 @@
+package handler_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+func TestList_TotalCount(t *testing.T) {
+	var rBooks []models.Book
+	w := execAndCheck(t, "GET", "/api/v1/books?author=5&limit=1", nil, http.StatusOK, &rBooks)
+
+	assert.Len(t, rBooks, 1, "The limit should be applied")
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"), "The total count should ignore the limit")
+}
+
+func TestList_Link(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		wantLink string
+	}{
+		"FirstPage": {
+			query: "?limit=5",
+			wantLink: `</api/v1/genres?limit=5&offset=0>; rel="first", ` +
+				`</api/v1/genres?limit=5&offset=5>; rel="next", ` +
+				`</api/v1/genres?limit=5&offset=5>; rel="last"`,
+		},
+		"MiddlePage": {
+			query: "?limit=3&offset=4&sort_by=name",
+			wantLink: `</api/v1/genres?limit=3&offset=0&sort_by=name>; rel="first", ` +
+				`</api/v1/genres?limit=3&offset=1&sort_by=name>; rel="prev", ` +
+				`</api/v1/genres?limit=3&offset=7&sort_by=name>; rel="next", ` +
+				`</api/v1/genres?limit=3&offset=6&sort_by=name>; rel="last"`,
+		},
+		"NoResults": {
+			query:    "?name=Brak&limit=2",
+			wantLink: `</api/v1/genres?limit=2&name=Brak&offset=0>; rel="first", </api/v1/genres?limit=2&name=Brak&offset=0>; rel="last"`,
+		},
+		"NoLimit": {
+			query:    "",
+			wantLink: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := execRequest("GET", "/api/v1/genres"+tt.query, nil)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantLink, w.Header().Get("Link"))
+		})
+	}
+}
+
+func TestList_Envelope(t *testing.T) {
+	w := execAndCheck(t, "GET", "/api/v1/languages?envelope=true&limit=2&offset=1", nil, http.StatusOK, nil)
+
+	var rPage struct {
+		Data   []models.Language `json:"data"`
+		Total  int64             `json:"total"`
+		Limit  *int64            `json:"limit"`
+		Offset int64             `json:"offset"`
+	}
+	err := json.NewDecoder(w.Body).Decode(&rPage)
+	assert.NoError(t, err)
+
+	assert.Len(t, rPage.Data, 2)
+	assert.Equal(t, int64(11), rPage.Total)
+	if assert.NotNil(t, rPage.Limit) {
+		assert.Equal(t, int64(2), *rPage.Limit)
+	}
+	assert.Equal(t, int64(1), rPage.Offset)
+	assert.Equal(t, "Polski", rPage.Data[0].Name)
+}
+
+func TestList_EnvelopeNoLimit(t *testing.T) {
+	var rPage models.Page
+	execAndCheck(t, "GET", "/api/v1/authors?envelope=true", nil, http.StatusOK, &rPage)
+
+	assert.Equal(t, int64(9), rPage.Total)
+	assert.Nil(t, rPage.Limit, "The limit should be null")
+}
+
+func TestList_Cursor(t *testing.T) {
+	var (
+		ids   []int64
+		total string
+		next  = "/api/v1/books?extend=true&sort_by=-author.last_name,year&limit=5"
+	)
+
+	for next != "" {
+		var rBooks []models.BookExt
+		w := execAndCheck(t, "GET", next, nil, http.StatusOK, &rBooks)
+		total = w.Header().Get("X-Total-Count")
+
+		for _, b := range rBooks {
+			ids = append(ids, b.ID)
+		}
+
+		next = ""
+		if cursor := w.Header().Get("X-Next-Cursor"); cursor != "" {
+			assert.Contains(t, w.Header().Get("Link"), `rel="first"`)
+			next = "/api/v1/books?extend=true&sort_by=-author.last_name,year&limit=5&after=" + url.QueryEscape(cursor)
+		}
+	}
+
+	assert.Equal(t, total, strconv.Itoa(len(ids)), "Every book should be listed")
+	assert.Len(t, slices.Compact(slices.Sorted(slices.Values(ids))), len(ids), "No book should be listed twice")
+}
+
+func TestList_CursorEnvelope(t *testing.T) {
+	var rPage models.Page
+	execAndCheck(t, "GET", "/api/v1/authors?envelope=true&limit=2", nil, http.StatusOK, &rPage)
+	assert.NotEmpty(t, rPage.NextCursor, "A full page should have a cursor")
+
+	w := execAndCheck(t, "GET", "/api/v1/authors?limit=2&after="+url.QueryEscape(rPage.NextCursor), nil, http.StatusOK, nil)
+	assert.Contains(t, w.Header().Get("Link"), `rel="next"`)
+	assert.NotContains(t, w.Header().Get("Link"), `rel="last"`)
+}
+
+func TestList_CursorError(t *testing.T) {
+	var rPage models.Page
+	execAndCheck(t, "GET", "/api/v1/genres?envelope=true&limit=2&sort_by=name", nil, http.StatusOK, &rPage)
+
+	// The cursor signed with the JWT secret of the test router instead of the derived key.
+	cursor, _, _ := strings.Cut(rPage.NextCursor, ".")
+	mac := hmac.New(sha256.New, []byte("random-string"))
+	mac.Write([]byte(cursor))
+	signedWithSecret := cursor + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	cursorTests := map[string]ErrorTests{
+		"BadRequest_Tampered": {
+			query:  "?limit=2&sort_by=name&after=" + url.QueryEscape(rPage.NextCursor+"x"),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Unsigned": {
+			query:  "?limit=2&sort_by=name&after=eyJpIjoxfQ",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_SignedWithSecret": {
+			query:  "?limit=2&sort_by=name&after=" + url.QueryEscape(signedWithSecret),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_DifferentSort": {
+			query:  "?limit=2&sort_by=-name&after=" + url.QueryEscape(rPage.NextCursor),
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_WithOffset": {
+			query:  "?limit=2&offset=2&sort_by=name&after=" + url.QueryEscape(rPage.NextCursor),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "genres", cursorTests)
+}
@@ -0,0 +1,501 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+const (
+	// mimeMergePatch is the media type of JSON merge patches (RFC 7396).
+	mimeMergePatch = "application/merge-patch+json"
+	// mimeJSONPatch is the media type of JSON patches (RFC 6902).
+	mimeJSONPatch = "application/json-patch+json"
+)
+
+// acceptPatch lists the formats of the bodies of PATCH requests for the
+// Accept-Patch header, the patch documents (plain JSON is applied as a merge
+// patch) followed by the partial records.
+var acceptPatch = strings.Join([]string{
+	mimeMergePatch,
+	mimeJSONPatch,
+	"application/json",
+	"application/xml",
+	"application/yaml",
+	"application/msgpack",
+}, ", ")
+
+// isPatchDocument reports whether the request body is a patch document: a
+// merge patch, a JSON patch or plain JSON, which is applied as a merge patch
+// so that its nulls and zero values are set as well. The bodies in the other
+// formats are partial records, which only update the non-zero fields they set.
+func isPatchDocument(c *gin.Context) bool {
+	switch c.ContentType() {
+	case mimeMergePatch, mimeJSONPatch, "application/json", "":
+		return true
+	}
+
+	return false
+}
+
+// patchError is an error of a patch document, which is responded with status.
+type patchError struct {
+	status int
+	detail string
+}
+
+func (e *patchError) Error() string {
+	return e.detail
+}
+
+func invalidPatch(format string, args ...any) error {
+	return &patchError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func unprocessablePatch(format string, args ...any) error {
+	return &patchError{http.StatusUnprocessableEntity, fmt.Sprintf(format, args...)}
+}
+
+// isEmptyObject reports whether body is a JSON object without members.
+func isEmptyObject(body []byte) bool {
+	var members map[string]json.RawMessage
+	return json.Unmarshal(body, &members) == nil && members != nil && len(members) == 0
+}
+
+// patchRecord applies the patch document in the request body to the record with
+// id, and replaces it with the result when it's valid. The record is read and
+// replaced in a single transaction, which locks it in between, so that the test
+// operations of JSON patches check the record which is replaced and concurrent
+// patches don't overwrite each other. It responds with 204 on success, 400 when
+// the patch or the result is invalid or a merge patch sets no fields, 409 when
+// a test operation fails and 422 when an operation can't be applied.
+func patchRecord[T any, P interface {
+	*T
+	Validate() models.Violations
+}](c *gin.Context, d db.DatabaseInterface, id int64,
+	get func(tx db.DatabaseInterface, ctx context.Context, id int64) (T, error),
+	update func(tx db.DatabaseInterface, ctx context.Context, id int64, record T) error,
+) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "Invalid patch in request body")
+		return
+	}
+
+	apply := applyMergePatch
+	if c.ContentType() == mimeJSONPatch {
+		apply = applyJSONPatch
+	} else if isEmptyObject(body) {
+		problem(c, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	var v models.Violations
+
+	ctx := c.Request.Context()
+	err = d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		current, err := get(tx, ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var record T
+		if err := patchJSON(current, &record, body, apply); err != nil {
+			return err
+		}
+
+		p := P(&record)
+
+		recordID := reflect.ValueOf(p).Elem().FieldByName("ID")
+		if recordID.Int() != 0 && recordID.Int() != id {
+			return invalidPatch("The id of the record can't be changed")
+		}
+
+		recordID.SetInt(id)
+
+		if v = p.Validate(); v != nil {
+			return v
+		}
+
+		return update(tx, ctx, id, record)
+	})
+
+	var pe *patchError
+
+	switch {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.As(err, &pe):
+		problem(c, pe.status, pe.detail)
+	case v != nil:
+		problem(c, http.StatusBadRequest, "One or more fields are invalid", v...)
+	default:
+		handleDBError(c, err)
+	}
+}
+
+// patchJSON applies the patch to current, encoded as JSON,
+// and decodes the result into record.
+func patchJSON(current, record any, patch []byte, apply func(doc any, patch []byte) (any, error)) error {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	doc, err = apply(doc, patch)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(record); err != nil {
+		return invalidPatch("The patched record is invalid (%v)", err)
+	}
+
+	return nil
+}
+
+// applyMergePatch applies a JSON merge patch to doc.
+func applyMergePatch(doc any, patch []byte) (any, error) {
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, invalidPatch("Invalid merge patch in request body")
+	}
+
+	return mergePatch(doc, p), nil
+}
+
+// mergePatch returns target merged with patch as in RFC 7396: the members
+// of a patch object replace the ones of the target, recursively for objects,
+// and null members remove them. Patches other than objects replace the target.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+
+		t[name] = mergePatch(t[name], value)
+	}
+
+	return t
+}
+
+// patchOp is an operation of a JSON patch. A missing value is nil,
+// while a null value is the JSON null.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies the operations of a JSON patch to doc in order,
+// as in RFC 6902. The patch fails as a whole when any of them fails.
+func applyJSONPatch(doc any, patch []byte) (any, error) {
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, invalidPatch("Invalid JSON patch in request body, expected an array of operations")
+	}
+
+	for i, op := range ops {
+		var err error
+
+		doc, err = applyOp(doc, op)
+		if err != nil {
+			var pe *patchError
+			if errors.As(err, &pe) {
+				pe.detail = fmt.Sprintf("Operation %d: %s", i, pe.detail)
+			}
+
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func applyOp(doc any, op patchOp) (any, error) {
+	if op.Path == nil {
+		return nil, invalidPatch("%q is missing the path", op.Op)
+	}
+
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, invalidPatch("%q is missing the value", op.Op)
+		}
+
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, invalidPatch("%q has an invalid value", op.Op)
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, invalidPatch("%q is missing from", op.Op)
+		}
+
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" && len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+			return nil, unprocessablePatch("%q can't move %q into itself", op.Op, *op.From)
+		}
+
+		if value, err = getPointer(doc, from); err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if doc, err = removePointer(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+	case "remove":
+	default:
+		return nil, invalidPatch("Unknown operation %q", op.Op)
+	}
+
+	switch op.Op {
+	case "remove":
+		return removePointer(doc, path)
+	case "replace":
+		if _, err := getPointer(doc, path); err != nil {
+			return nil, err
+		}
+
+		if len(path) > 0 {
+			if doc, err = removePointer(doc, path); err != nil {
+				return nil, err
+			}
+		}
+
+		return addPointer(doc, path, value)
+	case "test":
+		current, err := getPointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(current, value) {
+			return nil, &patchError{http.StatusConflict, fmt.Sprintf("The test of %q failed", *op.Path)}
+		}
+
+		return doc, nil
+	}
+
+	return addPointer(doc, path, value)
+}
+
+// pointerUnescaper unescapes the reference tokens of JSON pointers.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// parsePointer splits a JSON pointer (RFC 6901) into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, invalidPatch("Invalid path %q, it must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the token referencing an element of an array of length n.
+// With end set the index can also be n, referenced by "-" as well.
+func arrayIndex(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, unprocessablePatch("Invalid array index %q", token)
+	}
+
+	if i > n || (i == n && !end) {
+		return 0, unprocessablePatch("Array index %d is out of range", i)
+	}
+
+	return i, nil
+}
+
+// getPointer returns the value of doc referenced by path.
+func getPointer(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch v := doc.(type) {
+		case map[string]any:
+			value, ok := v[token]
+			if !ok {
+				return nil, unprocessablePatch("The member %q doesn't exist", token)
+			}
+
+			doc = value
+		case []any:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = v[i]
+		default:
+			return nil, unprocessablePatch("The member %q doesn't exist", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// setParent returns doc with fn applied to the container referenced by
+// all but the last token of path, which is passed to fn with it.
+func setParent(doc any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := getPointer(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	child, err = setParent(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := doc.(type) {
+	case map[string]any:
+		v[path[0]] = child
+	case []any:
+		i, _ := arrayIndex(path[0], len(v), false)
+		v[i] = child
+	}
+
+	return doc, nil
+}
+
+// addPointer returns doc with value added at path, replacing the member
+// of an object, or inserted into an array.
+func addPointer(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return setParent(doc, path, func(parent any, token string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			v[token] = value
+			return v, nil
+		case []any:
+			i, err := arrayIndex(token, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+
+			return slices.Insert(v, i, value), nil
+		}
+
+		return nil, unprocessablePatch("Can't add %q to a value which isn't an object or an array", token)
+	})
+}
+
+// removePointer returns doc without the value at path.
+func removePointer(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, unprocessablePatch("The whole record can't be removed")
+	}
+
+	return setParent(doc, path, func(parent any, token string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			if _, ok := v[token]; !ok {
+				return nil, unprocessablePatch("The member %q doesn't exist", token)
+			}
+
+			delete(v, token)
+			return v, nil
+		case []any:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+
+			return slices.Delete(v, i, i+1), nil
+		}
+
+		return nil, unprocessablePatch("The member %q doesn't exist", token)
+	})
+}
+
+// deepCopy copies the objects and arrays of a decoded JSON value.
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, item := range v {
+			c[k] = deepCopy(item)
+		}
+
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = deepCopy(item)
+		}
+
+		return c
+	}
+
+	return value
+}
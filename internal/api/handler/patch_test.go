@@ -0,0 +1,213 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+// execPatch sends body to target as a PATCH request with the Content-Type ct.
+func execPatch(t *testing.T, target, ct, body string, status int) models.Problem {
+	t.Helper()
+	w := execWithHeaders(t, "PATCH", target, []byte(body), map[string]string{"Content-Type": ct}, status)
+
+	var rProblem models.Problem
+	if status != http.StatusNoContent {
+		decodeJSONBodyCheckEmpty(t, w, &rProblem)
+	}
+
+	return rProblem
+}
+
+func TestPatchAuthor_MergePatch(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertAuthor(ctx, models.Author{FirstName: "Scalany", LastName: "Autor", BirthYear: 1900, DeathYear: models.I64Ptr(1980)})
+	assert.NoError(t, err)
+	defer database.DelAuthor(ctx, id)
+
+	execPatch(t, "/api/v1/authors/"+strconv.FormatInt(id, 10), "application/merge-patch+json", `{"first_name":"Scalony","death_year":null}`, http.StatusNoContent)
+
+	author, err := database.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Author{ID: id, FirstName: "Scalony", LastName: "Autor", BirthYear: 1900}, author)
+}
+
+// Plain JSON is applied as a merge patch, so it can clear a nullable field.
+func TestPatchAuthor_PlainJSON(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertAuthor(ctx, models.Author{FirstName: "Zwykły", LastName: "Autor", BirthYear: 1900, DeathYear: models.I64Ptr(1980)})
+	assert.NoError(t, err)
+	defer database.DelAuthor(ctx, id)
+
+	execPatch(t, "/api/v1/authors/"+strconv.FormatInt(id, 10), "application/json", `{"death_year":null}`, http.StatusNoContent)
+
+	author, err := database.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Author{ID: id, FirstName: "Zwykły", LastName: "Autor", BirthYear: 1900}, author)
+}
+
+func TestPatchAuthor_JSONPatch(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertAuthor(ctx, models.Author{FirstName: "Łatany", LastName: "Autor", BirthYear: 1900, DeathYear: models.I64Ptr(1980)})
+	assert.NoError(t, err)
+	defer database.DelAuthor(ctx, id)
+
+	body := `[
+		{"op":"test","path":"/first_name","value":"Łatany"},
+		{"op":"copy","from":"/first_name","path":"/last_name"},
+		{"op":"replace","path":"/first_name","value":"Załatany"},
+		{"op":"remove","path":"/death_year"}
+	]`
+	execPatch(t, "/api/v1/authors/"+strconv.FormatInt(id, 10), "application/json-patch+json", body, http.StatusNoContent)
+
+	author, err := database.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Author{ID: id, FirstName: "Załatany", LastName: "Łatany", BirthYear: 1900}, author)
+}
+
+func TestPatchBook_JSONPatch(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertBook(ctx, models.Book{Title: "Łatana", Year: 1990, Pages: 100, Author: 5, Genre: 1, Language: 1})
+	assert.NoError(t, err)
+	defer database.DelBook(ctx, id)
+
+	body := `[{"op":"test","path":"/pages","value":100},{"op":"replace","path":"/pages","value":120},{"op":"move","from":"/genre","path":"/language"},{"op":"add","path":"/genre","value":2}]`
+	execPatch(t, "/api/v1/books/"+strconv.FormatInt(id, 10), "application/json-patch+json", body, http.StatusNoContent)
+
+	book, err := database.GetBook(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Book{ID: id, Title: "Łatana", Year: 1990, Pages: 120, Author: 5, Genre: 2, Language: 1}, book)
+}
+
+func TestPatchBook_PatchDocumentErrors(t *testing.T) {
+	const mergePatch, jsonPatch = "application/merge-patch+json", "application/json-patch+json"
+
+	patchTests := map[string]struct {
+		target     string
+		ct         string
+		body       string
+		status     int
+		violations []models.Violation
+	}{
+		"BadRequest_InvalidMergePatch": {"/books/1", mergePatch, `{"title":`, http.StatusBadRequest, nil},
+		"BadRequest_NotAnArray":        {"/books/1", jsonPatch, `{"op":"remove","path":"/title"}`, http.StatusBadRequest, nil},
+		"BadRequest_UnknownOp":         {"/books/1", jsonPatch, `[{"op":"rename","path":"/title"}]`, http.StatusBadRequest, nil},
+		"BadRequest_MissingValue":      {"/books/1", jsonPatch, `[{"op":"add","path":"/title"}]`, http.StatusBadRequest, nil},
+		"BadRequest_InvalidPointer":    {"/books/1", jsonPatch, `[{"op":"remove","path":"title"}]`, http.StatusBadRequest, nil},
+		"BadRequest_UnknownField":      {"/books/1", mergePatch, `{"isbn":"83-01-00000-1"}`, http.StatusBadRequest, nil},
+		"BadRequest_WrongType":         {"/books/1", mergePatch, `{"pages":"sto"}`, http.StatusBadRequest, nil},
+		"BadRequest_ChangedID":         {"/books/1", jsonPatch, `[{"op":"replace","path":"/id","value":2}]`, http.StatusBadRequest, nil},
+		"BadRequest_ClearedPages": {
+			"/books/1", mergePatch, `{"pages":null}`, http.StatusBadRequest,
+			[]models.Violation{{Field: "pages", Rule: "min", Message: "pages must be at least 1"}},
+		},
+		"BadRequest_ClearedName": {
+			"/genres/1", jsonPatch, `[{"op":"remove","path":"/name"}]`, http.StatusBadRequest,
+			[]models.Violation{{Field: "name", Rule: "required", Message: "name is required"}},
+		},
+		"BadRequest_YearBeforeAuthorBirth": {
+			"/books/1", mergePatch, `{"year":1800,"author":5}`, http.StatusBadRequest,
+			[]models.Violation{{Field: "year", Rule: "afterbirth", Message: "year can't be earlier than the birth year of the author (1921)"}},
+		},
+		"BadRequest_EmptyMergePatch":  {"/books/1", mergePatch, `{}`, http.StatusBadRequest, nil},
+		"BadRequest_EmptyJSON":        {"/books/1", "application/json", `{}`, http.StatusBadRequest, nil},
+		"BadRequest_EmptyPartialYAML": {"/authors/1", "application/yaml", `{}`, http.StatusBadRequest, nil},
+		"NotFound_Record":             {"/books/9999", mergePatch, `{"pages":10}`, http.StatusNotFound, nil},
+		"Conflict_FailedTest":         {"/books/1", jsonPatch, `[{"op":"test","path":"/id","value":2},{"op":"remove","path":"/title"}]`, http.StatusConflict, nil},
+		"Unprocessable_NoMember":      {"/books/1", jsonPatch, `[{"op":"remove","path":"/isbn"}]`, http.StatusUnprocessableEntity, nil},
+		"Unprocessable_NotObject":     {"/books/1", jsonPatch, `[{"op":"add","path":"/title/first","value":"x"}]`, http.StatusUnprocessableEntity, nil},
+		"Unprocessable_MoveInto":      {"/languages/1", jsonPatch, `[{"op":"move","from":"","path":"/name"}]`, http.StatusUnprocessableEntity, nil},
+	}
+
+	before, err := database.GetBook(context.Background(), 1)
+	assert.NoError(t, err)
+
+	for name, tt := range patchTests {
+		t.Run(name, func(t *testing.T) {
+			rProblem := execPatch(t, "/api/v1"+tt.target, tt.ct, tt.body, tt.status)
+
+			assert.NotEmpty(t, rProblem.Detail)
+			assert.Equal(t, tt.violations, rProblem.Errors)
+		})
+	}
+
+	after, err := database.GetBook(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after, "A failed patch changed the book")
+}
+
+func TestPatchGenre_Success(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertGenre(ctx, models.Genre{Name: "Łatka"})
+	assert.NoError(t, err)
+	defer database.DelGenre(ctx, id)
+
+	execAndCheck(t, "PATCH", "/api/v1/genres/"+strconv.FormatInt(id, 10), []byte(`{"name":"Łata"}`), http.StatusNoContent, nil)
+
+	genre, err := database.GetGenre(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Łata", genre.Name)
+
+	execPatch(t, "/api/v1/genres/"+strconv.FormatInt(id, 10), "application/merge-patch+json", `{"name":"Scalona łata"}`, http.StatusNoContent)
+
+	genre, err = database.GetGenre(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Scalona łata", genre.Name)
+}
+
+func TestPatchLanguage_Success(t *testing.T) {
+	ctx := context.Background()
+	id, err := database.InsertLanguage(ctx, models.Language{Name: "Łaciński"})
+	assert.NoError(t, err)
+	defer database.DelLanguage(ctx, id)
+
+	execPatch(t, "/api/v1/languages/"+strconv.FormatInt(id, 10), "application/json-patch+json", `[{"op":"replace","path":"/name","value":"Łacina"}]`, http.StatusNoContent)
+
+	language, err := database.GetLanguage(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Łacina", language.Name)
+}
+
+func TestPatchGenreLanguage_Error(t *testing.T) {
+	patchTests := map[string]ErrorTests{
+		"BadRequest_NoFields": {
+			body:   []byte(`{}`),
+			query:  "/1",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_TooLongName": {
+			body:   []byte(`{"name":"` + strings.Repeat("a", 129) + `"}`),
+			query:  "/1",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_StringPathID": {
+			body:   []byte(`{"name":"Nowela"}`),
+			query:  "/string",
+			status: http.StatusBadRequest,
+		},
+		"NotFound_BigPathID": {
+			body:   []byte(`{"name":"Nowela"}`),
+			query:  "/9999",
+			status: http.StatusNotFound,
+		},
+	}
+
+	runTestErrors(t, "PATCH", "genres", patchTests)
+	runTestErrors(t, "PATCH", "languages", patchTests)
+}
+
+func TestOptions_AcceptPatch(t *testing.T) {
+	for _, resource := range []string{"books", "authors", "genres", "languages"} {
+		t.Run(resource, func(t *testing.T) {
+			w := execAndCheck(t, "OPTIONS", "/api/v1/"+resource+"/1", nil, http.StatusNoContent, nil)
+
+			assert.Contains(t, w.Header().Get("Accept-Patch"), "application/merge-patch+json")
+			assert.Contains(t, w.Header().Get("Accept-Patch"), "application/json-patch+json")
+		})
+	}
+}
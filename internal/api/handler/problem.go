@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/models"
+)
+
+const (
+	// mimeProblemJSON is the media type of RFC 7807 problem details in JSON.
+	mimeProblemJSON = "application/problem+json"
+	// mimeProblemXML is the media type of RFC 7807 problem details in XML.
+	mimeProblemXML = "application/problem+xml"
+	// problemNamespace is the namespace of problem details in XML.
+	problemNamespace = "urn:ietf:rfc:7807"
+)
+
+// problem responds with the RFC 7807 problem details of an error with
+// status, whose occurrence is explained by detail. violations lists the
+// rules which the request body doesn't satisfy.
+func problem(c *gin.Context, status int, detail string, violations ...models.Violation) {
+	respond(c, status, models.NewProblem(status, detail, c.Request.URL.Path, violations...))
+}
+
+// AbortWithProblem aborts the request with the RFC 7807 problem details
+// of an error with status, written as JSON.
+func AbortWithProblem(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", mimeProblemJSON)
+	c.AbortWithStatusJSON(status, models.NewProblem(status, detail, c.Request.URL.Path))
+}
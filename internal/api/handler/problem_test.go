@@ -0,0 +1,169 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+func TestProblem_Validation(t *testing.T) {
+	problemTests := map[string]struct {
+		resource   string
+		body       string
+		violations []models.Violation
+	}{
+		"Book": {
+			"books",
+			`{"title":"","year":1999,"pages":0,"author":1,"genre":1,"language":-1}`,
+			[]models.Violation{
+				{Field: "title", Rule: "required", Message: "title is required"},
+				{Field: "pages", Rule: "min", Message: "pages must be at least 1"},
+				{Field: "language", Rule: "min", Message: "language must be at least 1"},
+			},
+		},
+		"Author": {
+			"authors",
+			`{"first_name":"Jan","last_name":"Kowalski","birth_year":1950,"death_year":1900}`,
+			[]models.Violation{
+				{Field: "death_year", Rule: "gtefield", Message: "death_year can't be less than birth_year"},
+			},
+		},
+		"Genre": {
+			"genres",
+			`{"name":""}`,
+			[]models.Violation{
+				{Field: "name", Rule: "required", Message: "name is required"},
+			},
+		},
+	}
+
+	for name, tt := range problemTests {
+		t.Run(name, func(t *testing.T) {
+			var rProblem models.Problem
+			w := execAndCheck(t, "POST", "/api/v1/"+tt.resource, []byte(tt.body), http.StatusBadRequest, &rProblem)
+
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Equal(t, models.Problem{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "One or more required fields are missing or invalid",
+				Instance: "/api/v1/" + tt.resource,
+				Errors:   tt.violations,
+			}, rProblem)
+		})
+	}
+}
+
+func TestProblem_NotFound(t *testing.T) {
+	var rProblem models.Problem
+	w := execAndCheck(t, "GET", "/api/v1/books/999", nil, http.StatusNotFound, &rProblem)
+
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Not Found", rProblem.Title)
+	assert.Equal(t, http.StatusNotFound, rProblem.Status)
+	assert.Equal(t, "/api/v1/books/999", rProblem.Instance)
+	assert.Empty(t, rProblem.Errors)
+}
+
+func TestProblem_XML(t *testing.T) {
+	w := execWithHeaders(t, "POST", "/api/v1/genres", []byte(`{"name":""}`), map[string]string{"Accept": "application/xml"}, http.StatusBadRequest)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type>`)
+	assert.Contains(t, body, "<errors><i><field>name</field><rule>required</rule>")
+}
+
+func TestProblem_Batch(t *testing.T) {
+	body := []byte(`[{"name":"Problem partii"},{"name":""}]`)
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/genres:batch?atomic=false", body, http.StatusMultiStatus, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 2) {
+		assert.NoError(t, database.DelGenre(context.Background(), rBatch.Results[0].ID))
+		assert.Empty(t, rBatch.Results[0].Errors)
+		assert.Equal(t, []models.Violation{{Field: "name", Rule: "required", Message: "name is required"}}, rBatch.Results[1].Errors)
+	}
+}
+
+func TestProblem_ImportViolations(t *testing.T) {
+	body := []byte(importHeader + "CSV problem,1959,0,Stanisław,Lem,Powieść,Polski\n")
+
+	var rBatch models.Batch
+	execAndCheck(t, "POST", "/api/v1/books/import", body, http.StatusBadRequest, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 1) {
+		assert.Equal(t, []models.Violation{{Field: "pages", Rule: "min", Message: "pages must be at least 1"}}, rBatch.Results[0].Errors)
+	}
+}
+
+func TestProblem_Rules(t *testing.T) {
+	longTitle := strings.Repeat("a", 257)
+	nextYear := time.Now().Year() + 1
+
+	ruleTests := map[string]struct {
+		method    string
+		resource  string
+		body      string
+		violation models.Violation
+	}{
+		"TooLongTitle": {
+			"POST", "books",
+			`{"title":"` + longTitle + `","year":1999,"pages":10,"author":1,"genre":1,"language":1}`,
+			models.Violation{Field: "title", Rule: "max", Message: "title must be at most 256 characters long"},
+		},
+		"FutureYear": {
+			"POST", "books",
+			fmt.Sprintf(`{"title":"Z przyszłości","year":%d,"pages":10,"author":1,"genre":1,"language":1}`, nextYear),
+			models.Violation{Field: "year", Rule: "past", Message: "year can't be in the future"},
+		},
+		"YearBeforeAuthorBirth": {
+			"POST", "books",
+			`{"title":"Przed narodzinami","year":1900,"pages":10,"author":5,"genre":1,"language":1}`,
+			models.Violation{Field: "year", Rule: "afterbirth", Message: "year can't be earlier than the birth year of the author (1921)"},
+		},
+		"PatchNegativePages": {
+			"PATCH", "books/1",
+			`{"pages":-5}`,
+			models.Violation{Field: "pages", Rule: "min", Message: "pages must be at least 1"},
+		},
+		"PatchBirthAfterBooks": {
+			"PATCH", "authors/5",
+			`{"birth_year":1990}`,
+			models.Violation{Field: "birth_year", Rule: "beforebooks", Message: "birth_year can't be later than the year of the first book of the author (1961)"},
+		},
+		"PatchTooLongName": {
+			"PATCH", "authors/5",
+			`{"last_name":"` + longTitle + `"}`,
+			models.Violation{Field: "last_name", Rule: "max", Message: "last_name must be at most 128 characters long"},
+		},
+	}
+
+	for name, tt := range ruleTests {
+		t.Run(name, func(t *testing.T) {
+			var rProblem models.Problem
+			execAndCheck(t, tt.method, "/api/v1/"+tt.resource, []byte(tt.body), http.StatusBadRequest, &rProblem)
+
+			assert.Equal(t, []models.Violation{tt.violation}, rProblem.Errors)
+		})
+	}
+}
+
+func TestProblem_BatchPatch(t *testing.T) {
+	body := []byte(`[{"id":1,"pages":0,"year":-100000}]`)
+
+	var rBatch models.Batch
+	execAndCheck(t, "PATCH", "/api/v1/books:batch", body, http.StatusBadRequest, &rBatch)
+
+	if assert.Len(t, rBatch.Results, 1) {
+		assert.Equal(t, "One or more fields are invalid", rBatch.Results[0].Error)
+		assert.Equal(t, []models.Violation{{Field: "year", Rule: "min", Message: "year must be at least -99999"}}, rBatch.Results[0].Errors)
+	}
+}
@@ -0,0 +1,550 @@
+package handler
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"gopkg.in/yaml.v3"
+	"pawrest/internal/models"
+)
+
+// Formats of request and response bodies.
+const (
+	formatJSON    = "json"
+	formatXML     = "xml"
+	formatYAML    = "yaml"
+	formatMsgPack = "msgpack"
+	formatCSV     = "csv"
+	formatNDJSON  = "ndjson"
+)
+
+const (
+	// mimeCSV is the media type of comma separated values.
+	mimeCSV = "text/csv"
+	// mimeNDJSON is the media type of newline delimited JSON.
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// mediaType maps a media type to the format it names.
+type mediaType struct {
+	mime   string
+	format string
+}
+
+// mediaTypes lists the media types of the formats in the order they're
+// offered in, so that a wildcard in the Accept header picks JSON.
+var mediaTypes = []mediaType{
+	{binding.MIMEJSON, formatJSON},
+	{binding.MIMEXML, formatXML},
+	{binding.MIMEXML2, formatXML},
+	{binding.MIMEYAML2, formatYAML},
+	{binding.MIMEYAML, formatYAML},
+	{"text/yaml", formatYAML},
+	{binding.MIMEMSGPACK2, formatMsgPack},
+	{binding.MIMEMSGPACK, formatMsgPack},
+	{"application/vnd.msgpack", formatMsgPack},
+	{mimeCSV, formatCSV},
+	{mimeNDJSON, formatNDJSON},
+}
+
+// formatNames are the names of the formats used in error messages.
+var formatNames = map[string]string{
+	formatJSON:    "JSON",
+	formatXML:     "XML",
+	formatYAML:    "YAML",
+	formatMsgPack: "MessagePack",
+}
+
+// isStreamed reports whether format is only available for lists,
+// which are streamed record by record.
+func isStreamed(format string) bool {
+	return format == formatCSV || format == formatNDJSON
+}
+
+// responseFormat returns the format of the response negotiated with the
+// Accept header, JSON when it's not set. CSV and NDJSON are only offered to
+// GET requests. It returns false when none of the accepted media types can
+// be produced.
+func responseFormat(c *gin.Context) (string, bool) {
+	accept := c.GetHeader("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON, true
+	}
+
+	type mediaRange struct {
+		mime string
+		q    float64
+	}
+
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mime, params, _ := strings.Cut(part, ";")
+		r := mediaRange{mime: strings.ToLower(strings.TrimSpace(mime)), q: 1}
+
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(p, "=")
+			if strings.TrimSpace(k) != "q" {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				r.q = q
+			}
+		}
+
+		if r.mime != "" && r.q > 0 {
+			ranges = append(ranges, r)
+		}
+	}
+
+	slices.SortStableFunc(ranges, func(a, b mediaRange) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	streams := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+
+	for _, r := range ranges {
+		for _, t := range mediaTypes {
+			if isStreamed(t.format) && !streams {
+				continue
+			}
+
+			if r.mime == t.mime || r.mime == "*/*" ||
+				strings.HasSuffix(r.mime, "/*") && strings.HasPrefix(t.mime, strings.TrimSuffix(r.mime, "*")) {
+				return t.format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Negotiate responds with 406 to requests which don't accept any of the
+// formats the API responds with, before they're handled. OPTIONS requests
+// are let through, as they're responded to without a body.
+func Negotiate(c *gin.Context) {
+	if c.Request.Method == http.MethodOptions {
+		return
+	}
+
+	if _, ok := responseFormat(c); !ok {
+		notAcceptable(c)
+		c.Abort()
+	}
+}
+
+// notAcceptable responds with 406, in JSON, as the client doesn't accept
+// any format the response can be written in.
+func notAcceptable(c *gin.Context) {
+	problem(c, http.StatusNotAcceptable,
+		"The response can only be written as JSON, XML, YAML or MessagePack, or as CSV or NDJSON for lists")
+}
+
+// respond writes obj with the code in the format negotiated with the Accept
+// header. Successful responses which can't be written in it respond with 406
+// instead. Errors are written as JSON in that case, so that they're not lost.
+// Problem details are written with their own media types in JSON and XML.
+func respond(c *gin.Context, code int, obj any) {
+	format, ok := responseFormat(c)
+	if !ok || isStreamed(format) {
+		if code < http.StatusBadRequest {
+			notAcceptable(c)
+			return
+		}
+
+		format = formatJSON
+	}
+
+	_, isProblem := obj.(models.Problem)
+
+	if format == formatJSON {
+		if isProblem {
+			c.Header("Content-Type", mimeProblemJSON)
+		}
+
+		c.JSON(code, obj)
+		return
+	}
+
+	tree, err := toTree(obj)
+	if err != nil {
+		log.Println(err.Error())
+		problem(c, http.StatusInternalServerError, "An Internal Server Error occurred")
+		return
+	}
+
+	switch format {
+	case formatXML:
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+
+		if isProblem {
+			// RFC 7807 writes the items of arrays as i elements.
+			buf.WriteString(`<problem xmlns="` + problemNamespace + `">`)
+			for _, f := range tree.(object) {
+				writeXML(&buf, f.key, f.value, "i")
+			}
+			buf.WriteString("</problem>")
+			c.Data(code, mimeProblemXML+"; charset=utf-8", buf.Bytes())
+
+			return
+		}
+
+		writeXML(&buf, "response", tree, "item")
+		c.Data(code, "application/xml; charset=utf-8", buf.Bytes())
+	case formatYAML:
+		out, err := yaml.Marshal(yamlNode(tree))
+		if err != nil {
+			log.Println(err.Error())
+			problem(c, http.StatusInternalServerError, "An Internal Server Error occurred")
+			return
+		}
+
+		c.Data(code, "application/yaml; charset=utf-8", out)
+	case formatMsgPack:
+		c.Render(code, render.MsgPack{Data: plain(tree)})
+	}
+}
+
+// field is a member of an object of a response tree.
+type field struct {
+	key   string
+	value any
+}
+
+// object is an object of a response tree, which keeps
+// the order of its fields as encoded in JSON.
+type object []field
+
+// toTree converts obj into the tree of its JSON encoding, made of
+// objects, []any lists, strings, int64 and float64 numbers, bools and nils.
+// The formats other than JSON are written from it, so that they share the
+// field names and omissions of JSON.
+func toTree(obj any) (any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Marshal error: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return decodeTree(dec)
+}
+
+func decodeTree(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			o := object{}
+
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := decodeTree(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				o = append(o, field{key.(string), value})
+			}
+
+			_, err := dec.Token()
+			return o, err
+		}
+
+		list := []any{}
+
+		for dec.More() {
+			value, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		_, err := dec.Token()
+		return list, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+
+		return t.Float64()
+	}
+
+	return tok, nil
+}
+
+// writeXML writes value as the element name. Items of lists are written as
+// elements named item, and fields whose keys aren't valid element names as
+// entry elements with a key attribute. Null values are left out.
+func writeXML(buf *bytes.Buffer, name string, value any, item string) {
+	if value == nil {
+		return
+	}
+
+	start := "<" + name
+	if !isXMLName(name) {
+		var key bytes.Buffer
+		xml.EscapeText(&key, []byte(name))
+		start, name = `<entry key="`+key.String()+`"`, "entry"
+	}
+
+	buf.WriteString(start + ">")
+
+	switch v := value.(type) {
+	case object:
+		for _, f := range v {
+			writeXML(buf, f.key, f.value, item)
+		}
+	case []any:
+		for _, elem := range v {
+			writeXML(buf, item, elem, item)
+		}
+	case string:
+		xml.EscapeText(buf, []byte(v))
+	default:
+		buf.WriteString(scalar(v))
+	}
+
+	buf.WriteString("</" + name + ">")
+}
+
+// isXMLName reports whether name can be used as an element name.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r), r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// yamlNode converts a response tree into a YAML node, keeping the order of its fields.
+func yamlNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case object:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range v {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.key}, yamlNode(f.value))
+		}
+
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+
+		return n
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: scalar(v)}
+	case float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: scalar(v)}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: scalar(v)}
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+// scalar formats a number or a bool of a response tree.
+func scalar(value any) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// plain converts the objects of a response tree into maps.
+func plain(value any) any {
+	switch v := value.(type) {
+	case object:
+		m := make(map[string]any, len(v))
+		for _, f := range v {
+			m[f.key] = plain(f.value)
+		}
+
+		return m
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = plain(item)
+		}
+
+		return list
+	}
+
+	return value
+}
+
+// errUnsupportedBody is returned for request bodies in a format that can't be read.
+var errUnsupportedBody = errors.New("unsupported body format")
+
+// readBody reads the request body in the format named by its Content-Type,
+// JSON when it's not set, and returns it encoded as JSON. XML doesn't tell
+// strings from numbers, so it's decoded into target, which is then encoded
+// as JSON. It responds with 415 for other formats and with 400 when the body
+// can't be decoded.
+func readBody(c *gin.Context, target any, expected string) ([]byte, string, bool) {
+	format, data, err := bodyJSON(c, target)
+
+	switch {
+	case errors.Is(err, errUnsupportedBody):
+		problem(c, http.StatusUnsupportedMediaType, "The request body must be JSON, XML, YAML or MessagePack")
+		return nil, "", false
+	case err != nil:
+		problem(c, http.StatusBadRequest, invalidBody(format, expected))
+		return nil, "", false
+	}
+
+	return data, format, true
+}
+
+func bodyJSON(c *gin.Context, target any) (string, []byte, error) {
+	format := formatJSON
+
+	if ct := c.ContentType(); ct != "" {
+		i := slices.IndexFunc(mediaTypes, func(t mediaType) bool {
+			return t.mime == ct && !isStreamed(t.format)
+		})
+		if i < 0 {
+			return "", nil, errUnsupportedBody
+		}
+
+		format = mediaTypes[i].format
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return format, nil, err
+	}
+
+	var generic any
+
+	switch format {
+	case formatJSON:
+		return format, body, nil
+	case formatXML:
+		if err := xml.Unmarshal(body, target); err != nil {
+			return format, nil, err
+		}
+
+		data, err := json.Marshal(target)
+		return format, data, err
+	case formatYAML:
+		err = yaml.Unmarshal(body, &generic)
+	case formatMsgPack:
+		err = binding.MsgPack.BindBody(body, &generic)
+	}
+
+	if err != nil {
+		return format, nil, err
+	}
+
+	data, err := json.Marshal(normalize(generic))
+	return format, data, err
+}
+
+// normalize converts the maps with keys other than strings, which YAML and
+// MessagePack decode into, to maps with string keys and the raw strings of
+// MessagePack to strings, so that the value can be encoded as JSON.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			if b, ok := k.([]byte); ok {
+				k = string(b)
+			}
+
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+
+		return m
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalize(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	case []byte:
+		return string(v)
+	}
+
+	return value
+}
+
+func invalidBody(format, expected string) string {
+	return "Invalid " + formatNames[format] + " in request body" + expected
+}
+
+// bindBody decodes the request body into obj, and validates it. It responds
+// with 415 when the body is in an unsupported format and with 400 when it
+// can't be decoded, and returns false.
+func bindBody(c *gin.Context, obj any) bool {
+	data, format, ok := readBody(c, obj, "")
+	if !ok {
+		return false
+	}
+
+	if err := binding.JSON.BindBody(data, obj); err != nil {
+		problem(c, http.StatusBadRequest, invalidBody(format, ""))
+		return false
+	}
+
+	return true
+}
+
+// xmlList decodes the item elements of an XML document into
+// a list, which it's encoded as in JSON.
+type xmlList[T any] struct {
+	Items []T `xml:"item"`
+}
+
+func (l xmlList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Items)
+}
@@ -0,0 +1,324 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"pawrest/internal/models"
+)
+
+// execWithHeaders requests target with the headers set.
+func execWithHeaders(t *testing.T, method, target string, body []byte, headers map[string]string, status int) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	setupTestRouter(database).ServeHTTP(w, req)
+	assert.Equal(t, status, w.Code)
+
+	return w
+}
+
+func encodeMsgPack(t *testing.T, obj any) []byte {
+	t.Helper()
+	w := httptest.NewRecorder()
+	assert.NoError(t, render.MsgPack{Data: obj}.Render(w))
+
+	return w.Body.Bytes()
+}
+
+// GET /books/:id with Accept set to each of the formats
+func TestGetBook_Formats(t *testing.T) {
+	var rBook models.Book
+	execAndCheck(t, "GET", "/api/v1/books/1", nil, http.StatusOK, &rBook)
+
+	decoders := map[string]struct {
+		accept      string
+		contentType string
+		decode      func(data []byte, book *models.Book) error
+	}{
+		"XML": {
+			"application/xml",
+			"application/xml; charset=utf-8",
+			func(data []byte, book *models.Book) error { return xml.Unmarshal(data, book) },
+		},
+		"XML_Text": {
+			"text/xml",
+			"application/xml; charset=utf-8",
+			func(data []byte, book *models.Book) error { return xml.Unmarshal(data, book) },
+		},
+		"YAML": {
+			"application/yaml",
+			"application/yaml; charset=utf-8",
+			func(data []byte, book *models.Book) error { return yaml.Unmarshal(data, book) },
+		},
+		"MsgPack": {
+			"application/msgpack",
+			"application/msgpack; charset=utf-8",
+			func(data []byte, book *models.Book) error { return binding.MsgPack.BindBody(data, book) },
+		},
+	}
+
+	for name, tt := range decoders {
+		t.Run(name, func(t *testing.T) {
+			w := execWithHeaders(t, "GET", "/api/v1/books/1", nil, map[string]string{"Accept": tt.accept}, http.StatusOK)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+
+			var book models.Book
+			assert.NoError(t, tt.decode(w.Body.Bytes(), &book))
+			assert.Equal(t, rBook, book)
+		})
+	}
+}
+
+// GET /genres with Accept: application/xml
+func TestListGenres_XML(t *testing.T) {
+	var rGenres []models.Genre
+	execAndCheck(t, "GET", "/api/v1/genres", nil, http.StatusOK, &rGenres)
+
+	w := execWithHeaders(t, "GET", "/api/v1/genres", nil, map[string]string{"Accept": "application/xml"}, http.StatusOK)
+	assert.True(t, strings.HasPrefix(w.Body.String(), xml.Header+"<response><item><id>"))
+
+	var list struct {
+		Items []models.Genre `xml:"item"`
+	}
+
+	assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, rGenres, list.Items)
+}
+
+// GET /authors with Accept: application/yaml
+func TestListAuthors_YAML(t *testing.T) {
+	w := execWithHeaders(t, "GET", "/api/v1/authors?last_name=Lem", nil, map[string]string{"Accept": "application/yaml"}, http.StatusOK)
+
+	assert.Equal(t, "- id: 5\n  first_name: Stanisław\n  last_name: Lem\n  birth_year: 1921\n  death_year: 2006\n", w.Body.String(),
+		"The fields should keep their order")
+}
+
+func TestListGroups_XML(t *testing.T) {
+	w := execWithHeaders(t, "GET", "/api/v1/books?group_by=author&agg=count", nil, map[string]string{"Accept": "application/xml"}, http.StatusOK)
+
+	assert.Contains(t, w.Body.String(), "<group><author>")
+	assert.Contains(t, w.Body.String(), "<aggregates><count>")
+}
+
+func TestRespond_Negotiation(t *testing.T) {
+	negotiationTests := map[string]struct {
+		target      string
+		accept      string
+		status      int
+		contentType string
+	}{
+		"Wildcard": {
+			"/api/v1/genres/1", "*/*", http.StatusOK, "application/json; charset=utf-8",
+		},
+		"TypeWildcard": {
+			"/api/v1/genres/1", "text/*", http.StatusOK, "application/xml; charset=utf-8",
+		},
+		"Quality": {
+			"/api/v1/genres/1", "application/xml;q=0.5, application/yaml", http.StatusOK, "application/yaml; charset=utf-8",
+		},
+		"Order": {
+			"/api/v1/genres/1", "application/x-msgpack, application/json", http.StatusOK, "application/msgpack; charset=utf-8",
+		},
+		"Fallback": {
+			"/api/v1/genres/1", "image/png, application/json;q=0.1", http.StatusOK, "application/json; charset=utf-8",
+		},
+		"CSV_List": {
+			"/api/v1/genres", "text/csv", http.StatusOK, "text/csv; charset=utf-8",
+		},
+		"NotAcceptable": {
+			"/api/v1/genres", "image/png", http.StatusNotAcceptable, "application/problem+json",
+		},
+		"NotAcceptable_Excluded": {
+			"/api/v1/genres", "application/json;q=0", http.StatusNotAcceptable, "application/problem+json",
+		},
+		"NotAcceptable_CSVRecord": {
+			"/api/v1/genres/1", "text/csv", http.StatusNotAcceptable, "application/problem+json",
+		},
+		"NotAcceptable_NDJSONStats": {
+			"/api/v1/stats/decades", "application/x-ndjson", http.StatusNotAcceptable, "application/problem+json",
+		},
+		"Error_XML": {
+			"/api/v1/genres/999", "application/xml", http.StatusNotFound, "application/problem+xml; charset=utf-8",
+		},
+		"Error_CSV": {
+			"/api/v1/genres/999", "text/csv", http.StatusNotFound, "application/problem+json",
+		},
+	}
+
+	for name, tt := range negotiationTests {
+		t.Run(name, func(t *testing.T) {
+			w := execWithHeaders(t, "GET", tt.target, nil, map[string]string{"Accept": tt.accept}, tt.status)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+// POST /genres with bodies in each of the formats
+func TestPostGenre_Formats(t *testing.T) {
+	bodyTests := map[string]struct {
+		contentType string
+		body        []byte
+		name        string
+	}{
+		"XML": {
+			"application/xml",
+			[]byte("<genre><name>Gatunek XML</name></genre>"),
+			"Gatunek XML",
+		},
+		"YAML": {
+			"application/x-yaml",
+			[]byte("name: Gatunek YAML\n"),
+			"Gatunek YAML",
+		},
+		"MsgPack": {
+			"application/msgpack",
+			encodeMsgPack(t, map[string]any{"name": "Gatunek MessagePack"}),
+			"Gatunek MessagePack",
+		},
+		"JSON_Charset": {
+			"application/json; charset=utf-8",
+			[]byte(`{"name":"Gatunek JSON"}`),
+			"Gatunek JSON",
+		},
+	}
+
+	for name, tt := range bodyTests {
+		t.Run(name, func(t *testing.T) {
+			w := execWithHeaders(t, "POST", "/api/v1/genres", tt.body, map[string]string{"Content-Type": tt.contentType}, http.StatusCreated)
+
+			var rGenre models.Genre
+			decodeJSONBodyCheckEmpty(t, w, &rGenre)
+			defer database.DelGenre(context.Background(), rGenre.ID)
+
+			assert.Equal(t, tt.name, rGenre.Name)
+		})
+	}
+}
+
+// PATCH /authors/:id with an XML body
+func TestPatchAuthor_XML(t *testing.T) {
+	id, err := database.InsertAuthor(context.Background(), models.Author{FirstName: "Autor", LastName: "XML", BirthYear: 1900})
+	assert.NoError(t, err)
+	defer database.DelAuthor(context.Background(), id)
+
+	body := []byte("<author><death_year>1950</death_year></author>")
+	execWithHeaders(t, "PATCH", "/api/v1/authors/"+strconv.FormatInt(id, 10), body, map[string]string{"Content-Type": "text/xml"}, http.StatusNoContent)
+
+	author, err := database.GetAuthor(context.Background(), id)
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, author.DeathYear) {
+		assert.Equal(t, int64(1950), *author.DeathYear)
+	}
+}
+
+// POST /genres:batch with an XML body
+func TestPostGenresBatch_XML(t *testing.T) {
+	body := []byte("<genres><item><name>Partia XML 1</name></item><item><name>Partia XML 2</name></item></genres>")
+
+	w := execWithHeaders(t, "POST", "/api/v1/genres:batch", body,
+		map[string]string{"Content-Type": "application/xml", "Accept": "application/xml"}, http.StatusCreated)
+
+	var rBatch struct {
+		Succeeded int `xml:"succeeded"`
+		Results   []struct {
+			ID int64 `xml:"id"`
+		} `xml:"results>item"`
+	}
+
+	assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &rBatch))
+	assert.Equal(t, 2, rBatch.Succeeded)
+
+	for _, r := range rBatch.Results {
+		assert.NoError(t, database.DelGenre(context.Background(), r.ID))
+	}
+}
+
+// POST /login with a YAML body
+func TestLoginToken_YAML(t *testing.T) {
+	w := execWithHeaders(t, "POST", "/api/v1/login", []byte("return_admin_token: true\n"),
+		map[string]string{"Content-Type": "application/yaml", "Accept": "application/yaml"}, http.StatusOK)
+
+	var rToken models.Token
+	assert.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &rToken))
+	assert.True(t, rToken.Admin)
+	checkTokenStructure(t, rToken.Token)
+}
+
+func TestBindBody_Error(t *testing.T) {
+	bodyTests := map[string]struct {
+		target      string
+		contentType string
+		body        []byte
+		status      int
+		message     string
+	}{
+		"UnsupportedMediaType": {
+			"/api/v1/genres", "text/plain", []byte("name"),
+			http.StatusUnsupportedMediaType, "The request body must be JSON, XML, YAML or MessagePack",
+		},
+		"UnsupportedMediaType_CSV": {
+			"/api/v1/genres", "text/csv", []byte("name\nGatunek\n"),
+			http.StatusUnsupportedMediaType, "The request body must be JSON, XML, YAML or MessagePack",
+		},
+		"BadRequest_XML": {
+			"/api/v1/genres", "application/xml", []byte("<genre><name>Gatunek</genre>"),
+			http.StatusBadRequest, "Invalid XML in request body",
+		},
+		"BadRequest_YAML": {
+			"/api/v1/genres", "application/yaml", []byte("name: [Gatunek"),
+			http.StatusBadRequest, "Invalid YAML in request body",
+		},
+		"BadRequest_YAMLType": {
+			"/api/v1/authors", "application/yaml", []byte("first_name: Autor\nlast_name: YAML\nbirth_year: rok\n"),
+			http.StatusBadRequest, "Invalid YAML in request body",
+		},
+		"BadRequest_MsgPack": {
+			"/api/v1/genres", "application/msgpack", []byte{0xc1},
+			http.StatusBadRequest, "Invalid MessagePack in request body",
+		},
+		"BadRequest_YAMLLogin": {
+			"/api/v1/login", "application/yaml", []byte("admin: true\n"),
+			http.StatusBadRequest, "Invalid YAML in request body",
+		},
+		"BadRequest_XMLBatch": {
+			"/api/v1/genres:batch", "application/xml", []byte("<genres><item>"),
+			http.StatusBadRequest, "Invalid XML in request body, expected an array",
+		},
+		"BadRequest_YAMLBatch": {
+			"/api/v1/genres:batch", "application/yaml", []byte("name: Gatunek\n"),
+			http.StatusBadRequest, "Invalid YAML in request body, expected an array",
+		},
+	}
+
+	for name, tt := range bodyTests {
+		t.Run(name, func(t *testing.T) {
+			w := execWithHeaders(t, "POST", tt.target, tt.body, map[string]string{"Content-Type": tt.contentType}, tt.status)
+
+			var rError models.Problem
+			decodeJSONBodyCheckEmpty(t, w, &rError)
+			assert.Equal(t, tt.message, rError.Detail)
+		})
+	}
+}
+
+// OPTIONS requests are answered whatever they accept.
+func TestOptions_NotNegotiated(t *testing.T) {
+	w := execWithHeaders(t, "OPTIONS", "/api/v1/genres", nil, map[string]string{"Accept": "image/png"}, http.StatusNoContent)
+	assert.NotEmpty(t, w.Header().Get("Allow"))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// @Summary		Search books, authors, genres and languages
+// @Description	Responds with the resources matching any of the words of the query as JSON, the best matches first. Book hits include the extended book information.
+// @Tags			Search
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			q		query		string				true	"Search query"
+// @Param			limit	query		int					false	"Maximum number of hits (1-100, default 20)"
+// @Success		200		{array}		models.SearchHit	"OK - Found hits"
+// @Failure		400		{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401		{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500		{object}	models.Problem		"Internal Server Error"
+// @Router			/search [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		problem(c, http.StatusBadRequest, "The q parameter is required")
+		return
+	}
+
+	limit := defaultSearchLimit
+
+	if l, ok := c.GetQuery("limit"); ok {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			problem(c, http.StatusBadRequest, "The limit must be an integer between 1 and 100")
+			return
+		}
+
+		limit = n
+	}
+
+	hits, err := h.DB.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, hits)
+}
+
+// @Summary		Return allowed operations for search
+// @Description	Responds with an empty response body.
+// @Tags			Search
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/search [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsSearch(c *gin.Context) {
+	c.Header("Allow", "GET, OPTIONS")
+	c.Status(http.StatusNoContent)
+}
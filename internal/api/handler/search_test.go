@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+// GET /search
+func TestSearch_Success(t *testing.T) {
+	var rHits []models.SearchHit
+	execAndCheck(t, "GET", "/api/v1/search?q=Solaris", nil, http.StatusOK, &rHits)
+
+	assert.Equal(t, models.HitBook, rHits[0].Type, "The best hit should be the book")
+	assert.NotNil(t, rHits[0].Book, "The book hit should hold the book")
+	assert.Greater(t, rHits[0].Score, 0.0, "The score should be positive")
+}
+
+func TestSearch_Limit(t *testing.T) {
+	var rHits []models.SearchHit
+	execAndCheck(t, "GET", "/api/v1/search?q=pan&limit=1", nil, http.StatusOK, &rHits)
+
+	assert.Len(t, rHits, 1, "The number of hits should not exceed the limit")
+}
+
+func TestSearch_Error(t *testing.T) {
+	searchTests := map[string]ErrorTests{
+		"BadRequest_NoQuery": {
+			body:   nil,
+			query:  "",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_BlankQuery": {
+			body:   nil,
+			query:  "?q=%20",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_StringLimit": {
+			body:   nil,
+			query:  "?q=Lem&limit=abc",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_LimitTooBig": {
+			body:   nil,
+			query:  "?q=Lem&limit=101",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "search", searchTests)
+}
+
+// OPTIONS /search
+func TestOptionsSearch(t *testing.T) {
+	optionsTests := map[string]struct {
+		query   string
+		methods []string
+	}{
+		"Search": {
+			query:   "",
+			methods: []string{"GET", "OPTIONS"},
+		},
+	}
+
+	runTestOptionsSuccess(t, "search", optionsTests)
+}
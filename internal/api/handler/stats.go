@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/models"
+)
+
+// @Summary		Get all book statistics
+// @Description	Responds with all of the statistics of the /stats endpoints as JSON. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string			false	"Book title"
+// @Param			year		query		int				false	"Year of publishing of the book"
+// @Param			pages		query		int				false	"Number of pages in the book"
+// @Param			author		query		int				false	"Author id"
+// @Param			genre		query		int				false	"Genre id"
+// @Param			language	query		int				false	"Language id"
+// @Param			or			query		string			false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{object}	models.Stats	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem	"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem	"Internal Server Error"
+// @Router			/stats [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStats(c *gin.Context) {
+	respondStats(c, h.stats)
+}
+
+// @Summary		Get the number of books per genre
+// @Description	Responds with the number of books of each genre as JSON, the most numerous genres first. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string				false	"Book title"
+// @Param			year		query		int					false	"Year of publishing of the book"
+// @Param			pages		query		int					false	"Number of pages in the book"
+// @Param			author		query		int					false	"Author id"
+// @Param			genre		query		int					false	"Genre id"
+// @Param			language	query		int					false	"Language id"
+// @Param			or			query		string				false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{array}		models.BookCount	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/stats/genres [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStatsGenres(c *gin.Context) {
+	respondStats(c, h.DB.BooksPerGenre)
+}
+
+// @Summary		Get the number of books per language
+// @Description	Responds with the number of books in each language as JSON, the most numerous languages first. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string				false	"Book title"
+// @Param			year		query		int					false	"Year of publishing of the book"
+// @Param			pages		query		int					false	"Number of pages in the book"
+// @Param			author		query		int					false	"Author id"
+// @Param			genre		query		int					false	"Genre id"
+// @Param			language	query		int					false	"Language id"
+// @Param			or			query		string				false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{array}		models.BookCount	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/stats/languages [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStatsLanguages(c *gin.Context) {
+	respondStats(c, h.DB.BooksPerLanguage)
+}
+
+// @Summary		Get the productivity of authors
+// @Description	Responds with the number of books of each author and the years their first and last books were published in as JSON, the most productive authors first. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string				false	"Book title"
+// @Param			year		query		int					false	"Year of publishing of the book"
+// @Param			pages		query		int					false	"Number of pages in the book"
+// @Param			author		query		int					false	"Author id"
+// @Param			genre		query		int					false	"Genre id"
+// @Param			language	query		int					false	"Language id"
+// @Param			or			query		string				false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{array}		models.AuthorStats	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/stats/authors [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStatsAuthors(c *gin.Context) {
+	respondStats(c, h.DB.BooksPerAuthor)
+}
+
+// @Summary		Get the number of books per decade
+// @Description	Responds with the number of books published in each decade as JSON, in chronological order. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string				false	"Book title"
+// @Param			year		query		int					false	"Year of publishing of the book"
+// @Param			pages		query		int					false	"Number of pages in the book"
+// @Param			author		query		int					false	"Author id"
+// @Param			genre		query		int					false	"Genre id"
+// @Param			language	query		int					false	"Language id"
+// @Param			or			query		string				false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{array}		models.DecadeCount	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/stats/decades [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStatsDecades(c *gin.Context) {
+	respondStats(c, h.DB.BooksPerDecade)
+}
+
+// @Summary		Get the page count statistics
+// @Description	Responds with the minimum, maximum, average and median page count of books as JSON. Accepts the filtering parameters of /books, which scope the books that are counted.
+// @Tags			Stats
+// @Produce		json,xml,application/yaml,application/msgpack
+// @Param			title		query		string				false	"Book title"
+// @Param			year		query		int					false	"Year of publishing of the book"
+// @Param			pages		query		int					false	"Number of pages in the book"
+// @Param			author		query		int					false	"Author id"
+// @Param			genre		query		int					false	"Genre id"
+// @Param			language	query		int					false	"Language id"
+// @Param			or			query		string				false	"Conditions of which any must match, e.g. (genre.eq:5,pages.lt:30)"
+// @Success		200			{object}	models.PageStats	"OK - Computed statistics"
+// @Failure		400			{object}	models.Problem		"Bad Request - Invalid input"
+// @Failure		401			{object}	models.Problem		"Unauthorized - Invalid or missing token"
+// @Failure		500			{object}	models.Problem		"Internal Server Error"
+// @Router			/stats/pages [get]
+// @Security		ApiKeyAuth
+func (h *Handlers) GetStatsPages(c *gin.Context) {
+	respondStats(c, h.DB.PageStats)
+}
+
+// stats gathers the results of every statistics query.
+func (h *Handlers) stats(ctx context.Context, params url.Values) (models.Stats, error) {
+	var (
+		s   models.Stats
+		err error
+	)
+
+	if s.Genres, err = h.DB.BooksPerGenre(ctx, params); err != nil {
+		return s, err
+	}
+
+	if s.Languages, err = h.DB.BooksPerLanguage(ctx, params); err != nil {
+		return s, err
+	}
+
+	if s.Authors, err = h.DB.BooksPerAuthor(ctx, params); err != nil {
+		return s, err
+	}
+
+	if s.Decades, err = h.DB.BooksPerDecade(ctx, params); err != nil {
+		return s, err
+	}
+
+	s.Pages, err = h.DB.PageStats(ctx, params)
+
+	return s, err
+}
+
+// respondStats responds with the statistics returned by get
+// for the filtering parameters of the request.
+func respondStats[T any](c *gin.Context, get func(context.Context, url.Values) (T, error)) {
+	stats, err := get(c.Request.Context(), c.Request.URL.Query())
+	if err != nil {
+		handleDBError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, stats)
+}
+
+// @Summary		Return allowed operations for statistics
+// @Description	Responds with an empty response body.
+// @Tags			Stats
+// @Success		204	"No Content - Successfully responded with available options"
+// @Failure		401	{object}	models.Problem	"Unauthorized - Invalid or missing token"
+// @Header			204	{string}	Allow			"Allowed operations for the resource"
+// @Router			/stats [options]
+// @Router			/stats/genres [options]
+// @Router			/stats/languages [options]
+// @Router			/stats/authors [options]
+// @Router			/stats/decades [options]
+// @Router			/stats/pages [options]
+// @Security		ApiKeyAuth
+func (h *Handlers) OptionsStats(c *gin.Context) {
+	c.Header("Allow", "GET, OPTIONS")
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+// GET /stats
+func TestGetStats_Success(t *testing.T) {
+	var rStats models.Stats
+	execAndCheck(t, "GET", "/api/v1/stats", nil, http.StatusOK, &rStats)
+
+	w := execAndCheck(t, "GET", "/api/v1/books?limit=1", nil, http.StatusOK, nil)
+	total, _ := strconv.ParseInt(w.Header().Get("X-Total-Count"), 10, 64)
+
+	sum := func(counts []models.BookCount) int64 {
+		var n int64
+		for _, c := range counts {
+			n += c.Books
+		}
+
+		return n
+	}
+
+	assert.Equal(t, total, sum(rStats.Genres), "Every book should be counted in its genre")
+	assert.Equal(t, total, sum(rStats.Languages), "Every book should be counted in its language")
+	assert.Equal(t, total, rStats.Pages.Books)
+	assert.NotEmpty(t, rStats.Authors)
+	assert.NotEmpty(t, rStats.Decades)
+}
+
+// GET /stats/...
+func TestGetStats_Filter(t *testing.T) {
+	var rDecades []models.DecadeCount
+	execAndCheck(t, "GET", "/api/v1/stats/decades?year.between=1960,1969", nil, http.StatusOK, &rDecades)
+
+	if assert.Len(t, rDecades, 1) {
+		assert.Equal(t, int64(1960), rDecades[0].Decade)
+	}
+
+	var rAuthors []models.AuthorStats
+	execAndCheck(t, "GET", "/api/v1/stats/authors?author=5", nil, http.StatusOK, &rAuthors)
+
+	if assert.Len(t, rAuthors, 1) {
+		assert.Equal(t, "Lem", rAuthors[0].LastName)
+		assert.LessOrEqual(t, rAuthors[0].FirstYear, rAuthors[0].LastYear)
+	}
+
+	var rPages models.PageStats
+	execAndCheck(t, "GET", "/api/v1/stats/pages?pages.gte=300", nil, http.StatusOK, &rPages)
+	assert.GreaterOrEqual(t, rPages.Min, int64(300))
+	assert.True(t, float64(rPages.Min) <= rPages.Median && rPages.Median <= float64(rPages.Max))
+}
+
+func TestGetStats_Error(t *testing.T) {
+	statsTests := map[string]ErrorTests{
+		"BadRequest_UnknownParam": {
+			query:  "/genres?foo=bar",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Sort": {
+			query:  "/languages?sort_by=year",
+			status: http.StatusBadRequest,
+		},
+		"BadRequest_Limit": {
+			query:  "?limit=5",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	runTestErrors(t, "GET", "stats", statsTests)
+}
+
+// OPTIONS /stats
+func TestOptionsStats_Success(t *testing.T) {
+	optionsTests := map[string]struct {
+		query   string
+		methods []string
+	}{
+		"Overview": {
+			"",
+			[]string{"GET", "OPTIONS"},
+		},
+		"Pages": {
+			"/pages",
+			[]string{"GET", "OPTIONS"},
+		},
+	}
+
+	runTestOptionsSuccess(t, "stats", optionsTests)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+const (
+	// streamFlushEvery is the number of records written
+	// to a stream between its flushes.
+	streamFlushEvery = 100
+	// streamWriteTimeout is the time allowed for writing the records
+	// between two flushes of a stream.
+	streamWriteTimeout = 10 * time.Second
+	// streamErrorTrailer is the trailer set when a stream fails
+	// after its status has already been sent.
+	streamErrorTrailer = "X-Stream-Error"
+)
+
+// listFormat returns the format a list was requested in: CSV with
+// format=csv and the format negotiated with the Accept header otherwise,
+// which format=json, the default, leaves the choice to.
+// It responds with 406 when none of the accepted formats can be produced
+// and with 400 when the format is unknown or a streamed format is combined
+// with parameters which only apply to whole documents.
+func listFormat(c *gin.Context, params url.Values, expand []relation) (string, bool) {
+	format, ok := responseFormat(c)
+
+	switch c.Query("format") {
+	case "", formatJSON:
+		if !ok {
+			notAcceptable(c)
+			return "", false
+		}
+	case formatCSV:
+		format = formatCSV
+	default:
+		problem(c, http.StatusBadRequest, "Provided unknown format, expected json or csv")
+		return "", false
+	}
+
+	if isStreamed(format) && (len(expand) > 0 || db.IsGrouping(params) || params.Has("envelope")) {
+		problem(c, http.StatusBadRequest, "The expand, group_by, agg and envelope parameters can't be used with "+format)
+		return "", false
+	}
+
+	return format, true
+}
+
+// respondNDJSON writes the records yielded by records as NDJSON, a JSON
+// object per line, narrowed down to the fields parameter. Each record is
+// written as soon as it's read from the database, and the response is
+// flushed every streamFlushEvery records, when its write deadline is also
+// extended. An error yielded before the first record responds like a failed
+// list request does. A later one ends the stream with a line holding a
+// models.Problem instead of a record and sets the X-Stream-Error trailer,
+// since the status has already been sent by then.
+func respondNDJSON[T any](c *gin.Context, records iter.Seq2[T, error]) {
+	fields := requestedFields(c)
+	enc := json.NewEncoder(c.Writer)
+
+	started := false
+	start := func() {
+		c.Header("Content-Type", mimeNDJSON)
+		c.Header("Trailer", streamErrorTrailer)
+		c.Status(http.StatusOK)
+		extendWriteDeadline(c)
+		started = true
+	}
+
+	n := 0
+
+	for r, err := range records {
+		if err != nil {
+			if !started {
+				handleDBError(c, err)
+				return
+			}
+
+			if p, ok := streamFailed(c, err); ok {
+				enc.Encode(p)
+			}
+
+			return
+		}
+
+		if !started {
+			start()
+		}
+
+		var record any = r
+		if fields != nil {
+			record = pickFields(r, fields)
+		}
+
+		if err := enc.Encode(record); err != nil {
+			// The client is gone.
+			return
+		}
+
+		if n++; n%streamFlushEvery == 0 {
+			c.Writer.Flush()
+			extendWriteDeadline(c)
+		}
+	}
+
+	if !started {
+		start()
+	}
+
+	c.Writer.Flush()
+}
+
+// extendWriteDeadline moves the write deadline of the response, which the
+// server's WriteTimeout sets for the whole of it, streamWriteTimeout ahead,
+// so that long streams aren't cut off. Writers which don't support
+// deadlines are left as they are.
+func extendWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+}
+
+// streamFailed logs err, which ended a stream after its status was sent,
+// and sets the X-Stream-Error trailer to the detail of the problem returned
+// to the client. It returns false when the client closed the connection, as
+// there's no one to report the error to.
+func streamFailed(c *gin.Context, err error) (models.Problem, bool) {
+	if errors.Is(err, context.Canceled) {
+		return models.Problem{}, false
+	}
+
+	log.Println(err.Error())
+
+	p := models.NewProblem(http.StatusInternalServerError, "An Internal Server Error occurred", c.Request.URL.Path)
+	if errors.Is(err, context.DeadlineExceeded) {
+		p = models.NewProblem(http.StatusGatewayTimeout, "The database did not respond in time", c.Request.URL.Path)
+	}
+
+	c.Writer.Header().Set(streamErrorTrailer, p.Detail)
+
+	return p, true
+}
@@ -0,0 +1,171 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// execNDJSON requests target as NDJSON and decodes each line of the response into a T.
+func execNDJSON[T any](t *testing.T, target string, status int) (*httptest.ResponseRecorder, []T) {
+	t.Helper()
+	req := httptest.NewRequest("GET", target, nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	w := httptest.NewRecorder()
+	setupTestRouter(database).ServeHTTP(w, req)
+	assert.Equal(t, status, w.Code)
+
+	records := []T{}
+
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for scanner.Scan() {
+		var r T
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r), "Error decoding line %q", scanner.Text())
+		records = append(records, r)
+	}
+
+	return w, records
+}
+
+// GET /books with Accept: application/x-ndjson
+func TestListBooks_NDJSON(t *testing.T) {
+	var rBooks []models.Book
+	execAndCheck(t, "GET", "/api/v1/books?sort_by=-year,title", nil, http.StatusOK, &rBooks)
+
+	w, streamed := execNDJSON[models.Book](t, "/api/v1/books?sort_by=-year,title", http.StatusOK)
+
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, rBooks, streamed)
+	assert.Empty(t, w.Result().Trailer.Get("X-Stream-Error"))
+}
+
+func TestListBooks_NDJSONFields(t *testing.T) {
+	_, streamed := execNDJSON[map[string]any](t, "/api/v1/books?fields=title&limit=2", http.StatusOK)
+
+	if assert.Len(t, streamed, 2) {
+		for _, r := range streamed {
+			assert.Len(t, r, 1)
+			assert.Contains(t, r, "title")
+		}
+	}
+}
+
+// GET /authors/:id/books?extend=true with Accept: application/x-ndjson
+func TestListAuthorBooks_NDJSONExtended(t *testing.T) {
+	_, streamed := execNDJSON[models.BookExt](t, "/api/v1/authors/5/books?extend=true", http.StatusOK)
+
+	if assert.Len(t, streamed, 3) {
+		for _, b := range streamed {
+			assert.Equal(t, "Lem", b.Author.LastName)
+		}
+	}
+}
+
+// GET /languages with Accept: application/x-ndjson
+func TestListLanguages_NDJSONEmpty(t *testing.T) {
+	w, streamed := execNDJSON[models.Language](t, "/api/v1/languages?name=Brak", http.StatusOK)
+
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Empty(t, streamed)
+}
+
+func TestListNDJSON_Error(t *testing.T) {
+	ndjsonTests := map[string]struct {
+		query  string
+		status int
+	}{
+		"BadRequest_Expand":   {"books?expand=author", http.StatusBadRequest},
+		"BadRequest_Group":    {"authors?group_by=death_year", http.StatusBadRequest},
+		"BadRequest_Envelope": {"genres?envelope=true", http.StatusBadRequest},
+		"BadRequest_Filter":   {"languages?isbn=1", http.StatusBadRequest},
+		"NotFound_Parent":     {"authors/999/books", http.StatusNotFound},
+	}
+
+	for name, tt := range ndjsonTests {
+		t.Run(name, func(t *testing.T) {
+			_, streamed := execNDJSON[models.Problem](t, "/api/v1/"+tt.query, tt.status)
+
+			if assert.Len(t, streamed, 1, "The error should be returned as the whole body") {
+				assert.NotEmpty(t, streamed[0].Detail)
+			}
+		})
+	}
+}
+
+// failingStream is a database whose stream of genres fails after yielding two of them.
+type failingStream struct {
+	db.DatabaseInterface
+}
+
+func (f failingStream) StreamGenres(ctx context.Context, params url.Values) iter.Seq2[models.Genre, error] {
+	return func(yield func(models.Genre, error) bool) {
+		_ = yield(models.Genre{ID: 1, Name: "Nowela"}, nil) &&
+			yield(models.Genre{ID: 2, Name: "Epopeja"}, nil) &&
+			yield(models.Genre{}, errors.New("connection lost"))
+	}
+}
+
+func TestListGenres_NDJSONFailure(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/genres", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	w := httptest.NewRecorder()
+	setupTestRouter(failingStream{database}).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "The status is sent with the first record")
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.JSONEq(t, `{"id":2,"name":"Epopeja"}`, lines[1])
+		assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An Internal Server Error occurred","instance":"/api/v1/genres"}`, lines[2])
+	}
+
+	assert.Equal(t, "An Internal Server Error occurred", w.Result().Trailer.Get("X-Stream-Error"))
+}
+
+// deadlineRecorder is a recorder which records the write deadlines set on it.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (r *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	r.deadlines = append(r.deadlines, t)
+	return nil
+}
+
+func TestList_StreamExtendsWriteDeadline(t *testing.T) {
+	streamTests := map[string]string{
+		"NDJSON": "application/x-ndjson",
+		"CSV":    "text/csv",
+	}
+
+	for name, accept := range streamTests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/books", nil)
+			req.Header.Set("Accept", accept)
+
+			w := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+			start := time.Now()
+			setupTestRouter(database).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if assert.NotEmpty(t, w.deadlines, "The write deadline should be extended") {
+				assert.True(t, w.deadlines[0].After(start.Add(time.Second)))
+			}
+		})
+	}
+}
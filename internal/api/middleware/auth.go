@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"pawrest/internal/api/handler"
 )
 
 func Authenticate(secret string) gin.HandlerFunc {
@@ -14,13 +15,13 @@ func Authenticate(secret string) gin.HandlerFunc {
 		headerToken := c.GetHeader("Authorization")
 
 		if headerToken == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No token provided"})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, "No token provided")
 			return
 		}
 
 		userToken, ok := strings.CutPrefix(headerToken, "Bearer ")
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No Bearer prefix in Authorization header"})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, "No Bearer prefix in Authorization header")
 			return
 		}
 
@@ -42,13 +43,13 @@ func Authenticate(secret string) gin.HandlerFunc {
 				errorMsg = "Token verification failed"
 			}
 
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": errorMsg})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, errorMsg)
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unable to parse token claims"})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, "Unable to parse token claims")
 			return
 		}
 
@@ -61,18 +62,18 @@ func Authorize() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userClaims, ok := c.Get("user")
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User authentication data not found"})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, "User authentication data not found")
 			return
 		}
 
 		claims, ok := userClaims.(jwt.MapClaims)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unable to parse token claims"})
+			handler.AbortWithProblem(c, http.StatusUnauthorized, "Unable to parse token claims")
 			return
 		}
 
 		if isAdmin := claims["admin"]; isAdmin != true {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You do not have sufficient permissions to access this resource"})
+			handler.AbortWithProblem(c, http.StatusForbidden, "You do not have sufficient permissions to access this resource")
 			return
 		}
 
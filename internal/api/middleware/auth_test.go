@@ -105,12 +105,12 @@ func TestAuthentication(t *testing.T) {
 			assert.Equal(t, tt.status, w.Code)
 
 			if tt.errMsg != "" {
-				var rError models.Error
+				var rError models.Problem
 				err := json.NewDecoder(w.Body).Decode(&rError)
 				assert.NoError(t, err)
 
 				assert.NotEmpty(t, rError)
-				assert.Equal(t, tt.errMsg, rError.Error)
+				assert.Equal(t, tt.errMsg, rError.Detail)
 			} else {
 				var rResp map[string]string
 				err := json.NewDecoder(w.Body).Decode(&rResp)
@@ -156,12 +156,12 @@ func TestAuthorization(t *testing.T) {
 			assert.Equal(t, tt.status, w.Code)
 
 			if tt.errMsg != "" {
-				var rError models.Error
+				var rError models.Problem
 				err := json.NewDecoder(w.Body).Decode(&rError)
 				assert.NoError(t, err)
 
 				assert.NotEmpty(t, rError)
-				assert.Equal(t, tt.errMsg, rError.Error)
+				assert.Equal(t, tt.errMsg, rError.Detail)
 			} else {
 				var rResp map[string]string
 				err := json.NewDecoder(w.Body).Decode(&rResp)
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pawrest/internal/api/handler"
+)
+
+// LiteralColon matches the wildcard param as the literal text ":" + param.
+// gin 1.10 can't register paths with a literal colon, such as /books:batch,
+// so they're registered as a wildcard, which also matches /books:other and
+// /booksother. Put first in the chain, it responds to these with 404 before
+// any other middleware runs, like to any unknown path.
+func LiteralColon(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(param) != ":"+param {
+			handler.AbortWithProblem(c, http.StatusNotFound, "No resource found")
+			return
+		}
+
+		c.Next()
+	}
+}
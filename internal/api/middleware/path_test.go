@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/api/middleware"
+)
+
+func TestLiteralColon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/books", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/books:batch", middleware.LiteralColon("batch"), middleware.Authenticate("secret"), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	pathTests := map[string]struct {
+		target string
+		status int
+	}{
+		"Literal":       {"/books:batch", http.StatusUnauthorized},
+		"OtherSuffix":   {"/books:other", http.StatusNotFound},
+		"NoColon":       {"/booksbatch", http.StatusNotFound},
+		"LongerLiteral": {"/books:batches", http.StatusNotFound},
+	}
+
+	for name, tt := range pathTests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", tt.target, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
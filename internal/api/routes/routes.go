@@ -15,6 +15,12 @@ import (
 // @title		Book managing API
 // @description	Documentation of a book managing REST API.
 // @description
+// @description	**How to read errors:**
+// @description	Errors are RFC 7807 problem details (`application/problem+json`) with the `status`, its `title`, a `detail` message
+// @description	and the `instance` path of the request. A body failing validation lists each `field` and `rule` it violates in `errors`.
+// @description	Rules: `required`, `min`/`max` (numbers, or lengths of texts), `past`, `gtefield` (not less than another field),
+// @description	`afterbirth` (a book isn't older than its author) and `beforebooks` (an author isn't younger than their books).
+// @description
 // @description	**How to use filtering:**
 // @description	To use simple filtering put name of the column in the query parameter followed by the value.
 // @description	Examples: `last_name=Orwell`, `title=Dziady`
@@ -34,12 +40,60 @@ import (
 // @description	To sort, use `sort_by` query parameter followed by the column name.
 // @description	If you want to sort in descending order, prefix the column name with a minus sign (`-`).
 // @description	Examples: `sort_by=pages` - ascending order, `sort_by=-pages` - descending order
+// @description	To sort by more columns, separate them with commas: `sort_by=-year,title`
+// @description	NULL values come first in ascending and last in descending order.
+// @description	To change it, suffix the column with `.nullsfirst` or `.nullslast`: `sort_by=death_year.nullslast`
 // @description
 // @description	**How to use limit and offset:**
 // @description	To use limit, use the `limit` query parameter, like this: `limit=10`
 // @description	To use offset, you also need to provide a limit.
 // @description	The order of the limit and offset parameters doesn't matter.
 // @description	Examples: `offset=10&limit=50`, `limit=50&offset=10`
+// @description
+// @description	**How to use cursors:**
+// @description	Full pages of a limited list come with a cursor in the `X-Next-Cursor` header.
+// @description	Pass it in the `after` parameter (instead of `offset`) with the same `sort_by` to get the next page.
+// @description	Example: `sort_by=-year&limit=50&after=<cursor>`
+// @description
+// @description	**How to select fields:**
+// @description	To get only some of the fields, list them in the `fields` query parameter.
+// @description	Examples: `fields=id,title`, `extend=true&fields=title,author.last_name`
+// @description
+// @description	**How to expand relations:**
+// @description	To embed related resources instead of their ids, list them in the `expand` query parameter.
+// @description	Books can expand `author`, `genre` and `language`, while authors, genres and languages can expand `books`.
+// @description	Examples: `/books?expand=author,genre`, `/books/7?expand=language`, `/authors/5?expand=books`
+// @description
+// @description	**How to group and aggregate:**
+// @description	To get groups instead of the records, list the fields to group by in the `group_by` query parameter
+// @description	and the aggregates in the `agg` parameter: `count`, `sum(field)`, `avg(field)`, `min(field)`, `max(field)`.
+// @description	Groups can be filtered by the aggregates with `having.<aggregate>.<operator>` and sorted by them with `sort_by`.
+// @description	Example: `/books?group_by=genre.name&agg=count,avg(pages)&having.count.gte=2&sort_by=-count`
+// @description
+// @description	**How to use batches:**
+// @description	To create, update or delete many records at once, send an array of them (or of ids to delete) to `/<resource>:batch`.
+// @description	A batch is all-or-nothing, unless `atomic=false` is set, in which case the valid items are applied and the response is 207.
+// @description	Example: `POST /genres:batch?atomic=false` with `[{"name":"Fraszka"},{"name":""}]`
+// @description
+// @description	**How to patch:**
+// @description	Send a JSON merge patch (`Content-Type: application/merge-patch+json`), where `null` clears a field,
+// @description	or a JSON patch (`Content-Type: application/json-patch+json`), whose `test` operations respond with 409 when they fail.
+// @description	Example: `PATCH /authors/5` with `[{"op":"test","path":"/death_year","value":2006},{"op":"remove","path":"/death_year"}]`
+// @description	Other formats are partial records, which only update the fields they set.
+// @description
+// @description	**How to choose a format:**
+// @description	Responses are written as JSON, XML, YAML or MessagePack, as named by the `Accept` header, and 406 otherwise.
+// @description	Request bodies are read in the format named by the `Content-Type` header, JSON when it's not set, and 415 otherwise.
+// @description	Example: `Accept: application/yaml` or `Content-Type: application/xml` with `<genre><name>Fraszka</name></genre>`
+// @description
+// @description	**How to stream:**
+// @description	To get a list as newline delimited JSON, written record by record, send the `Accept: application/x-ndjson` header.
+// @description	A stream which fails midway ends with an error line and sets the `X-Stream-Error` trailer.
+// @description
+// @description	**How to use CSV:**
+// @description	To export a list as CSV, add `format=csv` to its parameters: `/books?format=csv&extend=true`
+// @description	To import books, send a CSV with a header row naming the columns like the parameters of extended books to `/books/import`.
+// @description	Authors, genres and languages are looked up by name, and created if missing with `create=true`.
 
 // @BasePath	/api/v1
 
@@ -52,10 +106,10 @@ import (
 // @externalDocs.description	OpenAPI Specification
 // @externalDocs.url			https://swagger.io/resources/open-api/
 func Router(router *gin.Engine, db db.DatabaseInterface, cfg *yamlconfig.Config) {
-	h := handler.Handlers{DB: db}
 	secret := cfg.Secret
+	h := handler.Handlers{DB: db, CursorKey: handler.CursorKey(secret)}
 
-	api := router.Group("/api")
+	api := router.Group("/api", handler.Negotiate)
 	{
 		v1 := api.Group("/v1")
 		{
@@ -65,10 +119,12 @@ func Router(router *gin.Engine, db db.DatabaseInterface, cfg *yamlconfig.Config)
 				books.GET("/:id", h.GetBook)
 				books.OPTIONS("", h.OptionsBooks)
 				books.OPTIONS("/:id", h.OptionsBook)
+				books.OPTIONS("/import", h.OptionsBooksImport)
 
 				admin := books.Group("", middleware.Authorize())
 				{
 					admin.POST("", h.PostBook)
+					admin.POST("/import", h.ImportBooks)
 					admin.PUT("/:id", h.PutBook)
 					admin.PATCH("/:id", h.PatchBook)
 					admin.DELETE("/:id", h.DeleteBook)
@@ -81,6 +137,8 @@ func Router(router *gin.Engine, db db.DatabaseInterface, cfg *yamlconfig.Config)
 				authors.GET("/:id", h.GetAuthor)
 				authors.OPTIONS("", h.OptionsAuthors)
 				authors.OPTIONS("/:id", h.OptionsAuthor)
+				authors.GET("/:id/books", h.GetAuthorBooks)
+				authors.OPTIONS("/:id/books", h.OptionsAuthorBooks)
 
 				admin := authors.Group("", middleware.Authorize())
 				{
@@ -97,11 +155,14 @@ func Router(router *gin.Engine, db db.DatabaseInterface, cfg *yamlconfig.Config)
 				genres.GET("/:id", h.GetGenre)
 				genres.OPTIONS("", h.OptionsGenres)
 				genres.OPTIONS("/:id", h.OptionsGenre)
+				genres.GET("/:id/books", h.GetGenreBooks)
+				genres.OPTIONS("/:id/books", h.OptionsGenreBooks)
 
 				admin := genres.Group("", middleware.Authorize())
 				{
 					admin.POST("", h.PostGenre)
 					admin.PUT("/:id", h.PutGenre)
+					admin.PATCH("/:id", h.PatchGenre)
 					admin.DELETE("/:id", h.DeleteGenre)
 				}
 			}
@@ -112,15 +173,68 @@ func Router(router *gin.Engine, db db.DatabaseInterface, cfg *yamlconfig.Config)
 				languages.GET("/:id", h.GetLanguage)
 				languages.OPTIONS("", h.OptionsLanguages)
 				languages.OPTIONS("/:id", h.OptionsLanguage)
+				languages.GET("/:id/books", h.GetLanguageBooks)
+				languages.OPTIONS("/:id/books", h.OptionsLanguageBooks)
 
 				admin := languages.Group("", middleware.Authorize())
 				{
 					admin.POST("", h.PostLanguage)
 					admin.PUT("/:id", h.PutLanguage)
+					admin.PATCH("/:id", h.PatchLanguage)
 					admin.DELETE("/:id", h.DeleteLanguage)
 				}
 			}
 
+			// gin registers the :batch paths as a wildcard, so they can't be put
+			// in the groups of the resources, and other suffixes are rejected
+			// before authentication.
+			batch := v1.Group("", middleware.LiteralColon("batch"), middleware.Authenticate(secret))
+			{
+				batch.OPTIONS("/books:batch", h.OptionsBatch)
+				batch.OPTIONS("/authors:batch", h.OptionsBatch)
+				batch.OPTIONS("/genres:batch", h.OptionsBatch)
+				batch.OPTIONS("/languages:batch", h.OptionsBatch)
+
+				admin := batch.Group("", middleware.Authorize())
+				{
+					admin.POST("/books:batch", h.PostBooksBatch)
+					admin.PATCH("/books:batch", h.PatchBooksBatch)
+					admin.DELETE("/books:batch", h.DeleteBooksBatch)
+					admin.POST("/authors:batch", h.PostAuthorsBatch)
+					admin.PATCH("/authors:batch", h.PatchAuthorsBatch)
+					admin.DELETE("/authors:batch", h.DeleteAuthorsBatch)
+					admin.POST("/genres:batch", h.PostGenresBatch)
+					admin.PATCH("/genres:batch", h.PatchGenresBatch)
+					admin.DELETE("/genres:batch", h.DeleteGenresBatch)
+					admin.POST("/languages:batch", h.PostLanguagesBatch)
+					admin.PATCH("/languages:batch", h.PatchLanguagesBatch)
+					admin.DELETE("/languages:batch", h.DeleteLanguagesBatch)
+				}
+			}
+
+			search := v1.Group("/search", middleware.Authenticate(secret))
+			{
+				search.GET("", h.Search)
+				search.OPTIONS("", h.OptionsSearch)
+			}
+
+			stats := v1.Group("/stats", middleware.Authenticate(secret))
+			{
+				stats.GET("", h.GetStats)
+				stats.GET("/genres", h.GetStatsGenres)
+				stats.GET("/languages", h.GetStatsLanguages)
+				stats.GET("/authors", h.GetStatsAuthors)
+				stats.GET("/decades", h.GetStatsDecades)
+				stats.GET("/pages", h.GetStatsPages)
+
+				stats.OPTIONS("", h.OptionsStats)
+				stats.OPTIONS("/genres", h.OptionsStats)
+				stats.OPTIONS("/languages", h.OptionsStats)
+				stats.OPTIONS("/authors", h.OptionsStats)
+				stats.OPTIONS("/decades", h.OptionsStats)
+				stats.OPTIONS("/pages", h.OptionsStats)
+			}
+
 			v1.POST("login", handler.ReturnToken(secret))
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +29,9 @@ var authRouteTests = []struct {
 	{"DELETE", "/api/v1/books/2", nil},
 	{"OPTIONS", "/api/v1/books", nil},
 	{"OPTIONS", "/api/v1/books/1", nil},
+	{"GET", "/api/v1/books?format=csv", nil},
+	{"POST", "/api/v1/books/import", []byte("title,year,pages,author.first_name,author.last_name,genre.name,language.name\nRoute import test,1996,200,John,Doe,Nowela,Polski\n")},
+	{"OPTIONS", "/api/v1/books/import", nil},
 
 	{"GET", "/api/v1/authors", nil},
 	{"GET", "/api/v1/authors/1", nil},
@@ -37,6 +41,8 @@ var authRouteTests = []struct {
 	{"DELETE", "/api/v1/authors/2", nil},
 	{"OPTIONS", "/api/v1/authors", nil},
 	{"OPTIONS", "/api/v1/authors/1", nil},
+	{"GET", "/api/v1/authors/1/books", nil},
+	{"OPTIONS", "/api/v1/authors/1/books", nil},
 
 	{"GET", "/api/v1/genres", nil},
 	{"GET", "/api/v1/genres/1", nil},
@@ -45,6 +51,8 @@ var authRouteTests = []struct {
 	{"DELETE", "/api/v1/genres/2", nil},
 	{"OPTIONS", "/api/v1/genres", nil},
 	{"OPTIONS", "/api/v1/genres/1", nil},
+	{"GET", "/api/v1/genres/1/books", nil},
+	{"OPTIONS", "/api/v1/genres/1/books", nil},
 
 	{"GET", "/api/v1/languages", nil},
 	{"GET", "/api/v1/languages/1", nil},
@@ -53,6 +61,25 @@ var authRouteTests = []struct {
 	{"DELETE", "/api/v1/languages/2", nil},
 	{"OPTIONS", "/api/v1/languages", nil},
 	{"OPTIONS", "/api/v1/languages/1", nil},
+	{"GET", "/api/v1/languages/1/books", nil},
+	{"OPTIONS", "/api/v1/languages/1/books", nil},
+
+	{"POST", "/api/v1/books:batch", []byte(`[{"title":"Route batch test","year":1996,"pages":200,"author":1,"genre":1,"language":1}]`)},
+	{"PATCH", "/api/v1/books:batch", []byte(`[{"id":1,"title":"Route batch patch"}]`)},
+	{"DELETE", "/api/v1/books:batch", []byte(`[3]`)},
+	{"OPTIONS", "/api/v1/books:batch", nil},
+	{"POST", "/api/v1/authors:batch", []byte(`[{"first_name":"Route batch", "last_name":"test"}]`)},
+	{"POST", "/api/v1/genres:batch", []byte(`[{"name":"Route batch"}]`)},
+	{"POST", "/api/v1/languages:batch", []byte(`[{"name":"Route batch"}]`)},
+	{"OPTIONS", "/api/v1/languages:batch", nil},
+
+	{"GET", "/api/v1/search?q=Lem", nil},
+	{"OPTIONS", "/api/v1/search", nil},
+
+	{"GET", "/api/v1/stats", nil},
+	{"GET", "/api/v1/stats/genres?year.gte=1900", nil},
+	{"GET", "/api/v1/stats/pages", nil},
+	{"OPTIONS", "/api/v1/stats/decades", nil},
 }
 
 func setupTestRouter() *gin.Engine {
@@ -128,6 +155,17 @@ func TestRoutes_NoToken(t *testing.T) {
 	}
 }
 
+func TestRoutes_BatchOtherSuffix(t *testing.T) {
+	router := setupTestRouter()
+
+	for _, endpoint := range []string{"/api/v1/books:other", "/api/v1/genresbatch", "/api/v1/languages:batches"} {
+		t.Run(endpoint, func(t *testing.T) {
+			w := execRequest(router, "POST", endpoint, nil, "")
+			assert.Equal(t, http.StatusNotFound, w.Code, "Unknown paths should be rejected before authentication")
+		})
+	}
+}
+
 func TestRoutes_NonAdminToken(t *testing.T) {
 	router := setupTestRouter()
 	token, ok := getToken(t, router, false)
@@ -167,7 +205,14 @@ func TestRoutes_AdminToken(t *testing.T) {
 				assert.Equal(t, http.StatusOK, w.Code)
 			case "POST":
 				assert.Equal(t, http.StatusCreated, w.Code)
-			case "PUT", "PATCH", "DELETE", "OPTIONS":
+			case "PATCH", "DELETE":
+				if strings.HasSuffix(tt.endpoint, ":batch") {
+					assert.Equal(t, http.StatusOK, w.Code)
+					return
+				}
+
+				assert.Equal(t, http.StatusNoContent, w.Code)
+			case "PUT", "OPTIONS":
 				assert.Equal(t, http.StatusNoContent, w.Code)
 			}
 		})
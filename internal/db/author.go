@@ -1,69 +1,111 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
 	"net/url"
 
 	"pawrest/internal/models"
 )
 
 type AuthorDatabaseInterface interface {
-	GetAuthors(params url.Values) ([]models.Author, error)
-	GetAuthor(id int64) (models.Author, error)
-	InsertAuthor(a models.Author) (int64, error)
-	UpdateWholeAuthor(id int64, a models.Author) error
-	UpdateAuthor(id int64, a models.Author) error
-	DelAuthor(id int64) error
+	GetAuthors(ctx context.Context, params url.Values) ([]models.Author, error)
+	// StreamAuthors is GetAuthors yielding the records one at a time.
+	StreamAuthors(ctx context.Context, params url.Values) iter.Seq2[models.Author, error]
+	CountAuthors(ctx context.Context, params url.Values) (int64, error)
+	GetAuthor(ctx context.Context, id int64) (models.Author, error)
+	// GetAuthorByName returns the author with the first and last names,
+	// ignoring case, the first one if there are many.
+	GetAuthorByName(ctx context.Context, firstName, lastName string) (models.Author, error)
+	InsertAuthor(ctx context.Context, a models.Author) (int64, error)
+	// InsertAuthors inserts authors with a single statement and returns their ids in order.
+	InsertAuthors(ctx context.Context, authors []models.Author) ([]int64, error)
+	UpdateWholeAuthor(ctx context.Context, id int64, a models.Author) error
+	UpdateAuthor(ctx context.Context, id int64, a models.Author) error
+	DelAuthor(ctx context.Context, id int64) error
 }
 
-func (d *Database) GetAuthors(params url.Values) ([]models.Author, error) {
+// AllowedAuthorParams maps the author query parameters to autor columns.
+var AllowedAuthorParams = map[string]string{
+	"id":         "id",
+	"first_name": "imie",
+	"last_name":  "nazwisko",
+	"birth_year": "rok_urodzenia",
+	"death_year": "rok_smierci",
+}
+
+// authorColumns lists the autor columns in the order they're selected in.
+var authorColumns = []column[models.Author]{
+	{"id", func(a *models.Author) any { return &a.ID }},
+	{"first_name", func(a *models.Author) any { return &a.FirstName }},
+	{"last_name", func(a *models.Author) any { return &a.LastName }},
+	{"birth_year", func(a *models.Author) any { return &a.BirthYear }},
+	{"death_year", func(a *models.Author) any { return &a.DeathYear }},
+}
+
+func (d *Database) GetAuthors(ctx context.Context, params url.Values) ([]models.Author, error) {
+	return queryWithParams(ctx, d, "autor", params, AllowedAuthorParams, authorColumns)
+}
+
+func (d *Database) StreamAuthors(ctx context.Context, params url.Values) iter.Seq2[models.Author, error] {
+	return streamWithParams(ctx, d, "autor", params, AllowedAuthorParams, authorColumns)
+}
+
+func (d *Database) CountAuthors(ctx context.Context, params url.Values) (int64, error) {
+	return countWithParams(ctx, d, "autor", params, AllowedAuthorParams)
+}
+
+func (d *Database) GetAuthor(ctx context.Context, id int64) (models.Author, error) {
 	query := `
 	SELECT id, imie, nazwisko, rok_urodzenia, rok_smierci
-	FROM autor`
-
-	allowedParams := map[string]string{
-		"id":         "id",
-		"first_name": "imie",
-		"last_name":  "nazwisko",
-		"birth_year": "rok_urodzenia",
-		"death_year": "rok_smierci",
-	}
+	FROM autor
+	WHERE id = ?`
 
-	authorFunc := func(a *models.Author, rows *sql.Rows) error {
-		return rows.Scan(&a.ID, &a.FirstName, &a.LastName, &a.BirthYear, &a.DeathYear)
+	authorFunc := func(a *models.Author, row *sql.Row) error {
+		return row.Scan(&a.ID, &a.FirstName, &a.LastName, &a.BirthYear, &a.DeathYear)
 	}
 
-	return queryWithParams[models.Author](
-		d,
-		query,
-		params,
-		allowedParams,
-		authorFunc,
-	)
+	return queryID[models.Author](ctx, d, query, id, authorFunc)
 }
 
-func (d *Database) GetAuthor(id int64) (models.Author, error) {
+func (d *Database) GetAuthorByName(ctx context.Context, firstName, lastName string) (models.Author, error) {
 	query := `
 	SELECT id, imie, nazwisko, rok_urodzenia, rok_smierci
 	FROM autor
-	WHERE id = ?`
+	WHERE LOWER(imie) = LOWER(?) AND LOWER(nazwisko) = LOWER(?)
+	ORDER BY id
+	LIMIT 1`
 
 	authorFunc := func(a *models.Author, row *sql.Row) error {
 		return row.Scan(&a.ID, &a.FirstName, &a.LastName, &a.BirthYear, &a.DeathYear)
 	}
 
-	return queryID[models.Author](d, query, id, authorFunc)
+	return queryOne(ctx, d, fmt.Sprintf("named %q", firstName+" "+lastName), query, authorFunc, firstName, lastName)
 }
 
-func (d *Database) InsertAuthor(a models.Author) (int64, error) {
+func (d *Database) InsertAuthor(ctx context.Context, a models.Author) (int64, error) {
 	query := `
 	INSERT INTO autor (imie, nazwisko, rok_urodzenia, rok_smierci)
 	VALUES (?, ?, ?, ?)`
 
-	return d.insert(query, a.FirstName, a.LastName, a.BirthYear, a.DeathYear)
+	return d.insert(ctx, query, a.FirstName, a.LastName, a.BirthYear, a.DeathYear)
+}
+
+func (d *Database) InsertAuthors(ctx context.Context, authors []models.Author) ([]int64, error) {
+	columns := []string{"imie", "nazwisko", "rok_urodzenia", "rok_smierci"}
+
+	rows := make([][]any, len(authors))
+	for i, a := range authors {
+		rows[i] = []any{a.FirstName, a.LastName, a.BirthYear, a.DeathYear}
+	}
+
+	return d.insertMany(ctx, "autor", columns, rows)
 }
 
-func (d *Database) UpdateWholeAuthor(id int64, a models.Author) error {
+func (d *Database) UpdateWholeAuthor(ctx context.Context, id int64, a models.Author) error {
 	query := `
 	UPDATE autor
 	SET
@@ -73,10 +115,16 @@ func (d *Database) UpdateWholeAuthor(id int64, a models.Author) error {
 		rok_smierci = ?
 	WHERE id = ?`
 
-	return d.updateWholeID(query, a.FirstName, a.LastName, a.BirthYear, a.DeathYear, id)
+	return d.inTx(ctx, func(tx *Database) error {
+		if err := tx.checkBirthYear(ctx, id, a.BirthYear); err != nil {
+			return err
+		}
+
+		return tx.updateWholeID(ctx, query, a.FirstName, a.LastName, a.BirthYear, a.DeathYear, id)
+	})
 }
 
-func (d *Database) UpdateAuthor(id int64, a models.Author) error {
+func (d *Database) UpdateAuthor(ctx context.Context, id int64, a models.Author) error {
 	fieldToDB := map[string]string{
 		"FirstName": "imie",
 		"LastName":  "nazwisko",
@@ -84,11 +132,97 @@ func (d *Database) UpdateAuthor(id int64, a models.Author) error {
 		"DeathYear": "rok_smierci",
 	}
 
-	return d.updatePartID(a, "autor", id, fieldToDB)
+	return d.inTx(ctx, func(tx *Database) error {
+		// The years of a partial update are checked against each other
+		// along with the stored ones.
+		if a.BirthYear != 0 || a.DeathYear != nil {
+			current, err := tx.GetAuthor(ctx, id)
+
+			switch {
+			case errors.Is(err, ErrNotFound):
+				// The update reports it.
+			case err != nil:
+				return err
+			default:
+				merged := mergeAuthor(current, a)
+				if v := merged.Validate(); v != nil {
+					return fmt.Errorf("%w: %w", ErrParam, v)
+				}
+			}
+		}
+
+		if a.BirthYear != 0 {
+			if err := tx.checkBirthYear(ctx, id, a.BirthYear); err != nil {
+				return err
+			}
+		}
+
+		return tx.updatePartID(ctx, a, "autor", id, fieldToDB)
+	})
 }
 
-func (d *Database) DelAuthor(id int64) error {
+func (d *Database) DelAuthor(ctx context.Context, id int64) error {
 	query := "DELETE FROM autor WHERE id = ?"
 
-	return d.deleteID(query, id)
+	return d.deleteID(ctx, query, id)
+}
+
+// mergeAuthor returns the author a with the fields set by
+// the partial update u replaced.
+func mergeAuthor(a, u models.Author) models.Author {
+	if u.FirstName != "" {
+		a.FirstName = u.FirstName
+	}
+	if u.LastName != "" {
+		a.LastName = u.LastName
+	}
+	if u.BirthYear != 0 {
+		a.BirthYear = u.BirthYear
+	}
+	if u.DeathYear != nil {
+		a.DeathYear = models.I64Ptr(*u.DeathYear)
+	}
+
+	return a
+}
+
+// checkBirthYear checks that the author with id, born in birthYear,
+// isn't younger than the oldest of their books. Inside a transaction the
+// author is locked until it ends, which checkBookYear waits for, so that
+// no older book can be written for them in the meantime.
+func (d *Database) checkBirthYear(ctx context.Context, id, birthYear int64) error {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	if lock := d.lockRows(); lock != "" {
+		var locked int64
+
+		query := "SELECT id FROM autor WHERE id = ?" + lock
+
+		err := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), id).Scan(&locked)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil
+		case err != nil:
+			return fmt.Errorf("Query error (%w)", err)
+		}
+	}
+
+	var firstBook sql.NullInt64
+
+	query := "SELECT MIN(rok_wydania) FROM ksiazka WHERE id_autora = ?"
+
+	if err := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), id).Scan(&firstBook); err != nil {
+		return fmt.Errorf("Query error (%w)", err)
+	}
+
+	if !firstBook.Valid {
+		return nil
+	}
+
+	if v := models.ValidateBirthYear(birthYear, firstBook.Int64); v != nil {
+		return fmt.Errorf("%w: %w", ErrParam, v)
+	}
+
+	return nil
 }
@@ -1,122 +1,127 @@
 package db
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
 	"net/url"
+	"strings"
 
 	"pawrest/internal/models"
 )
 
 type BookDatabaseInterface interface {
-	GetBooks(params url.Values) ([]models.Book, error)
-	GetBooksExt(params url.Values) ([]models.BookExt, error)
-	GetBook(id int64) (models.Book, error)
-	InsertBook(b models.Book) (int64, error)
-	UpdateWholeBook(id int64, b models.Book) error
-	UpdateBook(id int64, b models.Book) error
-	DelBook(id int64) error
+	GetBooks(ctx context.Context, params url.Values) ([]models.Book, error)
+	GetBooksExt(ctx context.Context, params url.Values) ([]models.BookExt, error)
+	// StreamBooks is GetBooks yielding the books one at a time, as they're
+	// read from the database, instead of collecting all of them first.
+	StreamBooks(ctx context.Context, params url.Values) iter.Seq2[models.Book, error]
+	StreamBooksExt(ctx context.Context, params url.Values) iter.Seq2[models.BookExt, error]
+	// CountBooks returns the number of books matching the filtering
+	// conditions in params, ignoring the sorting and pagination.
+	CountBooks(ctx context.Context, params url.Values) (int64, error)
+	CountBooksExt(ctx context.Context, params url.Values) (int64, error)
+	GetBook(ctx context.Context, id int64) (models.Book, error)
+	InsertBook(ctx context.Context, b models.Book) (int64, error)
+	// InsertBooks inserts books with a single statement, so that either all
+	// or none of them are inserted, and returns their ids in order.
+	InsertBooks(ctx context.Context, books []models.Book) ([]int64, error)
+	UpdateWholeBook(ctx context.Context, id int64, b models.Book) error
+	UpdateBook(ctx context.Context, id int64, b models.Book) error
+	DelBook(ctx context.Context, id int64) error
 }
 
-func (d *Database) GetBooks(params url.Values) ([]models.Book, error) {
-	query := `
-	SELECT
-		id,
-		tytul,
-		rok_wydania,
-		liczba_stron,
-		id_autora,
-		id_gatunku,
-		id_jezyka
-	FROM ksiazka`
-
-	allowedParams := map[string]string{
-		"id":       "id",
-		"title":    "tytul",
-		"year":     "rok_wydania",
-		"pages":    "liczba_stron",
-		"author":   "id_autora",
-		"genre":    "id_gatunku",
-		"language": "id_jezyka",
-	}
+// AllowedBookParams maps the book query parameters to ksiazka columns.
+var AllowedBookParams = map[string]string{
+	"id":       "id",
+	"title":    "tytul",
+	"year":     "rok_wydania",
+	"pages":    "liczba_stron",
+	"author":   "id_autora",
+	"genre":    "id_gatunku",
+	"language": "id_jezyka",
+}
 
-	bookFunc := func(b *models.Book, rows *sql.Rows) error {
-		return rows.Scan(&b.ID, &b.Title, &b.Year, &b.Pages, &b.Author, &b.Genre, &b.Language)
-	}
+// bookColumns lists the ksiazka columns in the order they're selected in.
+var bookColumns = []column[models.Book]{
+	{"id", func(b *models.Book) any { return &b.ID }},
+	{"title", func(b *models.Book) any { return &b.Title }},
+	{"year", func(b *models.Book) any { return &b.Year }},
+	{"pages", func(b *models.Book) any { return &b.Pages }},
+	{"author", func(b *models.Book) any { return &b.Author }},
+	{"genre", func(b *models.Book) any { return &b.Genre }},
+	{"language", func(b *models.Book) any { return &b.Language }},
+}
 
-	return queryWithParams[models.Book](
-		d,
-		query,
-		params,
-		allowedParams,
-		bookFunc,
-	)
+func (d *Database) GetBooks(ctx context.Context, params url.Values) ([]models.Book, error) {
+	return queryWithParams(ctx, d, "ksiazka", params, AllowedBookParams, bookColumns)
 }
 
-func (d *Database) GetBooksExt(params url.Values) ([]models.BookExt, error) {
-	query := `
-	SELECT
-		k.id,
-		tytul,
-		rok_wydania,
-		liczba_stron,
-		id_autora,
-		a.imie,
-		a.nazwisko,
-		a.rok_urodzenia,
-		a.rok_smierci,
-		id_gatunku,
-		g.nazwa,
-		id_jezyka,
-		j.nazwa
-	FROM ksiazka k
+func (d *Database) StreamBooks(ctx context.Context, params url.Values) iter.Seq2[models.Book, error] {
+	return streamWithParams(ctx, d, "ksiazka", params, AllowedBookParams, bookColumns)
+}
+
+// AllowedBookExtParams maps the extended book query parameters to the joined columns.
+var AllowedBookExtParams = map[string]string{
+	"id":                "k.id",
+	"title":             "tytul",
+	"year":              "rok_wydania",
+	"pages":             "liczba_stron",
+	"author.id":         "id_autora",
+	"author.first_name": "a.imie",
+	"author.last_name":  "a.nazwisko",
+	"author.birth_year": "a.rok_urodzenia",
+	"author.death_year": "a.rok_smierci",
+	"genre.id":          "id_gatunku",
+	"genre.name":        "g.nazwa",
+	"language.id":       "id_jezyka",
+	"language.name":     "j.nazwa",
+}
+
+// bookExtTables joins ksiazka with the tables holding
+// the extended book information.
+const bookExtTables = `ksiazka k
 		JOIN autor a ON k.id_autora = a.id
 		JOIN gatunek g ON k.id_gatunku = g.id
 		JOIN jezyk j ON k.id_jezyka = j.id`
 
-	allowedParams := map[string]string{
-		"id":                "k.id",
-		"title":             "tytul",
-		"year":              "rok_wydania",
-		"pages":             "liczba_stron",
-		"author.id":         "id_autora",
-		"author.first_name": "a.imie",
-		"author.last_name":  "a.nazwisko",
-		"author.birth_year": "a.rok_urodzenia",
-		"author.death_year": "a.rok_smierci",
-		"genre.id":          "id_gatunku",
-		"genre.name":        "g.nazwa",
-		"language.id":       "id_jezyka",
-		"language.name":     "j.nazwa",
-	}
+// bookExtColumns lists the joined columns in the order they're selected in.
+var bookExtColumns = []column[models.BookExt]{
+	{"id", func(b *models.BookExt) any { return &b.ID }},
+	{"title", func(b *models.BookExt) any { return &b.Title }},
+	{"year", func(b *models.BookExt) any { return &b.Year }},
+	{"pages", func(b *models.BookExt) any { return &b.Pages }},
+	{"author.id", func(b *models.BookExt) any { return &b.Author.ID }},
+	{"author.first_name", func(b *models.BookExt) any { return &b.Author.FirstName }},
+	{"author.last_name", func(b *models.BookExt) any { return &b.Author.LastName }},
+	{"author.birth_year", func(b *models.BookExt) any { return &b.Author.BirthYear }},
+	{"author.death_year", func(b *models.BookExt) any { return &b.Author.DeathYear }},
+	{"genre.id", func(b *models.BookExt) any { return &b.Genre.ID }},
+	{"genre.name", func(b *models.BookExt) any { return &b.Genre.Name }},
+	{"language.id", func(b *models.BookExt) any { return &b.Language.ID }},
+	{"language.name", func(b *models.BookExt) any { return &b.Language.Name }},
+}
 
-	bookFunc := func(b *models.BookExt, rows *sql.Rows) error {
-		return rows.Scan(
-			&b.ID,
-			&b.Title,
-			&b.Year,
-			&b.Pages,
-			&b.Author.ID,
-			&b.Author.FirstName,
-			&b.Author.LastName,
-			&b.Author.BirthYear,
-			&b.Author.DeathYear,
-			&b.Genre.ID,
-			&b.Genre.Name,
-			&b.Language.ID,
-			&b.Language.Name,
-		)
-	}
+func (d *Database) GetBooksExt(ctx context.Context, params url.Values) ([]models.BookExt, error) {
+	return queryWithParams(ctx, d, bookExtTables, params, AllowedBookExtParams, bookExtColumns)
+}
 
-	return queryWithParams[models.BookExt](
-		d,
-		query,
-		params,
-		allowedParams,
-		bookFunc,
-	)
+func (d *Database) StreamBooksExt(ctx context.Context, params url.Values) iter.Seq2[models.BookExt, error] {
+	return streamWithParams(ctx, d, bookExtTables, params, AllowedBookExtParams, bookExtColumns)
+}
+
+func (d *Database) CountBooks(ctx context.Context, params url.Values) (int64, error) {
+	return countWithParams(ctx, d, "ksiazka", params, AllowedBookParams)
 }
 
-func (d *Database) GetBook(id int64) (models.Book, error) {
+func (d *Database) CountBooksExt(ctx context.Context, params url.Values) (int64, error) {
+	return countWithParams(ctx, d, bookExtTables, params, AllowedBookExtParams)
+}
+
+func (d *Database) GetBook(ctx context.Context, id int64) (models.Book, error) {
 	query := `
 	SELECT
 		id,
@@ -133,10 +138,10 @@ func (d *Database) GetBook(id int64) (models.Book, error) {
 		return row.Scan(&b.ID, &b.Title, &b.Year, &b.Pages, &b.Author, &b.Genre, &b.Language)
 	}
 
-	return queryID[models.Book](d, query, id, bookFunc)
+	return queryID[models.Book](ctx, d, query, id, bookFunc)
 }
 
-func (d *Database) InsertBook(b models.Book) (int64, error) {
+func (d *Database) InsertBook(ctx context.Context, b models.Book) (id int64, err error) {
 	query := `
 	INSERT INTO ksiazka (
 		tytul,
@@ -148,10 +153,39 @@ func (d *Database) InsertBook(b models.Book) (int64, error) {
 	)
 	VALUES (?, ?, ?, ?, ?, ?)`
 
-	return d.insert(query, b.Title, b.Year, b.Pages, b.Author, b.Genre, b.Language)
+	err = d.inTx(ctx, func(tx *Database) error {
+		if err := tx.checkBookYear(ctx, b.Year, b.Author); err != nil {
+			return err
+		}
+
+		id, err = tx.insert(ctx, query, b.Title, b.Year, b.Pages, b.Author, b.Genre, b.Language)
+		return err
+	})
+
+	return id, err
 }
 
-func (d *Database) UpdateWholeBook(id int64, b models.Book) error {
+func (d *Database) InsertBooks(ctx context.Context, books []models.Book) (ids []int64, err error) {
+	columns := []string{"tytul", "rok_wydania", "liczba_stron", "id_autora", "id_gatunku", "id_jezyka"}
+
+	rows := make([][]any, len(books))
+	for i, b := range books {
+		rows[i] = []any{b.Title, b.Year, b.Pages, b.Author, b.Genre, b.Language}
+	}
+
+	err = d.inTx(ctx, func(tx *Database) error {
+		if err := tx.checkBookYears(ctx, books); err != nil {
+			return err
+		}
+
+		ids, err = tx.insertMany(ctx, "ksiazka", columns, rows)
+		return err
+	})
+
+	return ids, err
+}
+
+func (d *Database) UpdateWholeBook(ctx context.Context, id int64, b models.Book) error {
 	query := `
 	UPDATE ksiazka
 	SET
@@ -163,10 +197,16 @@ func (d *Database) UpdateWholeBook(id int64, b models.Book) error {
 		id_jezyka = ?
 	WHERE id = ?`
 
-	return d.updateWholeID(query, b.Title, b.Year, b.Pages, b.Author, b.Genre, b.Language, id)
+	return d.inTx(ctx, func(tx *Database) error {
+		if err := tx.checkBookYear(ctx, b.Year, b.Author); err != nil {
+			return err
+		}
+
+		return tx.updateWholeID(ctx, query, b.Title, b.Year, b.Pages, b.Author, b.Genre, b.Language, id)
+	})
 }
 
-func (d *Database) UpdateBook(id int64, b models.Book) error {
+func (d *Database) UpdateBook(ctx context.Context, id int64, b models.Book) error {
 	fieldToDB := map[string]string{
 		"Title":    "tytul",
 		"Year":     "rok_wydania",
@@ -176,11 +216,101 @@ func (d *Database) UpdateBook(id int64, b models.Book) error {
 		"Language": "id_jezyka",
 	}
 
-	return d.updatePartID(b, "ksiazka", id, fieldToDB)
+	return d.inTx(ctx, func(tx *Database) error {
+		if b.Year != 0 || b.Author != 0 {
+			current, err := tx.GetBook(ctx, id)
+
+			switch {
+			case errors.Is(err, ErrNotFound):
+				// The update reports it.
+			case err != nil:
+				return err
+			default:
+				year, author := cmp.Or(b.Year, current.Year), cmp.Or(b.Author, current.Author)
+				if err := tx.checkBookYear(ctx, year, author); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.updatePartID(ctx, b, "ksiazka", id, fieldToDB)
+	})
 }
 
-func (d *Database) DelBook(id int64) error {
+func (d *Database) DelBook(ctx context.Context, id int64) error {
 	query := "DELETE FROM ksiazka WHERE id = ?"
 
-	return d.deleteID(query, id)
+	return d.deleteID(ctx, query, id)
+}
+
+// checkBookYear checks that a book published in year isn't older than
+// its author, leaving the authors which don't exist to the foreign key.
+// Inside a transaction the author is locked until it ends, so that their
+// birth year can't change before the book is written.
+func (d *Database) checkBookYear(ctx context.Context, year, author int64) error {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	var birthYear int64
+
+	query := "SELECT rok_urodzenia FROM autor WHERE id = ?" + d.lockRows()
+
+	err := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), author).Scan(&birthYear)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("Query error (%w)", err)
+	}
+
+	if v := models.ValidateBookYear(year, birthYear); v != nil {
+		return fmt.Errorf("%w: %w", ErrParam, v)
+	}
+
+	return nil
+}
+
+// checkBookYears is checkBookYear for many books, reading the birth years
+// of all of their authors with a single query.
+func (d *Database) checkBookYears(ctx context.Context, books []models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	var authors []any
+
+	seen := make(map[int64]bool)
+	for _, b := range books {
+		if !seen[b.Author] {
+			seen[b.Author] = true
+			authors = append(authors, b.Author)
+		}
+	}
+
+	query := "SELECT id, rok_urodzenia FROM autor WHERE id IN (?" +
+		strings.Repeat(", ?", len(authors)-1) + ")" + d.lockRows()
+
+	authorFunc := func(a *models.Author, rows *sql.Rows) error {
+		return rows.Scan(&a.ID, &a.BirthYear)
+	}
+
+	found, err := queryRows(ctx, d, query, authors, authorFunc)
+	if err != nil {
+		return err
+	}
+
+	birthYears := make(map[int64]int64, len(found))
+	for _, a := range found {
+		birthYears[a.ID] = a.BirthYear
+	}
+
+	// The missing authors are left to the foreign key, as their zero
+	// birth year is unknown.
+	for _, b := range books {
+		if v := models.ValidateBookYear(b.Year, birthYears[b.Author]); v != nil {
+			return fmt.Errorf("%w: %w", ErrParam, v)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Cursor is the position after which keyset pagination continues: the
+// values of the sorted fields and the id of the last record of a page.
+// It's only valid for requests sorted the same way as the one it came from.
+type Cursor struct {
+	Sort   string    `json:"s,omitempty"` // sort_by parameter formatted by FormatSort
+	Values []*string `json:"v,omitempty"` // value of each sort key, nil for NULL
+	ID     int64     `json:"i"`
+}
+
+// Encode returns the cursor as a URL-safe string accepted by the after parameter.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor", ErrParam)
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor", ErrParam)
+	}
+
+	return c, nil
+}
+
+// keysetSQL renders the condition selecting the records which come after
+// the cursor in the order of f. For keys k1, k2 and the id it's
+//
+//	k1 after v1 OR (k1 = v1 AND k2 after v2) OR (k1 = v1 AND k2 = v2 AND id after i)
+//
+// where "after" depends on the direction and the placement of NULLs.
+func (f Filter) keysetSQL() (string, []any) {
+	var (
+		alternatives []string
+		args         []any
+		equal        []string
+		equalArgs    []any
+	)
+
+	for i, k := range f.OrderKeys() {
+		var value any = f.After.ID
+		if i < len(f.After.Values) {
+			if v := f.After.Values[i]; v != nil {
+				value = *v
+			} else {
+				value = nil
+			}
+		}
+
+		op := " > ?"
+		if k.Desc {
+			op = " < ?"
+		}
+
+		var after string
+
+		switch {
+		case value != nil && (k.NullsFirst() || k.Column == f.IDColumn):
+			after = k.Column + op
+		case value != nil:
+			after = "(" + k.Column + op + " OR " + k.Column + " IS NULL)"
+		case k.NullsFirst():
+			after = k.Column + " IS NOT NULL"
+		}
+
+		if after != "" {
+			conds := append(equal[:len(equal):len(equal)], after)
+			alternatives = append(alternatives, strings.Join(conds, " AND "))
+			args = append(args, equalArgs...)
+
+			if value != nil {
+				args = append(args, value)
+			}
+		}
+
+		if value == nil {
+			equal = append(equal, k.Column+" IS NULL")
+		} else {
+			equal = append(equal, k.Column+" = ?")
+			equalArgs = append(equalArgs, value)
+		}
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], args
+	}
+
+	for i, a := range alternatives {
+		if strings.Contains(a, " AND ") {
+			alternatives[i] = "(" + a + ")"
+		}
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
@@ -1,15 +1,22 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 	"pawrest/internal/yamlconfig"
 )
 
@@ -20,18 +27,58 @@ var (
 )
 
 type DatabaseInterface interface {
+	TxDatabaseInterface
 	BookDatabaseInterface
 	AuthorDatabaseInterface
 	GenreDatabaseInterface
 	LanguageDatabaseInterface
+	SearchDatabaseInterface
+	StatsDatabaseInterface
+	GroupDatabaseInterface
+}
+
+type TxDatabaseInterface interface {
+	// WithTx runs fn inside a single transaction. The transaction is
+	// committed when fn returns nil and rolled back when it returns an error
+	// or panics. Calling WithTx on a transactional value reuses the
+	// already running transaction, only rolling back the changes made
+	// by fn (up to a savepoint) when it fails. The records read by id inside
+	// the transaction can't be changed by other ones until it ends.
+	WithTx(ctx context.Context, fn func(tx DatabaseInterface) error) error
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 type Database struct {
-	pool *sql.DB
+	pool    *sql.DB
+	conn    querier
+	dialect dialect
+	timeout time.Duration
+	// savepoints is the number of savepoints of the running transaction.
+	savepoints int
 }
 
 var _ DatabaseInterface = (*Database)(nil)
 
+// Connect opens the database backend selected by cfg.DBDriver.
+func Connect(cfg *yamlconfig.Config) (*Database, error) {
+	switch cfg.DBDriver {
+	case "", "mysql":
+		return ConnectToDB(cfg)
+	case "sqlite":
+		return ConnectToSQLite(cfg)
+	case "postgres":
+		return ConnectToPostgres(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.DBDriver)
+	}
+}
+
 func ConnectToDB(cfg *yamlconfig.Config) (*Database, error) {
 	dbCfg := mysql.NewConfig()
 
@@ -55,7 +102,7 @@ func ConnectToDB(cfg *yamlconfig.Config) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Database{pool: db}, nil
+	return &Database{pool: db, conn: db, dialect: mysqlDialect{}, timeout: cfg.DBTimeout}, nil
 }
 
 func (d *Database) CloseDB() {
@@ -68,101 +115,466 @@ func (d *Database) Pool() *sql.DB {
 	return d.pool
 }
 
+func (d *Database) WithTx(ctx context.Context, fn func(tx DatabaseInterface) error) error {
+	if _, inTx := d.conn.(*sql.Tx); inTx {
+		return d.withSavepoint(ctx, fn)
+	}
+
+	tx, err := d.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction (%w)", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Database{pool: d.pool, conn: tx, dialect: d.dialect, timeout: d.timeout}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback error: %v)", err, rbErr)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit transaction (%w)", err)
+	}
+
+	return nil
+}
+
+// lockRows returns the clause which locks the rows read by a query until the
+// running transaction ends, or an empty string outside of transactions.
+func (d *Database) lockRows() string {
+	if _, inTx := d.conn.(*sql.Tx); inTx {
+		return d.dialect.lockRows()
+	}
+
+	return ""
+}
+
+// inTx is WithTx passing fn the transactional *Database.
+func (d *Database) inTx(ctx context.Context, fn func(tx *Database) error) error {
+	return d.WithTx(ctx, func(tx DatabaseInterface) error {
+		return fn(tx.(*Database))
+	})
+}
+
+// withSavepoint runs fn inside the running transaction, rolling back
+// to a savepoint taken before it when it returns an error or panics.
+func (d *Database) withSavepoint(ctx context.Context, fn func(tx DatabaseInterface) error) error {
+	d.savepoints++
+	defer func() { d.savepoints-- }()
+
+	name := "sp" + strconv.Itoa(d.savepoints)
+
+	if _, err := d.conn.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("Failed to create savepoint (%w)", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			d.conn.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(d); err != nil {
+		if _, rbErr := d.conn.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("%w (rollback error: %v)", err, rbErr)
+		}
+
+		return err
+	}
+
+	if _, err := d.conn.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("Failed to release savepoint (%w)", err)
+	}
+
+	return nil
+}
+
+// queryContext derives the context used for a single query,
+// bounded by the configured per-query timeout if there is one.
+func (d *Database) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, d.timeout)
+}
+
+// queryWithParams selects the columns of the fields requested in params
+// (all columns by default) from the from clause, filtered, sorted and
+// paginated according to params. The id and the sorted fields are always
+// selected, as the pagination cursors are built from them.
 func queryWithParams[T any](
+	ctx context.Context,
 	d *Database,
-	query string,
+	from string,
 	params url.Values,
 	allowPar map[string]string,
-	scanFunc func(*T, *sql.Rows) error,
+	columns []column[T],
 ) ([]T, error) {
-	var args []any
+	query, args, scanFunc, err := selectWithParams(d, from, params, allowPar, columns)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(params) > 0 {
-		filter, argsOut, err := AssembleFilter(params, allowPar)
-		if err != nil {
-			return nil, err
+	return queryRows(ctx, d, query, args, scanFunc)
+}
+
+// streamWithParams is queryWithParams yielding the records one at a time,
+// as they're scanned. A stream lasts as long as the client takes to read
+// it, so it's bounded by ctx alone instead of the per-query timeout. On
+// the backends which buffer streams the records are read at once, within
+// the timeout, so that a slow client doesn't hold the only connection.
+func streamWithParams[T any](
+	ctx context.Context,
+	d *Database,
+	from string,
+	params url.Values,
+	allowPar map[string]string,
+	columns []column[T],
+) iter.Seq2[T, error] {
+	query, args, scanFunc, err := selectWithParams(d, from, params, allowPar, columns)
+	if err != nil {
+		return func(yield func(T, error) bool) {
+			var zero T
+			yield(zero, err)
 		}
+	}
 
-		query += filter
-		args = argsOut
+	if d.dialect.bufferStreams() {
+		return StreamList(ctx, nil, func(ctx context.Context, _ url.Values) ([]T, error) {
+			return queryRows(ctx, d, query, args, scanFunc)
+		})
 	}
 
-	records := []T{}
+	return streamRows(ctx, d, query, args, scanFunc)
+}
 
-	rows, err := d.pool.Query(query, args...)
+// selectWithParams builds the query of queryWithParams
+// along with its arguments and the function scanning its rows.
+func selectWithParams[T any](
+	d *Database,
+	from string,
+	params url.Values,
+	allowPar map[string]string,
+	columns []column[T],
+) (string, []any, func(*T, *sql.Rows) error, error) {
+	f, err := ParseFilter(params, allowPar)
 	if err != nil {
-		return nil, fmt.Errorf("Query error (%v)", err)
+		return "", nil, nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var r T
+	f.dialect = d.dialect
 
-		if err := scanFunc(&r, rows); err != nil {
-			return nil, fmt.Errorf("Scan error (%v)", err)
+	if len(f.Fields) > 0 {
+		columns = slices.DeleteFunc(slices.Clone(columns), func(c column[T]) bool {
+			return c.param != "id" && !slices.Contains(f.Fields, c.param) &&
+				!slices.ContainsFunc(f.Sort, func(k SortKey) bool { return k.Param == c.param })
+		})
+	}
+
+	exprs := make([]string, len(columns))
+	for i, c := range columns {
+		exprs[i] = allowPar[c.param]
+	}
+
+	filter, args := f.SQL()
+	query := "SELECT " + strings.Join(exprs, ", ") + " FROM " + from + filter
+
+	scanFunc := func(r *T, rows *sql.Rows) error {
+		dests := make([]any, len(columns))
+		for i, c := range columns {
+			dests[i] = c.dest(r)
 		}
 
-		records = append(records, r)
+		return rows.Scan(dests...)
+	}
+
+	return query, args, scanFunc, nil
+}
+
+// countWithParams counts the records of from (a table, optionally
+// with joins) matching the filtering conditions in params. Sorting and
+// pagination parameters are validated but don't affect the count.
+func countWithParams(
+	ctx context.Context,
+	d *Database,
+	from string,
+	params url.Values,
+	allowPar map[string]string,
+) (int64, error) {
+	f, err := ParseFilter(params, allowPar)
+	if err != nil {
+		return 0, err
+	}
+
+	f.dialect = d.dialect
+	where, args := f.Where()
+	query := "SELECT COUNT(*) FROM " + from + where
+
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	var n int64
+
+	if err := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("Count error (%w)", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("Rows error (%v)", err)
+	return n, nil
+}
+
+// queryRows runs query with args and scans every returned row with scanFunc.
+func queryRows[T any](
+	ctx context.Context,
+	d *Database,
+	query string,
+	args []any,
+	scanFunc func(*T, *sql.Rows) error,
+) ([]T, error) {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	records := []T{}
+
+	for r, err := range streamRows(ctx, d, query, args, scanFunc) {
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, r)
 	}
 
 	return records, nil
 }
 
+// streamRows returns an iterator running query with args when the iteration
+// starts and yielding every returned row, scanned with scanFunc. A failure
+// is yielded as the last error, and the rows are closed when the iteration
+// ends, including when the caller stops it early. The iteration is bounded
+// by ctx alone, queryRows applies the per-query timeout.
+func streamRows[T any](
+	ctx context.Context,
+	d *Database,
+	query string,
+	args []any,
+	scanFunc func(*T, *sql.Rows) error,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := d.conn.QueryContext(ctx, d.dialect.rebind(query), args...)
+		if err != nil {
+			yield(zero, fmt.Errorf("Query error (%w)", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var r T
+
+			if err := scanFunc(&r, rows); err != nil {
+				yield(zero, fmt.Errorf("Scan error (%w)", err))
+				return
+			}
+
+			if !yield(r, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, fmt.Errorf("Rows error (%w)", err))
+		}
+	}
+}
+
+// StreamList adapts list, which returns all of the records at once, to the
+// iterators of the Stream methods, for the implementations which don't read
+// the records one at a time. list is called when the iteration starts.
+func StreamList[T any](
+	ctx context.Context,
+	params url.Values,
+	list func(context.Context, url.Values) ([]T, error),
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		records, err := list(ctx, params)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		for _, r := range records {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+// queryID scans the record with id returned by query. Inside a transaction
+// the row is locked until the transaction ends, so that a record read and
+// then replaced by it can't be changed by another transaction in between.
 func queryID[T any](
+	ctx context.Context,
 	d *Database,
 	query string,
 	id int64,
 	scanFunc func(*T, *sql.Row) error,
+) (T, error) {
+	query += d.lockRows()
+
+	return queryOne(ctx, d, fmt.Sprintf("with id %v", id), query, scanFunc, id)
+}
+
+// queryOne scans the single row returned by query with args. When there's
+// none, the ErrNotFound it returns is described by what.
+func queryOne[T any](
+	ctx context.Context,
+	d *Database,
+	what string,
+	query string,
+	scanFunc func(*T, *sql.Row) error,
+	args ...any,
 ) (T, error) {
 	var r T
 
-	row := d.pool.QueryRow(query, id)
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	row := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), args...)
 	if err := scanFunc(&r, row); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return r, fmt.Errorf("%w with id %v", ErrNotFound, id)
+			return r, fmt.Errorf("%w %s", ErrNotFound, what)
 		}
 
-		return r, fmt.Errorf("Scan error (%v)", err)
+		return r, fmt.Errorf("Scan error (%w)", err)
 	}
 
 	return r, nil
 }
 
-func (d *Database) insert(query string, args ...any) (int64, error) {
-	res, err := d.pool.Exec(query, args...)
+func (d *Database) insert(ctx context.Context, query string, args ...any) (int64, error) {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	query = d.dialect.rebind(query)
+
+	if d.dialect.returningID() {
+		var id int64
+
+		if err := d.conn.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id); err != nil {
+			if isErrForeignKey(err) {
+				return 0, ErrForeignKey
+			}
+
+			return 0, fmt.Errorf("Failed to insert record (%w)", err)
+		}
+
+		return id, nil
+	}
+
+	res, err := d.conn.ExecContext(ctx, query, args...)
 	if err != nil {
 		if isErrForeignKey(err) {
 			return 0, ErrForeignKey
 		}
 
-		return 0, fmt.Errorf("Failed to insert record (%v)", err)
+		return 0, fmt.Errorf("Failed to insert record (%w)", err)
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
-		return 0, fmt.Errorf("Failed to retrieve id (%v)", err)
+		return 0, fmt.Errorf("Failed to retrieve id (%w)", err)
 	}
 
 	return id, nil
 }
 
-func (d *Database) updateWholeID(query string, args ...any) error {
-	res, err := d.pool.Exec(query, args...)
+// insertMany inserts rows of values of columns into table, so that either all
+// or none of them are inserted, and returns the ids of the inserted rows in
+// order. The rows are inserted with a single multi-row INSERT on the backends
+// which read the ids with RETURNING id, and one at a time inside a transaction
+// on the others, as the ids of a multi-row INSERT aren't always consecutive
+// there (e.g. with innodb_autoinc_lock_mode=2 or auto_increment_increment > 1),
+// so they can't be derived from its LastInsertId.
+func (d *Database) insertMany(ctx context.Context, table string, columns []string, rows [][]any) ([]int64, error) {
+	if len(rows) == 0 {
+		return []int64{}, nil
+	}
+
+	placeholders := "(" + strings.Repeat("?, ", len(columns)-1) + "?)"
+	insert := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES "
+
+	if !d.dialect.returningID() {
+		ids := make([]int64, 0, len(rows))
+
+		err := d.inTx(ctx, func(tx *Database) error {
+			for _, row := range rows {
+				id, err := tx.insert(ctx, insert+placeholders, row...)
+				if err != nil {
+					return err
+				}
+
+				ids = append(ids, id)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return ids, nil
+	}
+
+	var (
+		values []string
+		args   []any
+	)
+
+	for _, row := range rows {
+		values = append(values, placeholders)
+		args = append(args, row...)
+	}
+
+	idFunc := func(id *int64, rows *sql.Rows) error {
+		return rows.Scan(id)
+	}
+
+	ids, err := queryRows(ctx, d, insert+strings.Join(values, ", ")+" RETURNING id", args, idFunc)
+	if isErrForeignKey(err) {
+		return nil, ErrForeignKey
+	}
+
+	return ids, err
+}
+
+func (d *Database) updateWholeID(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	res, err := d.conn.ExecContext(ctx, d.dialect.rebind(query), args...)
 	if err != nil {
 		if isErrForeignKey(err) {
 			return ErrForeignKey
 		}
 
-		return fmt.Errorf("Failed to update (%v)", err)
+		return fmt.Errorf("Failed to update (%w)", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("Rows affected error (%v)", err)
+		return fmt.Errorf("Rows affected error (%w)", err)
 	}
 
 	if rows == 0 {
@@ -172,7 +584,7 @@ func (d *Database) updateWholeID(query string, args ...any) error {
 	return nil
 }
 
-func (d *Database) updatePartID(r any, table string, id int64, fToDB map[string]string) error {
+func (d *Database) updatePartID(ctx context.Context, r any, table string, id int64, fToDB map[string]string) error {
 	var (
 		updates []string
 		args    []any
@@ -197,24 +609,27 @@ func (d *Database) updatePartID(r any, table string, id int64, fToDB map[string]
 	}
 
 	if len(updates) == 0 {
-		return fmt.Errorf("No columns to update")
+		return fmt.Errorf("%w: no columns to update", ErrParam)
 	}
 
 	query := "UPDATE " + table + " SET " + strings.Join(updates, ", ") + " WHERE id = ?"
 	args = append(args, id)
 
-	res, err := d.pool.Exec(query, args...)
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	res, err := d.conn.ExecContext(ctx, d.dialect.rebind(query), args...)
 	if err != nil {
 		if isErrForeignKey(err) {
 			return ErrForeignKey
 		}
 
-		return fmt.Errorf("Failed to update (%v)", err)
+		return fmt.Errorf("Failed to update (%w)", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("Rows affected error (%v)", err)
+		return fmt.Errorf("Rows affected error (%w)", err)
 	}
 
 	if rows == 0 {
@@ -224,19 +639,22 @@ func (d *Database) updatePartID(r any, table string, id int64, fToDB map[string]
 	return nil
 }
 
-func (d *Database) deleteID(query string, id int64) error {
-	res, err := d.pool.Exec(query, id)
+func (d *Database) deleteID(ctx context.Context, query string, id int64) error {
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	res, err := d.conn.ExecContext(ctx, d.dialect.rebind(query), id)
 	if err != nil {
 		if isErrForeignKey(err) {
 			return ErrForeignKey
 		}
 
-		return fmt.Errorf("Failed to delete (%v)", err)
+		return fmt.Errorf("Failed to delete (%w)", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("Rows affected error (%v)", err)
+		return fmt.Errorf("Rows affected error (%w)", err)
 	}
 
 	if rows == 0 {
@@ -247,124 +665,24 @@ func (d *Database) deleteID(query string, id int64) error {
 }
 
 func isErrForeignKey(err error) bool {
-	var mysqlerr *mysql.MySQLError
+	var (
+		mysqlerr  *mysql.MySQLError
+		sqliteerr *sqlite.Error
+		pqerr     *pq.Error
+	)
 
 	if errors.As(err, &mysqlerr) {
 		return mysqlerr.Number == 1452 ||
 			mysqlerr.Number == 1451
 	}
 
-	return false
-}
-
-func AssembleFilter(params url.Values, allowedParams map[string]string) (string, []any, error) {
-	var (
-		conditions []string
-		args       []any
-	)
-
-	operators := map[string]string{
-		".eq":  "=",
-		".gt":  ">",
-		".lt":  "<",
-		".gte": ">=",
-		".lte": "<=",
-		".neq": "<>",
-	}
-
-	limit, hasLimit := params["limit"]
-	offset, hasOffset := params["offset"]
-
-	if !hasLimit && hasOffset {
-		return "", nil, fmt.Errorf("%w: a limit must be provided when using an offset", ErrParam)
-	}
-
-	for key, valSlice := range params {
-		if key == "limit" || key == "offset" || key == "sort_by" || key == "extend" {
-			continue
-		}
-
-		operator := "="
-
-		for suffix, sqlOp := range operators {
-			if before, found := strings.CutSuffix(key, suffix); found {
-				key = before
-				operator = sqlOp
-				break
-			}
-		}
-
-		columnName, allowed := allowedParams[key]
-		if !allowed {
-			return "", nil, fmt.Errorf("%w: an unknown parameter was provided", ErrParam)
-		}
-
-		if len(valSlice) == 0 || valSlice[0] == "" {
-			return "", nil, fmt.Errorf("%w: provided parameter is empty", ErrParam)
-		}
-
-		if len(valSlice) > 1 {
-			return "", nil, fmt.Errorf("%w: too many parameters were provided for a single column", ErrParam)
-		}
-
-		if strings.ToLower(valSlice[0]) != "null" {
-			conditions = append(conditions, columnName+" "+operator+" ?")
-			args = append(args, valSlice[0])
-		} else {
-			if operator != "=" && operator != "<>" {
-				return "", nil, fmt.Errorf("%w: cannot use other operations than equal or not equal on null", ErrParam)
-			}
-
-			if operator == "=" {
-				operator = "IS"
-			} else {
-				operator = "IS NOT"
-			}
-
-			conditions = append(conditions, columnName+" "+operator+" NULL")
-		}
-	}
-
-	filter := ""
-
-	if len(conditions) > 0 {
-		filter += " WHERE " + strings.Join(conditions, " AND ")
+	if errors.As(err, &sqliteerr) {
+		return sqliteerr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
 	}
 
-	if sort, hasSort := params["sort_by"]; hasSort {
-		if len(sort) == 0 || sort[0] == "" {
-			return "", nil, fmt.Errorf("%w: provided column is empty", ErrParam)
-		}
-
-		if len(sort) > 1 {
-			return "", nil, fmt.Errorf("%w: too many columns were provided for sorting", ErrParam)
-		}
-
-		order := ""
-
-		arg := sort[0]
-		if after, found := strings.CutPrefix(arg, "-"); found {
-			order = " DESC"
-			arg = after
-		}
-
-		columnName, allowed := allowedParams[arg]
-		if !allowed {
-			return "", nil, fmt.Errorf("%w: an unknown column was provided", ErrParam)
-		}
-
-		filter = filter + " ORDER BY " + columnName + order
-	}
-
-	if hasLimit {
-		filter += " LIMIT ?"
-		args = append(args, limit[0])
+	if errors.As(err, &pqerr) {
+		return pqerr.Code == "23503"
 	}
 
-	if hasOffset {
-		filter += " OFFSET ?"
-		args = append(args, offset[0])
-	}
-
-	return filter, args, nil
+	return false
 }
@@ -1,12 +1,15 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"log"
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"pawrest/internal/yamlconfig"
@@ -25,8 +28,8 @@ func runMain(m *testing.M) (int, error) {
 	flag.Parse()
 
 	if testing.Short() {
-		log.Println("Skipping Database testing in short mode because we don't have connection to the database")
-		return 0, nil
+		log.Println("Skipping MariaDB testing in short mode because we don't have connection to the database")
+		return m.Run(), nil
 	}
 
 	cfg := &yamlconfig.Config{
@@ -63,7 +66,17 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+func requireMariaDB(t *testing.T) {
+	t.Helper()
+
+	if database == nil {
+		t.Skip("No connection to MariaDB in short mode")
+	}
+}
+
 func TestQueryID(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		giveID    int64
 		wantQuote string
@@ -96,14 +109,14 @@ func TestQueryID(t *testing.T) {
 			}
 
 			if tt.wantErrIs == nil {
-				q, err := queryID[quote](database, query, tt.giveID, quoteFunc)
+				q, err := queryID[quote](context.Background(), database, query, tt.giveID, quoteFunc)
 				assert.NoError(t, err)
 
 				assert.Equal(t, q.Quote, tt.wantQuote)
 				assert.Equal(t, q.Ranking, tt.wantRank)
 				assert.Equal(t, q.FK, tt.wantFK)
 			} else {
-				_, err := queryID[quote](database, query, tt.giveID, quoteFunc)
+				_, err := queryID[quote](context.Background(), database, query, tt.giveID, quoteFunc)
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.wantErrIs)
 			}
@@ -112,6 +125,8 @@ func TestQueryID(t *testing.T) {
 }
 
 func TestQueryWithParams(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		giveParams url.Values
 		wantErrIs  error
@@ -197,28 +212,25 @@ func TestQueryWithParams(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			query := `
-				SELECT
-					id, quote, ranking
-				FROM test_table`
-
 			allowedParams := map[string]string{
 				"id":      "id",
 				"quote":   "quote",
 				"ranking": "ranking",
 			}
 
-			quoteFunc := func(q *quote, rows *sql.Rows) error {
-				return rows.Scan(&q.ID, &q.Quote, &q.Ranking)
+			quoteColumns := []column[quote]{
+				{"id", func(q *quote) any { return &q.ID }},
+				{"quote", func(q *quote) any { return &q.Quote }},
+				{"ranking", func(q *quote) any { return &q.Ranking }},
 			}
 
 			if tt.wantErrIs == nil {
-				qs, err := queryWithParams[quote](database, query, tt.giveParams, allowedParams, quoteFunc)
+				qs, err := queryWithParams(context.Background(), database, "test_table", tt.giveParams, allowedParams, quoteColumns)
 				assert.NoError(t, err)
 
 				assert.NotEmpty(t, qs)
 			} else {
-				_, err := queryWithParams[quote](database, query, tt.giveParams, allowedParams, quoteFunc)
+				_, err := queryWithParams(context.Background(), database, "test_table", tt.giveParams, allowedParams, quoteColumns)
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.wantErrIs)
 			}
@@ -227,6 +239,8 @@ func TestQueryWithParams(t *testing.T) {
 }
 
 func TestInsert(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		giveQuote any
 		giveRank  any
@@ -262,11 +276,11 @@ func TestInsert(t *testing.T) {
 				VALUES (?, ?, ?)`
 
 			if !tt.wantErr {
-				id, err := database.insert(query, tt.giveQuote, tt.giveRank, tt.giveFK)
+				id, err := database.insert(context.Background(), query, tt.giveQuote, tt.giveRank, tt.giveFK)
 				assert.NoError(t, err)
 				assert.NotEmpty(t, id)
 			} else {
-				_, err := database.insert(query, tt.giveQuote, tt.giveRank, tt.giveFK)
+				_, err := database.insert(context.Background(), query, tt.giveQuote, tt.giveRank, tt.giveFK)
 				assert.Error(t, err)
 
 				if tt.wantErrIs != nil {
@@ -278,6 +292,8 @@ func TestInsert(t *testing.T) {
 }
 
 func TestUpdateWholeID(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		giveQuote string
 		giveRank  any
@@ -334,7 +350,7 @@ func TestUpdateWholeID(t *testing.T) {
 					fk = ?
 				WHERE id = ?`
 
-			err := database.updateWholeID(query, tt.giveQuote, tt.giveRank, tt.giveFK, tt.giveID)
+			err := database.updateWholeID(context.Background(), query, tt.giveQuote, tt.giveRank, tt.giveFK, tt.giveID)
 			if !tt.wantErr {
 				assert.NoError(t, err)
 			} else {
@@ -349,6 +365,8 @@ func TestUpdateWholeID(t *testing.T) {
 }
 
 func TestUpdatePartID(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		giveQuote quote
 		giveID    int64
@@ -412,7 +430,7 @@ func TestUpdatePartID(t *testing.T) {
 				"FK":      "fk",
 			}
 
-			err := database.updatePartID(tt.giveQuote, "test_table", tt.giveID, fieldToDB)
+			err := database.updatePartID(context.Background(), tt.giveQuote, "test_table", tt.giveID, fieldToDB)
 			if !tt.wantErr {
 				assert.NoError(t, err)
 			} else {
@@ -427,6 +445,8 @@ func TestUpdatePartID(t *testing.T) {
 }
 
 func TestDelete(t *testing.T) {
+	requireMariaDB(t)
+
 	tests := map[string]struct {
 		id      int64
 		wantErr error
@@ -444,7 +464,7 @@ func TestDelete(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			query := "DELETE FROM test_table WHERE id = ?"
 
-			err := database.deleteID(query, tt.id)
+			err := database.deleteID(context.Background(), query, tt.id)
 			if tt.wantErr == nil {
 				assert.NoError(t, err)
 			} else {
@@ -455,6 +475,188 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestQueryContext(t *testing.T) {
+	requireMariaDB(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := map[string]struct {
+		giveCtx     context.Context
+		giveTimeout time.Duration
+		giveQuery   string
+		wantErrIs   error
+	}{
+		"Success": {
+			giveCtx:     context.Background(),
+			giveTimeout: time.Second,
+			giveQuery:   "SELECT id, quote, ranking FROM test_table",
+		},
+		"ErrCanceled": {
+			giveCtx:   cancelled,
+			giveQuery: "SELECT id, quote, ranking FROM test_table",
+			wantErrIs: context.Canceled,
+		},
+		"ErrDeadlineExceeded": {
+			giveCtx:     context.Background(),
+			giveTimeout: 50 * time.Millisecond,
+			giveQuery:   "SELECT id, quote, ranking FROM test_table WHERE SLEEP(1) = 0",
+			wantErrIs:   context.DeadlineExceeded,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &Database{pool: database.Pool(), conn: database.Pool(), dialect: mysqlDialect{}, timeout: tt.giveTimeout}
+
+			quoteFunc := func(q *quote, rows *sql.Rows) error {
+				return rows.Scan(&q.ID, &q.Quote, &q.Ranking)
+			}
+
+			_, err := queryRows(tt.giveCtx, d, tt.giveQuery, nil, quoteFunc)
+			if tt.wantErrIs == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestExecContext_Canceled(t *testing.T) {
+	requireMariaDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	query := `
+		INSERT INTO test_table
+		(quote, ranking, fk)
+		VALUES (?, ?, ?)`
+
+	_, err := database.insert(ctx, query, "Cancelled quote", 1, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = database.deleteID(ctx, "DELETE FROM test_table WHERE id = ?", 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithTx(t *testing.T) {
+	requireMariaDB(t)
+
+	errRollback := errors.New("rollback")
+
+	countQuotes := func(t *testing.T, quote string) int {
+		t.Helper()
+
+		var n int
+		err := database.Pool().QueryRow("SELECT COUNT(*) FROM test_table WHERE quote = ?", quote).Scan(&n)
+		assert.NoError(t, err)
+
+		return n
+	}
+
+	insertQuote := func(tx DatabaseInterface, quote string) error {
+		query := `
+			INSERT INTO test_table
+			(quote, ranking, fk)
+			VALUES (?, ?, ?)`
+
+		_, err := tx.(*Database).insert(context.Background(), query, quote, 1, 1)
+		return err
+	}
+
+	tests := map[string]struct {
+		giveQuote string
+		giveFunc  func(tx DatabaseInterface, quote string) error
+		wantCount int
+		wantErrIs error
+		wantPanic bool
+	}{
+		"SuccessCommit": {
+			giveQuote: "Tx commit",
+			giveFunc: func(tx DatabaseInterface, quote string) error {
+				if err := insertQuote(tx, quote); err != nil {
+					return err
+				}
+
+				return insertQuote(tx, quote)
+			},
+			wantCount: 2,
+		},
+		"SuccessNested": {
+			giveQuote: "Tx nested",
+			giveFunc: func(tx DatabaseInterface, quote string) error {
+				return tx.WithTx(context.Background(), func(inner DatabaseInterface) error {
+					if inner != tx {
+						return errors.New("nested call started a new transaction")
+					}
+
+					return insertQuote(inner, quote)
+				})
+			},
+			wantCount: 1,
+		},
+		"RollbackOnError": {
+			giveQuote: "Tx error",
+			giveFunc: func(tx DatabaseInterface, quote string) error {
+				if err := insertQuote(tx, quote); err != nil {
+					return err
+				}
+
+				return errRollback
+			},
+			wantErrIs: errRollback,
+		},
+		"RollbackOnForeignKey": {
+			giveQuote: "Tx foreign key",
+			giveFunc: func(tx DatabaseInterface, quote string) error {
+				if err := insertQuote(tx, quote); err != nil {
+					return err
+				}
+
+				query := "INSERT INTO test_table (quote, ranking, fk) VALUES (?, ?, ?)"
+				_, err := tx.(*Database).insert(context.Background(), query, quote, 1, 1000)
+				return err
+			},
+			wantErrIs: ErrForeignKey,
+		},
+		"RollbackOnPanic": {
+			giveQuote: "Tx panic",
+			giveFunc: func(tx DatabaseInterface, quote string) error {
+				if err := insertQuote(tx, quote); err != nil {
+					return err
+				}
+
+				panic("tx panic")
+			},
+			wantPanic: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			run := func() error {
+				return database.WithTx(context.Background(), func(tx DatabaseInterface) error {
+					return tt.giveFunc(tx, tt.giveQuote)
+				})
+			}
+
+			switch {
+			case tt.wantPanic:
+				assert.Panics(t, func() { run() })
+			case tt.wantErrIs != nil:
+				assert.ErrorIs(t, run(), tt.wantErrIs)
+			default:
+				assert.NoError(t, run())
+			}
+
+			assert.Equal(t, tt.wantCount, countQuotes(t, tt.giveQuote))
+		})
+	}
+}
+
 func setupTestDatabase(db *sql.DB) error {
 	if _, err := db.Exec("DROP TABLE IF EXISTS test_table"); err != nil {
 		return err
@@ -0,0 +1,128 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testGetAuthors(t *testing.T, d db.DatabaseInterface) {
+	tests := map[string]struct {
+		giveParams url.Values
+		wantNames  []string
+		wantLen    int
+		wantErrIs  error
+	}{
+		"NoParams": {
+			giveParams: url.Values{},
+			wantLen:    9,
+		},
+		"EqCaseInsensitive": {
+			giveParams: url.Values{"last_name": {"LEM"}},
+			wantNames:  []string{"Lem"},
+		},
+		"BirthYearSort": {
+			giveParams: url.Values{"birth_year.gt": {"1900"}, "sort_by": {"-birth_year"}},
+			wantNames:  []string{"Lem", "Gombrowicz", "Orwell"},
+		},
+		"DeathYearLimit": {
+			giveParams: url.Values{"death_year.lt": {"1900"}, "sort_by": {"death_year"}, "limit": {"1"}},
+			wantNames:  []string{"Mickiewicz"},
+		},
+		"StartsWith": {
+			giveParams: url.Values{"last_name.startswith": {"S"}},
+			wantNames:  []string{"Sienkiewicz"},
+		},
+		"NotNull": {
+			giveParams: url.Values{"death_year.neq": {"null"}},
+			wantLen:    9,
+		},
+		"Null": {
+			giveParams: url.Values{"death_year": {"null"}},
+			wantLen:    0,
+		},
+		"ErrUnknownParam": {
+			giveParams: url.Values{"title": {"Lalka"}},
+			wantErrIs:  db.ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			authors, err := d.GetAuthors(context.Background(), tt.giveParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantNames == nil {
+				assert.Len(t, authors, tt.wantLen)
+				return
+			}
+
+			var names []string
+			for _, a := range authors {
+				names = append(names, a.LastName)
+			}
+
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func testAuthorCRUD(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	give := models.Author{FirstName: "Wisława", LastName: "Szymborska", BirthYear: 1923}
+
+	id, err := d.InsertAuthor(ctx, give)
+	assert.NoError(t, err)
+	assert.Greater(t, id, int64(9))
+
+	give.ID = id
+
+	author, err := d.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, author)
+
+	alive, err := d.GetAuthors(ctx, url.Values{"death_year": {"null"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Author{give}, alive)
+
+	err = d.UpdateAuthor(ctx, id, models.Author{DeathYear: models.I64Ptr(2012)})
+	assert.NoError(t, err)
+
+	give.DeathYear = models.I64Ptr(2012)
+
+	author, err = d.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, author)
+
+	// Replacing the whole author clears the omitted death year.
+	give = models.Author{FirstName: "Maria", LastName: "Szymborska", BirthYear: 1923}
+
+	err = d.UpdateWholeAuthor(ctx, id, give)
+	assert.NoError(t, err)
+
+	give.ID = id
+
+	author, err = d.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, author)
+
+	assert.NoError(t, d.DelAuthor(ctx, id))
+
+	_, err = d.GetAuthor(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound)
+
+	assert.ErrorIs(t, d.DelAuthor(ctx, 1), db.ErrForeignKey)
+
+	_, err = d.GetAuthor(ctx, 1)
+	assert.NoError(t, err, "an author with books was deleted")
+}
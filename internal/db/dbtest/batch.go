@@ -0,0 +1,158 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// testInsertMany checks that the multi-row inserts return the ids in order
+// and insert either all or none of the records.
+func testInsertMany(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	give := []models.Book{
+		{Title: "Cyberiada", Year: 1965, Pages: 280, Author: 5, Genre: 6, Language: 2},
+		{Title: "Bajki robotów", Year: 1964, Pages: 180, Author: 5, Genre: 6, Language: 2},
+		{Title: "Kongres futurologiczny", Year: 1971, Pages: 120, Author: 5, Genre: 6, Language: 2},
+	}
+
+	ids, err := d.InsertBooks(ctx, give)
+	assert.NoError(t, err)
+
+	if assert.Len(t, ids, len(give)) {
+		for i, id := range ids {
+			give[i].ID = id
+
+			book, err := d.GetBook(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, give[i], book, "ids should be returned in order")
+		}
+	}
+
+	before, err := d.CountBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+
+	_, err = d.InsertBooks(ctx, []models.Book{
+		{Title: "Głos Pana", Year: 1968, Pages: 240, Author: 5, Genre: 6, Language: 2},
+		{Title: "Brak", Year: 2000, Pages: 100, Author: 5, Genre: 6, Language: 999},
+	})
+	assert.ErrorIs(t, err, db.ErrForeignKey)
+
+	after, err := d.CountBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, before, after, "none of the books should be inserted when one fails")
+
+	authors, err := d.InsertAuthors(ctx, []models.Author{
+		{FirstName: "Wisława", LastName: "Szymborska", BirthYear: 1923, DeathYear: models.I64Ptr(2012)},
+		{FirstName: "Olga", LastName: "Tokarczuk", BirthYear: 1962},
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, authors, 2) {
+		author, err := d.GetAuthor(ctx, authors[1])
+		assert.NoError(t, err)
+		assert.Equal(t, "Tokarczuk", author.LastName)
+		assert.Nil(t, author.DeathYear)
+	}
+
+	genres, err := d.InsertGenres(ctx, []models.Genre{{Name: "Reportaż"}, {Name: "Fraszka"}})
+	assert.NoError(t, err)
+	assert.Len(t, genres, 2)
+
+	languages, err := d.InsertLanguages(ctx, []models.Language{{Name: "Czeski"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, languages, 1) {
+		language, err := d.GetLanguage(ctx, languages[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "Czeski", language.Name)
+	}
+
+	empty, err := d.InsertGenres(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+// testConcurrentInsertMany checks that the ids returned by batches
+// inserted at the same time are the ids of their own records.
+func testConcurrentInsertMany(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	const batches, size = 4, 25
+
+	var wg sync.WaitGroup
+
+	for b := range batches {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			give := make([]models.Genre, size)
+			for i := range give {
+				give[i].Name = fmt.Sprintf("Gatunek %d-%d", b, i)
+			}
+
+			ids, err := d.InsertGenres(ctx, give)
+			if !assert.NoError(t, err) || !assert.Len(t, ids, size) {
+				return
+			}
+
+			for i, id := range ids {
+				genre, err := d.GetGenre(ctx, id)
+				assert.NoError(t, err)
+				assert.Equal(t, give[i].Name, genre.Name, "id %d belongs to another record", id)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// testSavepoint checks that a nested transaction only rolls back its own
+// changes, even after a failed statement.
+func testSavepoint(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	err := d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		if _, err := tx.InsertLanguage(ctx, models.Language{Name: "Czeski"}); err != nil {
+			return err
+		}
+
+		err := tx.WithTx(ctx, func(inner db.DatabaseInterface) error {
+			if _, err := inner.InsertLanguage(ctx, models.Language{Name: "Słowacki"}); err != nil {
+				return err
+			}
+
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+
+		// A failed statement doesn't abort the transaction either.
+		err = tx.WithTx(ctx, func(inner db.DatabaseInterface) error {
+			_, err := inner.InsertBook(ctx, models.Book{Title: "Brak", Year: 2000, Pages: 100, Author: 5, Genre: 6, Language: 999})
+			return err
+		})
+		assert.ErrorIs(t, err, db.ErrForeignKey)
+
+		return tx.WithTx(ctx, func(inner db.DatabaseInterface) error {
+			_, err := inner.InsertLanguage(ctx, models.Language{Name: "Węgierski"})
+			return err
+		})
+	})
+	assert.NoError(t, err)
+
+	for name, want := range map[string]int{"Czeski": 1, "Słowacki": 0, "Węgierski": 1} {
+		langs, err := d.GetLanguages(ctx, url.Values{"name": {name}})
+		assert.NoError(t, err)
+		assert.Len(t, langs, want, name)
+	}
+}
@@ -0,0 +1,442 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testGetBooks(t *testing.T, d db.DatabaseInterface) {
+	tests := map[string]struct {
+		giveParams url.Values
+		wantTitles []string
+		wantLen    int
+		wantErrIs  error
+	}{
+		"NoParams": {
+			giveParams: url.Values{},
+			wantLen:    17,
+		},
+		"EqCaseInsensitive": {
+			giveParams: url.Values{"title": {"dziady"}},
+			wantTitles: []string{"Dziady"},
+		},
+		"EqSuffix": {
+			giveParams: url.Values{"id.eq": {"4"}},
+			wantTitles: []string{"Lalka"},
+		},
+		"GtSortDesc": {
+			giveParams: url.Values{"year.gt": {"1950"}, "sort_by": {"-pages"}},
+			wantTitles: []string{"Powrót z gwiazd", "Pokój na Ziemi", "Solaris", "Brzechwa dzieciom", "Stary człowiek i morze"},
+		},
+		"GteLt": {
+			giveParams: url.Values{"year.gte": {"1946"}, "year.lt": {"1953"}, "sort_by": {"year"}},
+			wantTitles: []string{"Akademia pana Kleksa", "Rok 1984", "Stary człowiek i morze"},
+		},
+		"LteNeq": {
+			giveParams: url.Values{"pages.lte": {"100"}, "author.neq": {"8"}, "sort_by": {"pages"}},
+			wantTitles: []string{"Kamizelka", "Stary człowiek i morze"},
+		},
+		"In": {
+			giveParams: url.Values{"year.in": {"1834,1937,1949"}, "sort_by": {"year"}},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie", "Ferdydurke", "Rok 1984"},
+		},
+		"InParenthesized": {
+			giveParams: url.Values{"title.in": {"(lalka,Potop,Nieistniejąca)"}, "sort_by": {"id"}},
+			wantTitles: []string{"Lalka", "Potop"},
+		},
+		"InEscapedComma": {
+			giveParams: url.Values{"title.in": {`Pan Tadeusz\, czyli ostatni zajazd na Litwie,Dziady`}, "sort_by": {"id"}},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie", "Dziady"},
+		},
+		"Between": {
+			giveParams: url.Values{"pages.between": {"300,340"}, "sort_by": {"pages"}},
+			wantTitles: []string{"Dziady", "Rok 1984", "Solaris"},
+		},
+		"OrGroup": {
+			giveParams: url.Values{"or": {"(genre.eq:5,pages.lt:30)"}, "sort_by": {"id"}},
+			wantTitles: []string{"Dziady", "Kamizelka"},
+		},
+		"OrGroupsAndConditions": {
+			giveParams: url.Values{
+				"or":       {"(author:1,author:5)", "(year.lt:1850,pages.between:(350,400))"},
+				"language": {"2"},
+				"sort_by":  {"id"},
+			},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie", "Dziady", "Powrót z gwiazd", "Pokój na Ziemi"},
+		},
+		"OrGroupIn": {
+			giveParams: url.Values{"or": {"(year.in:(1881,1882),title:Solaris)"}, "sort_by": {"-id"}},
+			wantTitles: []string{"Latarnik", "Solaris", "Kamizelka"},
+		},
+		"Contains": {
+			giveParams: url.Values{"title.contains": {"Tadeusz"}},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie"},
+		},
+		"ContainsMatchesCase": {
+			giveParams: url.Values{"title.contains": {"tADEUSZ"}},
+			wantLen:    0,
+		},
+		"StartsWith": {
+			giveParams: url.Values{"title.startswith": {"Po"}, "sort_by": {"id"}},
+			wantTitles: []string{"Powrót z gwiazd", "Pokój na Ziemi", "Potop"},
+		},
+		"EndsWith": {
+			giveParams: url.Values{"title.endswith": {"kara"}},
+			wantTitles: []string{"Zbrodnia i kara"},
+		},
+		"IContains": {
+			giveParams: url.Values{"title.icontains": {"SOLAR"}},
+			wantTitles: []string{"Solaris"},
+		},
+		"IContainsIgnoresCase": {
+			giveParams: url.Values{"title.icontains": {"tADEUSZ"}},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie"},
+		},
+		"Like": {
+			giveParams: url.Values{"title.like": {"P_to%"}},
+			wantTitles: []string{"Potop"},
+		},
+		"ContainsEscapesWildcards": {
+			giveParams: url.Values{"title.contains": {"_"}},
+			wantLen:    0,
+		},
+		"ContainsEscapesEscape": {
+			giveParams: url.Values{"title.contains": {"!"}},
+			wantLen:    0,
+		},
+		"OrGroupTextMatching": {
+			giveParams: url.Values{"or": {"(title.startswith:Pan,title.endswith:kara)"}, "sort_by": {"id"}},
+			wantTitles: []string{"Pan Tadeusz, czyli ostatni zajazd na Litwie", "Zbrodnia i kara"},
+		},
+		"Limit": {
+			giveParams: url.Values{"sort_by": {"-pages"}, "limit": {"2"}},
+			wantTitles: []string{"Potop", "Lalka"},
+		},
+		"LimitOffset": {
+			giveParams: url.Values{"author": {"8"}, "sort_by": {"pages"}, "limit": {"2"}, "offset": {"1"}},
+			wantTitles: []string{"Quo vadis", "Ogniem i mieczem"},
+		},
+		"LimitZero": {
+			giveParams: url.Values{"limit": {"0"}},
+			wantLen:    0,
+		},
+		"OffsetPastEnd": {
+			giveParams: url.Values{"limit": {"5"}, "offset": {"100"}},
+			wantLen:    0,
+		},
+		"NoMatches": {
+			giveParams: url.Values{"title": {"Nieistniejąca"}},
+			wantLen:    0,
+		},
+		"ErrUnknownParam": {
+			giveParams: url.Values{"foo": {"bar"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrRepeatedParam": {
+			giveParams: url.Values{"year": {"1834", "1937"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrBetweenOneValue": {
+			giveParams: url.Values{"pages.between": {"300"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrInNull": {
+			giveParams: url.Values{"pages.in": {"300,null"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrOrUnknownParam": {
+			giveParams: url.Values{"or": {"(foo:bar,year:1834)"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrOrWithoutParentheses": {
+			giveParams: url.Values{"or": {"year:1834,year:1937"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrContainsOnNumber": {
+			giveParams: url.Values{"year.contains": {"19"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrUnknownSortColumn": {
+			giveParams: url.Values{"sort_by": {"foo"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrOffsetWithoutLimit": {
+			giveParams: url.Values{"offset": {"1"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrNegativeLimit": {
+			giveParams: url.Values{"limit": {"-1"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrNullComparison": {
+			giveParams: url.Values{"pages.gt": {"null"}},
+			wantErrIs:  db.ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			books, err := d.GetBooks(context.Background(), tt.giveParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantTitles == nil {
+				assert.Len(t, books, tt.wantLen)
+				return
+			}
+
+			assert.Equal(t, tt.wantTitles, titles(books))
+		})
+	}
+}
+
+func testGetBooksExt(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	books, err := d.GetBooksExt(ctx, url.Values{"author.last_name": {"orwell"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 1) {
+		want := models.BookExt{
+			ID:       17,
+			Title:    "Rok 1984",
+			Year:     1949,
+			Pages:    312,
+			Author:   models.Author{ID: 9, FirstName: "George", LastName: "Orwell", BirthYear: 1903, DeathYear: models.I64Ptr(1950)},
+			Genre:    models.Genre{ID: 9, Name: "Dystopia"},
+			Language: models.Language{ID: 3, Name: "Angielski"},
+		}
+
+		assert.Equal(t, want, books[0])
+	}
+
+	books, err = d.GetBooksExt(ctx, url.Values{"genre.name": {"Nowela"}, "sort_by": {"-pages"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 2) {
+		assert.Equal(t, "Latarnik", books[0].Title)
+		assert.Equal(t, "Kamizelka", books[1].Title)
+	}
+
+	books, err = d.GetBooksExt(ctx, url.Values{"language.id": {"5"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, "Zbrodnia i kara", books[0].Title)
+		assert.Equal(t, "Rosyjski", books[0].Language.Name)
+	}
+
+	books, err = d.GetBooksExt(ctx, url.Values{"or": {"(genre.name.eq:Dramat,genre.name.eq:Dystopia)"}, "sort_by": {"id"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 2) {
+		assert.Equal(t, "Dziady", books[0].Title)
+		assert.Equal(t, "Rok 1984", books[1].Title)
+	}
+
+	books, err = d.GetBooksExt(ctx, url.Values{"author.last_name.icontains": {"WICZ"}})
+	assert.NoError(t, err)
+	assert.Len(t, books, 7)
+
+	_, err = d.GetBooksExt(ctx, url.Values{"author": {"1"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+}
+
+func testBookCRUD(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	book, err := d.GetBook(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Book{ID: 5, Title: "Kamizelka", Year: 1882, Pages: 24, Author: 3, Genre: 1, Language: 2}, book)
+
+	give := models.Book{Title: "Sklepy cynamonowe", Year: 1934, Pages: 148, Author: 2, Genre: 3, Language: 2}
+
+	id, err := d.InsertBook(ctx, give)
+	assert.NoError(t, err)
+	assert.Greater(t, id, int64(17))
+
+	give.ID = id
+
+	book, err = d.GetBook(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, book)
+
+	// Partial updates only change the non-zero fields.
+	err = d.UpdateBook(ctx, id, models.Book{Pages: 150, Genre: 1})
+	assert.NoError(t, err)
+
+	give.Pages = 150
+	give.Genre = 1
+
+	book, err = d.GetBook(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, book)
+
+	give = models.Book{Title: "Sanatorium pod Klepsydrą", Year: 1937, Pages: 300, Author: 3, Genre: 6, Language: 3}
+
+	err = d.UpdateWholeBook(ctx, id, give)
+	assert.NoError(t, err)
+
+	give.ID = id
+
+	book, err = d.GetBook(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, give, book)
+
+	books, err := d.GetBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, books, 18)
+
+	assert.NoError(t, d.DelBook(ctx, id))
+
+	_, err = d.GetBook(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound)
+	assert.ErrorIs(t, d.DelBook(ctx, id), db.ErrNotFound)
+
+	// Ids of deleted records are not reused.
+	newID, err := d.InsertBook(ctx, give)
+	assert.NoError(t, err)
+	assert.Greater(t, newID, id)
+}
+
+func testBookForeignKey(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	valid := models.Book{Title: "FK", Year: 2000, Pages: 10, Author: 1, Genre: 1, Language: 1}
+
+	tests := map[string]func(b *models.Book){
+		"Author":   func(b *models.Book) { b.Author = 999 },
+		"Genre":    func(b *models.Book) { b.Genre = 999 },
+		"Language": func(b *models.Book) { b.Language = 999 },
+	}
+
+	for name, invalidate := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := valid
+			invalidate(&b)
+
+			_, err := d.InsertBook(ctx, b)
+			assert.ErrorIs(t, err, db.ErrForeignKey, "InsertBook")
+
+			err = d.UpdateWholeBook(ctx, 1, b)
+			assert.ErrorIs(t, err, db.ErrForeignKey, "UpdateWholeBook")
+
+			partial := models.Book{}
+			invalidate(&partial)
+
+			err = d.UpdateBook(ctx, 1, partial)
+			assert.ErrorIs(t, err, db.ErrForeignKey, "UpdateBook")
+		})
+	}
+
+	book, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), book.Author, "a failed update changed the book")
+
+	books, err := d.GetBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, books, 17, "a failed insert added a book")
+}
+
+func testBookYear(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	// violation asserts that err is db.ErrParam wrapping a violation of rule by field.
+	violation := func(t *testing.T, err error, field, rule, msg string) {
+		t.Helper()
+		assert.ErrorIs(t, err, db.ErrParam, msg)
+
+		var v models.Violations
+		if assert.ErrorAs(t, err, &v, msg) && assert.Len(t, v, 1, msg) {
+			assert.Equal(t, field, v[0].Field, msg)
+			assert.Equal(t, rule, v[0].Rule, msg)
+		}
+	}
+
+	// Lem was born in 1921.
+	early := models.Book{Title: "Przed narodzinami", Year: 1900, Pages: 10, Author: 5, Genre: 1, Language: 1}
+
+	_, err := d.InsertBook(ctx, early)
+	violation(t, err, "year", "afterbirth", "InsertBook")
+
+	_, err = d.InsertBooks(ctx, []models.Book{{Title: "Po", Year: 1949, Pages: 10, Author: 9, Genre: 1, Language: 1}, early})
+	violation(t, err, "year", "afterbirth", "InsertBooks")
+
+	err = d.UpdateWholeBook(ctx, 1, early)
+	violation(t, err, "year", "afterbirth", "UpdateWholeBook")
+
+	err = d.UpdateBook(ctx, 1, models.Book{Author: 5})
+	violation(t, err, "year", "afterbirth", "UpdateBook changing the author")
+
+	err = d.UpdateBook(ctx, 1, models.Book{Year: 1700})
+	violation(t, err, "year", "afterbirth", "UpdateBook changing the year")
+
+	lem, err := d.GetAuthor(ctx, 5)
+	assert.NoError(t, err)
+
+	lem.BirthYear = 1990
+	err = d.UpdateWholeAuthor(ctx, 5, lem)
+	violation(t, err, "birth_year", "beforebooks", "UpdateWholeAuthor")
+
+	err = d.UpdateAuthor(ctx, 5, models.Author{BirthYear: 1990})
+	violation(t, err, "birth_year", "beforebooks", "UpdateAuthor")
+
+	assert.NoError(t, d.UpdateAuthor(ctx, 5, models.Author{BirthYear: 1921}), "An unchanged birth year should be accepted")
+
+	err = d.UpdateAuthor(ctx, 5, models.Author{DeathYear: models.I64Ptr(1800)})
+	violation(t, err, "death_year", "gtefield", "UpdateAuthor changing the death year")
+
+	books, err := d.GetBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, books, 17, "a failed insert added a book")
+
+	author, err := d.GetAuthor(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1921), author.BirthYear, "a failed update changed the author")
+	assert.NotEqual(t, models.I64Ptr(1800), author.DeathYear, "a failed update changed the author")
+}
+
+// testConcurrentBookYear checks that a book and the birth year of its author
+// written at the same time can't break the rule checked by testBookYear.
+func testConcurrentBookYear(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	for range 5 {
+		author, err := d.InsertAuthor(ctx, models.Author{FirstName: "Jan", LastName: "Współczesny", BirthYear: 1900})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var (
+			wg                 sync.WaitGroup
+			bookErr, authorErr error
+		)
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			_, bookErr = d.InsertBook(ctx, models.Book{Title: "Wczesna", Year: 1950, Pages: 10, Author: author, Genre: 1, Language: 1})
+		}()
+
+		go func() {
+			defer wg.Done()
+			authorErr = d.UpdateAuthor(ctx, author, models.Author{BirthYear: 2000})
+		}()
+
+		wg.Wait()
+
+		assert.False(t, bookErr == nil && authorErr == nil, "both the book and the later birth year were written")
+		assert.True(t, errors.Is(bookErr, db.ErrParam) || errors.Is(authorErr, db.ErrParam), "one of the writes should be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// testEqualityCase checks that the equality operators ignore the case of
+// text, as the text columns of every SQL backend have case-insensitive
+// collations (utf8mb4_unicode_ci, NOCASE and CITEXT).
+func testEqualityCase(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	books, err := d.GetBooks(ctx, url.Values{"title": {"DZIADY"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Dziady"}, titles(books), "=")
+
+	books, err = d.GetBooks(ctx, url.Values{"title.in": {"lalka,POTOP"}, "sort_by": {"id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Lalka", "Potop"}, titles(books), ".in")
+
+	n, err := d.CountBooks(ctx, url.Values{"title.neq": {"dziady"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), n, ".neq")
+
+	extended, err := d.GetBooksExt(ctx, url.Values{"author.last_name": {"lem"}})
+	assert.NoError(t, err)
+	assert.Len(t, extended, 3, "= on a joined column")
+
+	authors, err := d.GetAuthors(ctx, url.Values{"first_name": {"adam"}, "last_name": {"MICKIEWICZ"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, authors, 1) {
+		assert.Equal(t, "Mickiewicz", authors[0].LastName)
+	}
+
+	genres, err := d.GetGenres(ctx, url.Values{"name": {"nowela"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, genres, 1) {
+		assert.Equal(t, "Nowela", genres[0].Name)
+	}
+
+	languages, err := d.GetLanguages(ctx, url.Values{"name.in": {"POLSKI,angielski"}, "sort_by": {"name"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Language{{ID: 3, Name: "Angielski"}, {ID: 2, Name: "Polski"}}, languages)
+}
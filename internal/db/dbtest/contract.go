@@ -0,0 +1,165 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// RunContract runs the part of the conformance suite which doesn't depend
+// on the stored data, so that it can also be run against databases which
+// don't hold the sample data or don't filter, sort and paginate, like the
+// mock. It only expects the records with id 1 to exist and the ones with
+// id 999 not to. RunConformance runs it as well.
+func RunContract(t *testing.T, newDB Factory) {
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newDB(t)) })
+	t.Run("InvalidParams", func(t *testing.T) { testInvalidParams(t, newDB(t)) })
+	t.Run("Canceled", func(t *testing.T) { testCanceled(t, newDB(t)) })
+	t.Run("Rollback", func(t *testing.T) { testRollback(t, newDB(t)) })
+}
+
+func testNotFound(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	const id = 999
+
+	_, err := d.GetBook(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound, "GetBook")
+	_, err = d.GetAuthor(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound, "GetAuthor")
+	_, err = d.GetGenre(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound, "GetGenre")
+	_, err = d.GetLanguage(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound, "GetLanguage")
+
+	book := models.Book{Title: "Brak", Year: 2000, Pages: 10, Author: 1, Genre: 1, Language: 1}
+	assert.ErrorIs(t, d.UpdateWholeBook(ctx, id, book), db.ErrNotFound, "UpdateWholeBook")
+	assert.ErrorIs(t, d.UpdateBook(ctx, id, models.Book{Title: "Brak"}), db.ErrNotFound, "UpdateBook")
+
+	author := models.Author{FirstName: "Jan", LastName: "Brak", BirthYear: 1900}
+	assert.ErrorIs(t, d.UpdateWholeAuthor(ctx, id, author), db.ErrNotFound, "UpdateWholeAuthor")
+	assert.ErrorIs(t, d.UpdateAuthor(ctx, id, models.Author{LastName: "Brak"}), db.ErrNotFound, "UpdateAuthor")
+
+	assert.ErrorIs(t, d.UpdateWholeGenre(ctx, id, models.Genre{Name: "Brak"}), db.ErrNotFound, "UpdateWholeGenre")
+	assert.ErrorIs(t, d.UpdateWholeLanguage(ctx, id, models.Language{Name: "Brak"}), db.ErrNotFound, "UpdateWholeLanguage")
+
+	assert.ErrorIs(t, d.DelBook(ctx, id), db.ErrNotFound, "DelBook")
+	assert.ErrorIs(t, d.DelAuthor(ctx, id), db.ErrNotFound, "DelAuthor")
+	assert.ErrorIs(t, d.DelGenre(ctx, id), db.ErrNotFound, "DelGenre")
+	assert.ErrorIs(t, d.DelLanguage(ctx, id), db.ErrNotFound, "DelLanguage")
+}
+
+func testInvalidParams(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	paramTests := map[string]url.Values{
+		"UnknownParam":   {"isbn": {"83-01-00000-1"}},
+		"UnknownOp":      {"id.like": {"1"}},
+		"OffsetNoLimit":  {"offset": {"1"}},
+		"UnknownSortKey": {"sort_by": {"isbn"}},
+	}
+
+	for name, params := range paramTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := d.GetBooks(ctx, params)
+			assert.ErrorIs(t, err, db.ErrParam, "GetBooks")
+			_, err = d.GetBooksExt(ctx, params)
+			assert.ErrorIs(t, err, db.ErrParam, "GetBooksExt")
+			_, err = d.GetAuthors(ctx, params)
+			assert.ErrorIs(t, err, db.ErrParam, "GetAuthors")
+			_, err = d.GetGenres(ctx, params)
+			assert.ErrorIs(t, err, db.ErrParam, "GetGenres")
+			_, err = d.GetLanguages(ctx, params)
+			assert.ErrorIs(t, err, db.ErrParam, "GetLanguages")
+
+			for _, err := range d.StreamBooks(ctx, params) {
+				assert.ErrorIs(t, err, db.ErrParam, "StreamBooks")
+			}
+		})
+	}
+
+	countParams := url.Values{"isbn": {"83-01-00000-1"}}
+
+	_, err := d.CountBooks(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "CountBooks")
+	_, err = d.CountBooksExt(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "CountBooksExt")
+	_, err = d.CountAuthors(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "CountAuthors")
+	_, err = d.CountGenres(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "CountGenres")
+	_, err = d.CountLanguages(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "CountLanguages")
+
+	_, err = d.BooksPerGenre(ctx, url.Values{"limit": {"1"}})
+	assert.ErrorIs(t, err, db.ErrParam, "BooksPerGenre")
+	_, err = d.PageStats(ctx, countParams)
+	assert.ErrorIs(t, err, db.ErrParam, "PageStats")
+
+	_, err = d.Group(ctx, "books", url.Values{"group_by": {"isbn"}})
+	assert.ErrorIs(t, err, db.ErrParam, "Group")
+}
+
+func testCanceled(t *testing.T, d db.DatabaseInterface) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := d.GetBooks(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled, "GetBooks")
+	_, err = d.CountAuthors(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled, "CountAuthors")
+	_, err = d.GetBook(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled, "GetBook")
+	_, err = d.InsertGenre(ctx, models.Genre{Name: "Anulowany"})
+	assert.ErrorIs(t, err, context.Canceled, "InsertGenre")
+	assert.ErrorIs(t, d.DelLanguage(ctx, 1), context.Canceled, "DelLanguage")
+
+	for _, err := range d.StreamBooks(ctx, nil) {
+		assert.ErrorIs(t, err, context.Canceled, "StreamBooks")
+	}
+
+	err = d.WithTx(ctx, func(tx db.DatabaseInterface) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled, "WithTx")
+}
+
+func testRollback(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	before, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err)
+
+	err = d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		if err := tx.UpdateBook(ctx, 1, models.Book{Title: "Wycofany"}); err != nil {
+			return err
+		}
+
+		if err := tx.DelBook(ctx, 1); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	after, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err, "rolled back delete is visible")
+	assert.Equal(t, before, after, "rolled back update is visible")
+
+	assert.Panics(t, func() {
+		_ = d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+			if err := tx.DelBook(ctx, 1); err != nil {
+				return err
+			}
+
+			panic("rollback")
+		})
+	})
+
+	_, err = d.GetBook(ctx, 1)
+	assert.NoError(t, err, "delete rolled back by a panic is visible")
+}
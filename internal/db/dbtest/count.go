@@ -0,0 +1,41 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+)
+
+func testCount(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	n, err := d.CountBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(17), n)
+
+	n, err = d.CountBooks(ctx, url.Values{"author": {"5"}, "sort_by": {"-year"}, "limit": {"1"}, "offset": {"1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n, "sorting and pagination should not affect the count")
+
+	n, err = d.CountBooksExt(ctx, url.Values{"or": {"(genre.name:Nowela,language.name:Angielski)"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	n, err = d.CountAuthors(ctx, url.Values{"death_year.gt": {"1950"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	n, err = d.CountGenres(ctx, url.Values{"name.startswith": {"Opow"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	n, err = d.CountLanguages(ctx, url.Values{"name": {"Klingoński"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	_, err = d.CountLanguages(ctx, url.Values{"title": {"Polski"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+}
@@ -0,0 +1,153 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testCursor(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	bookValues := map[string]func(b models.Book) *string{
+		"id":    func(b models.Book) *string { return itoa(b.ID) },
+		"year":  func(b models.Book) *string { return itoa(b.Year) },
+		"title": func(b models.Book) *string { return &b.Title },
+	}
+
+	for _, sortBy := range []string{"", "year", "-year", "id", "-title", "-year,title"} {
+		t.Run("Books_"+sortBy, func(t *testing.T) {
+			all, err := d.GetBooks(ctx, url.Values{"sort_by": {sortBy}})
+			if sortBy == "" {
+				all, err = d.GetBooks(ctx, url.Values{"limit": {"100"}})
+			}
+			assert.NoError(t, err)
+
+			paged := walkPages(t, sortBy, func(params url.Values) ([]models.Book, error) {
+				return d.GetBooks(ctx, params)
+			}, func(b models.Book, param string) *string {
+				return bookValues[param](b)
+			}, func(b models.Book) int64 { return b.ID })
+
+			assert.Equal(t, all, paged)
+		})
+	}
+
+	// The death year of a living author is NULL, which by default comes
+	// first in the ascending order and last in the descending one.
+	living, err := d.InsertAuthor(ctx, models.Author{FirstName: "Olga", LastName: "Tokarczuk", BirthYear: 1962})
+	assert.NoError(t, err)
+
+	authorValues := map[string]func(a models.Author) *string{
+		"death_year": func(a models.Author) *string {
+			if a.DeathYear == nil {
+				return nil
+			}
+
+			return itoa(*a.DeathYear)
+		},
+		"last_name": func(a models.Author) *string { return &a.LastName },
+	}
+
+	for _, sortBy := range []string{"death_year", "-death_year", "death_year.nullslast", "-death_year.nullsfirst,last_name"} {
+		t.Run("Authors_"+sortBy, func(t *testing.T) {
+			all, err := d.GetAuthors(ctx, url.Values{"sort_by": {sortBy}})
+			assert.NoError(t, err)
+
+			switch sortBy {
+			case "death_year", "-death_year.nullsfirst,last_name":
+				assert.Equal(t, living, all[0].ID, "NULL should come first")
+			case "-death_year", "death_year.nullslast":
+				assert.Equal(t, living, all[len(all)-1].ID, "NULL should come last")
+			}
+
+			paged := walkPages(t, sortBy, func(params url.Values) ([]models.Author, error) {
+				return d.GetAuthors(ctx, params)
+			}, func(a models.Author, param string) *string {
+				return authorValues[param](a)
+			}, func(a models.Author) int64 { return a.ID })
+
+			assert.Equal(t, all, paged)
+		})
+	}
+
+	t.Run("BooksExt_author.last_name,-pages", func(t *testing.T) {
+		sortBy := "author.last_name,-pages"
+
+		all, err := d.GetBooksExt(ctx, url.Values{"sort_by": {sortBy}})
+		assert.NoError(t, err)
+
+		paged := walkPages(t, sortBy, func(params url.Values) ([]models.BookExt, error) {
+			return d.GetBooksExt(ctx, params)
+		}, func(b models.BookExt, param string) *string {
+			if param == "pages" {
+				return itoa(b.Pages)
+			}
+
+			return &b.Author.LastName
+		}, func(b models.BookExt) int64 { return b.ID })
+
+		assert.Equal(t, all, paged)
+	})
+
+	cursor := db.Cursor{Sort: "year", Values: []*string{itoa(1900)}, ID: 1}
+	_, err = d.GetBooks(ctx, url.Values{"after": {cursor.Encode()}, "sort_by": {"pages"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+}
+
+// walkPages fetches the records sorted by sortBy in pages of 4,
+// following the cursor built from the last record of each page.
+func walkPages[T any](
+	t *testing.T,
+	sortBy string,
+	get func(url.Values) ([]T, error),
+	value func(r T, param string) *string,
+	id func(T) int64,
+) []T {
+	t.Helper()
+
+	var out []T
+
+	keys, err := db.ParseSort(sortBy)
+	if sortBy == "" {
+		keys, err = nil, nil
+	}
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	params := url.Values{"limit": {"4"}}
+	if sortBy != "" {
+		params.Set("sort_by", sortBy)
+	}
+
+	for range 10 {
+		page, err := get(params)
+		if !assert.NoError(t, err) || len(page) == 0 {
+			return out
+		}
+
+		out = append(out, page...)
+		last := page[len(page)-1]
+
+		cursor := db.Cursor{Sort: db.FormatSort(keys), ID: id(last)}
+		for _, k := range keys {
+			cursor.Values = append(cursor.Values, value(last, k.Param))
+		}
+
+		params.Set("after", cursor.Encode())
+	}
+
+	t.Fatal("Too many pages")
+	return nil
+}
+
+func itoa(n int64) *string {
+	s := strconv.FormatInt(n, 10)
+	return &s
+}
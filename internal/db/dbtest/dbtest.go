@@ -0,0 +1,134 @@
+// Package dbtest holds a conformance suite which checks that an implementation
+// of db.DatabaseInterface behaves like the SQL backends do.
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// Factory returns a database holding exactly the sample data loaded by
+// the seed data migration. It is called at the start of every test,
+// so each of them can modify the data without affecting the others.
+type Factory func(t *testing.T) db.DatabaseInterface
+
+// RunConformance runs the conformance suite against the databases returned by newDB.
+func RunConformance(t *testing.T, newDB Factory) {
+	t.Run("GetBooks", func(t *testing.T) { testGetBooks(t, newDB(t)) })
+	t.Run("GetBooksExt", func(t *testing.T) { testGetBooksExt(t, newDB(t)) })
+	t.Run("BookCRUD", func(t *testing.T) { testBookCRUD(t, newDB(t)) })
+	t.Run("BookForeignKey", func(t *testing.T) { testBookForeignKey(t, newDB(t)) })
+	t.Run("BookYear", func(t *testing.T) { testBookYear(t, newDB(t)) })
+	t.Run("ConcurrentBookYear", func(t *testing.T) { testConcurrentBookYear(t, newDB(t)) })
+	t.Run("GetAuthors", func(t *testing.T) { testGetAuthors(t, newDB(t)) })
+	t.Run("AuthorCRUD", func(t *testing.T) { testAuthorCRUD(t, newDB(t)) })
+	t.Run("GenreCRUD", func(t *testing.T) { testGenreCRUD(t, newDB(t)) })
+	t.Run("LanguageCRUD", func(t *testing.T) { testLanguageCRUD(t, newDB(t)) })
+	t.Run("GetByName", func(t *testing.T) { testGetByName(t, newDB(t)) })
+	t.Run("EqualityCase", func(t *testing.T) { testEqualityCase(t, newDB(t)) })
+	t.Run("Count", func(t *testing.T) { testCount(t, newDB(t)) })
+	t.Run("Cursor", func(t *testing.T) { testCursor(t, newDB(t)) })
+	t.Run("Fields", func(t *testing.T) { testFields(t, newDB(t)) })
+	t.Run("Stream", func(t *testing.T) { testStream(t, newDB(t)) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, newDB(t)) })
+	t.Run("Stats", func(t *testing.T) { testStats(t, newDB(t)) })
+	t.Run("Group", func(t *testing.T) { testGroup(t, newDB(t)) })
+	t.Run("WithTx", func(t *testing.T) { testWithTx(t, newDB(t)) })
+	t.Run("ConcurrentTx", func(t *testing.T) { testConcurrentTx(t, newDB(t)) })
+	t.Run("Savepoint", func(t *testing.T) { testSavepoint(t, newDB(t)) })
+	t.Run("InsertMany", func(t *testing.T) { testInsertMany(t, newDB(t)) })
+	t.Run("ConcurrentInsertMany", func(t *testing.T) { testConcurrentInsertMany(t, newDB(t)) })
+
+	RunContract(t, newDB)
+}
+
+func testWithTx(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	err := d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		if _, err := tx.InsertLanguage(ctx, models.Language{Name: "Czeski"}); err != nil {
+			return err
+		}
+
+		if err := tx.DelBook(ctx, 1); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	langs, err := d.GetLanguages(ctx, url.Values{"name": {"Czeski"}})
+	assert.NoError(t, err)
+	assert.Empty(t, langs, "rolled back insert is visible")
+
+	_, err = d.GetBook(ctx, 1)
+	assert.NoError(t, err, "rolled back delete is visible")
+
+	err = d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		_, err := tx.InsertLanguage(ctx, models.Language{Name: "Słowacki"})
+		return err
+	})
+	assert.NoError(t, err)
+
+	langs, err = d.GetLanguages(ctx, url.Values{"name": {"Słowacki"}})
+	assert.NoError(t, err)
+	assert.Len(t, langs, 1, "committed insert is not visible")
+}
+
+// testConcurrentTx checks that a record read by id inside a transaction
+// can't be changed by another one before the first one ends, so that
+// concurrent read-modify-write transactions don't lose updates.
+func testConcurrentTx(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	const n = 8
+
+	before, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := d.WithTx(ctx, func(tx db.DatabaseInterface) error {
+				b, err := tx.GetBook(ctx, 1)
+				if err != nil {
+					return err
+				}
+
+				b.Pages++
+
+				return tx.UpdateWholeBook(ctx, 1, b)
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	after, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, before.Pages+n, after.Pages, "concurrent updates were lost")
+}
+
+// titles returns the titles of books in order.
+func titles(books []models.Book) []string {
+	var out []string
+	for _, b := range books {
+		out = append(out, b.Title)
+	}
+
+	return out
+}
@@ -0,0 +1,40 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+)
+
+// testFields checks the requested fields are filled in. Implementations
+// may fill in the other fields too, as the handlers narrow the response.
+func testFields(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	books, err := d.GetBooks(ctx, url.Values{"fields": {"title"}, "id": {"7"}})
+	assert.NoError(t, err)
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, int64(7), books[0].ID)
+		assert.Equal(t, "Solaris", books[0].Title)
+	}
+
+	ext, err := d.GetBooksExt(ctx, url.Values{"fields": {"author.first_name, genre.name"}, "id": {"7"}})
+	assert.NoError(t, err)
+	if assert.Len(t, ext, 1) {
+		assert.Equal(t, "Stanisław", ext[0].Author.FirstName)
+		assert.Equal(t, "Powieść", ext[0].Genre.Name)
+	}
+
+	authors, err := d.GetAuthors(ctx, url.Values{"fields": {"death_year"}, "death_year": {"null"}})
+	assert.NoError(t, err)
+	assert.Empty(t, authors)
+
+	_, err = d.GetGenres(ctx, url.Values{"fields": {"title"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+
+	_, err = d.GetLanguages(ctx, url.Values{"fields": {""}})
+	assert.ErrorIs(t, err, db.ErrParam)
+}
@@ -0,0 +1,55 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testGenreCRUD(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	all, err := d.GetGenres(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 9)
+
+	found, err := d.GetGenres(ctx, url.Values{"name": {"EPOPEJA"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Genre{{ID: 2, Name: "Epopeja"}}, found)
+
+	found, err = d.GetGenres(ctx, url.Values{"name.endswith": {"ja"}, "sort_by": {"id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Genre{{ID: 2, Name: "Epopeja"}}, found)
+
+	_, err = d.GetGenres(ctx, url.Values{"title": {"Nowela"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+
+	id, err := d.InsertGenre(ctx, models.Genre{Name: "Reportaż"})
+	assert.NoError(t, err)
+	assert.Greater(t, id, int64(9))
+
+	got, err := d.GetGenre(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Genre{ID: id, Name: "Reportaż"}, got)
+
+	err = d.UpdateWholeGenre(ctx, id, models.Genre{Name: "Esej"})
+	assert.NoError(t, err)
+
+	got, err = d.GetGenre(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Genre{ID: id, Name: "Esej"}, got)
+
+	assert.NoError(t, d.DelGenre(ctx, id))
+
+	_, err = d.GetGenre(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound)
+
+	assert.ErrorIs(t, d.DelGenre(ctx, 2), db.ErrForeignKey)
+
+	_, err = d.GetGenre(ctx, 2)
+	assert.NoError(t, err, "a genre with books was deleted")
+}
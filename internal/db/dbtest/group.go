@@ -0,0 +1,132 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// testGroup checks the aggregations against the ones computed
+// from the extended books and the validation of the parameters.
+func testGroup(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	books, err := d.GetBooksExt(ctx, url.Values{})
+	assert.NoError(t, err)
+
+	t.Run("Aggregates", func(t *testing.T) {
+		groups, err := d.Group(ctx, "books", url.Values{
+			"group_by": {"genre.name"},
+			"agg":      {"count,avg(pages),max(year)"},
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, groups)
+
+		for _, g := range groups {
+			var (
+				n, pages int64
+				maxYear  int64
+			)
+
+			for _, b := range books {
+				if b.Genre.Name == g.Group["genre.name"] {
+					n++
+					pages += b.Pages
+					maxYear = max(maxYear, b.Year)
+				}
+			}
+
+			assert.Equal(t, n, g.Aggregates["count"], "count of %v", g.Group)
+			assert.InDelta(t, float64(pages)/float64(n), g.Aggregates["avg(pages)"], 0.001, "avg of %v", g.Group)
+			assert.Equal(t, maxYear, g.Aggregates["max(year)"], "max of %v", g.Group)
+		}
+	})
+
+	t.Run("SortAndPaginate", func(t *testing.T) {
+		params := url.Values{"group_by": {"language"}, "sort_by": {"-count,language"}, "limit": {"2"}}
+
+		groups, err := d.Group(ctx, "books", params)
+		assert.NoError(t, err)
+
+		if assert.Len(t, groups, 2) {
+			assert.Equal(t, map[string]any{"language": int64(2)}, groups[0].Group, "most books are in Polish")
+			assert.GreaterOrEqual(t, groups[0].Aggregates["count"], groups[1].Aggregates["count"])
+		}
+
+		n, err := d.CountGroups(ctx, "books", params)
+		assert.NoError(t, err)
+
+		languages := map[int64]bool{}
+		for _, b := range books {
+			languages[b.Language.ID] = true
+		}
+
+		assert.Equal(t, int64(len(languages)), n)
+	})
+
+	t.Run("Having", func(t *testing.T) {
+		params := url.Values{"group_by": {"author.last_name"}, "agg": {"count,min(year)"}, "having.count.gte": {"2"}, "year.gt": {"1900"}}
+
+		groups, err := d.Group(ctx, "books", params)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, groups)
+
+		for _, g := range groups {
+			assert.GreaterOrEqual(t, g.Aggregates["count"], int64(2))
+			assert.Greater(t, g.Aggregates["min(year)"], int64(1900))
+		}
+
+		n, err := d.CountGroups(ctx, "books", params)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(groups)), n)
+	})
+
+	t.Run("NoGroupBy", func(t *testing.T) {
+		groups, err := d.Group(ctx, "books", url.Values{"agg": {"count,sum(pages)"}, "title": {"Brak"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Group{{
+			Group:      map[string]any{},
+			Aggregates: map[string]any{"count": int64(0), "sum(pages)": nil},
+		}}, groups, "aggregating no records should return a single group")
+	})
+
+	t.Run("NullGroup", func(t *testing.T) {
+		_, err := d.InsertAuthor(ctx, models.Author{FirstName: "Wisława", LastName: "Szymborska", BirthYear: 1923})
+		assert.NoError(t, err)
+
+		groups, err := d.Group(ctx, "authors", url.Values{"group_by": {"death_year"}, "agg": {"count,max(last_name)"}})
+		assert.NoError(t, err)
+
+		if assert.NotEmpty(t, groups) {
+			assert.Equal(t, models.Group{
+				Group:      map[string]any{"death_year": nil},
+				Aggregates: map[string]any{"count": int64(1), "max(last_name)": "Szymborska"},
+			}, groups[0], "NULL should be sorted first")
+		}
+
+		assert.True(t, slices.IsSortedFunc(groups[1:], func(a, b models.Group) int {
+			return int(a.Group["death_year"].(int64) - b.Group["death_year"].(int64))
+		}))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for name, params := range map[string]url.Values{
+			"UnknownField":      {"group_by": {"isbn"}},
+			"SumOfText":         {"agg": {"sum(title)"}},
+			"UnknownFunc":       {"agg": {"median(pages)"}},
+			"HavingNotInAgg":    {"group_by": {"genre"}, "having.avg(pages).gt": {"100"}},
+			"HavingNotNumber":   {"group_by": {"genre"}, "having.count.gt": {"many"}},
+			"SortByNotGrouped":  {"group_by": {"genre"}, "sort_by": {"title"}},
+			"Fields":            {"group_by": {"genre"}, "fields": {"genre"}},
+			"RepeatedAggregate": {"agg": {"count,count"}},
+		} {
+			_, err := d.Group(ctx, "books", params)
+			assert.ErrorIs(t, err, db.ErrParam, name)
+		}
+	})
+}
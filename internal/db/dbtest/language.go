@@ -0,0 +1,55 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testLanguageCRUD(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	all, err := d.GetLanguages(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 11)
+
+	found, err := d.GetLanguages(ctx, url.Values{"name": {"POLSKI"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Language{{ID: 2, Name: "Polski"}}, found)
+
+	found, err = d.GetLanguages(ctx, url.Values{"name.startswith": {"A"}, "sort_by": {"id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Language{{ID: 3, Name: "Angielski"}, {ID: 9, Name: "Arabski"}}, found)
+
+	_, err = d.GetLanguages(ctx, url.Values{"title": {"Polski"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+
+	id, err := d.InsertLanguage(ctx, models.Language{Name: "Czeski"})
+	assert.NoError(t, err)
+	assert.Greater(t, id, int64(11))
+
+	got, err := d.GetLanguage(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Language{ID: id, Name: "Czeski"}, got)
+
+	err = d.UpdateWholeLanguage(ctx, id, models.Language{Name: "Słowacki"})
+	assert.NoError(t, err)
+
+	got, err = d.GetLanguage(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Language{ID: id, Name: "Słowacki"}, got)
+
+	assert.NoError(t, d.DelLanguage(ctx, id))
+
+	_, err = d.GetLanguage(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound)
+
+	assert.ErrorIs(t, d.DelLanguage(ctx, 2), db.ErrForeignKey)
+
+	_, err = d.GetLanguage(ctx, 2)
+	assert.NoError(t, err, "a language with books was deleted")
+}
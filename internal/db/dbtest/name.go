@@ -0,0 +1,41 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testGetByName(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	author, err := d.GetAuthorByName(ctx, "stanisław", "LEM")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), author.ID)
+
+	genre, err := d.GetGenreByName(ctx, "epopeja")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Genre{ID: 2, Name: "Epopeja"}, genre)
+
+	language, err := d.GetLanguageByName(ctx, "POLSKI")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), language.ID)
+
+	// The names are matched literally, not as filtering values or patterns.
+	_, err = d.GetLanguageByName(ctx, "Pol%")
+	assert.ErrorIs(t, err, db.ErrNotFound)
+	_, err = d.GetAuthorByName(ctx, "Stanisław", "Lem,Prus")
+	assert.ErrorIs(t, err, db.ErrNotFound)
+	_, err = d.GetGenreByName(ctx, "null")
+	assert.ErrorIs(t, err, db.ErrNotFound)
+
+	id, err := d.InsertGenre(ctx, models.Genre{Name: "null"})
+	assert.NoError(t, err)
+
+	genre, err = d.GetGenreByName(ctx, "NULL")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Genre{ID: id, Name: "null"}, genre)
+}
@@ -0,0 +1,59 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func testSearch(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	hits, err := d.Search(ctx, "Solaris", 0)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, hits) {
+		assert.Equal(t, models.HitBook, hits[0].Type)
+		assert.Equal(t, "Solaris", hits[0].Book.Title)
+		assert.Equal(t, "Lem", hits[0].Book.Author.LastName, "book hits are extended")
+	}
+
+	hits, err = d.Search(ctx, "brzechwa", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.HitBook, models.HitAuthor}, hitTypes(hits))
+
+	hits, err = d.Search(ctx, "dramat POLSKI", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.HitGenre, models.HitLanguage}, hitTypes(hits))
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "Dramat", hits[0].Genre.Name)
+		assert.Equal(t, "Polski", hits[1].Language.Name)
+	}
+
+	hits, err = d.Search(ctx, "Lem", 1)
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 1) {
+		assert.Equal(t, models.HitAuthor, hits[0].Type)
+		assert.Equal(t, "Stanisław", hits[0].Author.FirstName)
+	}
+
+	hits, err = d.Search(ctx, "xyzzy", 0)
+	assert.NoError(t, err)
+	assert.NotNil(t, hits)
+	assert.Empty(t, hits)
+
+	_, err = d.Search(ctx, "  ", 0)
+	assert.ErrorIs(t, err, db.ErrParam)
+}
+
+// hitTypes returns the types of hits in order.
+func hitTypes(hits []models.SearchHit) []string {
+	var out []string
+	for _, h := range hits {
+		out = append(out, h.Type)
+	}
+
+	return out
+}
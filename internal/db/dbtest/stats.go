@@ -0,0 +1,110 @@
+package dbtest
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// testStats checks the aggregations against the ones computed
+// from the books returned by GetBooks for the same parameters.
+func testStats(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+
+	tests := map[string]url.Values{
+		"All":     {},
+		"Filter":  {"year.gte": {"1900"}},
+		"OrGroup": {"or": {"(language:2,pages.lt:100)"}},
+		"Empty":   {"title": {"Brak"}},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			books, err := d.GetBooks(ctx, params)
+			assert.NoError(t, err)
+
+			genres, err := d.BooksPerGenre(ctx, params)
+			assert.NoError(t, err)
+			assert.Equal(t, countBooks(books, func(b models.Book) int64 { return b.Genre }), bookCounts(genres))
+			assert.True(t, slices.IsSortedFunc(genres, func(a, b models.BookCount) int { return int(b.Books - a.Books) }))
+
+			languages, err := d.BooksPerLanguage(ctx, params)
+			assert.NoError(t, err)
+			assert.Equal(t, countBooks(books, func(b models.Book) int64 { return b.Language }), bookCounts(languages))
+
+			authors, err := d.BooksPerAuthor(ctx, params)
+			assert.NoError(t, err)
+
+			perAuthor := map[int64]int64{}
+			for _, a := range authors {
+				perAuthor[a.ID] = a.Books
+
+				for _, b := range books {
+					if b.Author == a.ID {
+						assert.True(t, a.FirstYear <= b.Year && b.Year <= a.LastYear, "%d should be within the years of author %d", b.Year, a.ID)
+					}
+				}
+			}
+
+			assert.Equal(t, countBooks(books, func(b models.Book) int64 { return b.Author }), perAuthor)
+
+			decades, err := d.BooksPerDecade(ctx, params)
+			assert.NoError(t, err)
+
+			perDecade := map[int64]int64{}
+			for _, dc := range decades {
+				perDecade[dc.Decade] = dc.Books
+			}
+
+			assert.Equal(t, countBooks(books, func(b models.Book) int64 { return b.Year / 10 * 10 }), perDecade)
+			assert.True(t, slices.IsSortedFunc(decades, func(a, b models.DecadeCount) int { return int(a.Decade - b.Decade) }))
+
+			pages, err := d.PageStats(ctx, params)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(books)), pages.Books)
+		})
+	}
+
+	lem, err := d.BooksPerAuthor(ctx, url.Values{"author": {"5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AuthorStats{
+		{ID: 5, FirstName: "Stanisław", LastName: "Lem", Books: 3, FirstYear: 1961, LastYear: 1987},
+	}, lem)
+
+	pages, err := d.PageStats(ctx, url.Values{"author": {"5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PageStats{Books: 3, Min: 340, Max: 400, Average: 372, Median: 376}, pages)
+
+	pages, err = d.PageStats(ctx, url.Values{"id.in": {"7,8"}})
+	assert.NoError(t, err)
+	assert.InDelta(t, pages.Average, pages.Median, 0.001, "the median of two books is their average")
+
+	_, err = d.BooksPerGenre(ctx, url.Values{"sort_by": {"year"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+
+	_, err = d.PageStats(ctx, url.Values{"name": {"Dramat"}})
+	assert.ErrorIs(t, err, db.ErrParam)
+}
+
+func countBooks(books []models.Book, key func(models.Book) int64) map[int64]int64 {
+	counts := map[int64]int64{}
+	for _, b := range books {
+		counts[key(b)]++
+	}
+
+	return counts
+}
+
+func bookCounts(counts []models.BookCount) map[int64]int64 {
+	out := map[int64]int64{}
+	for _, c := range counts {
+		out[c.ID] = c.Books
+	}
+
+	return out
+}
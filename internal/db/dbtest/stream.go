@@ -0,0 +1,84 @@
+package dbtest
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+)
+
+// collect returns the records yielded by seq up to the first error.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	records := []T{}
+
+	for r, err := range seq {
+		if err != nil {
+			return records, err
+		}
+
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// testStream checks that the Stream methods yield the records returned
+// by the Get methods and release the query when the iteration is stopped.
+func testStream(t *testing.T, d db.DatabaseInterface) {
+	ctx := context.Background()
+	params := func() url.Values {
+		return url.Values{"sort_by": {"-year,title"}, "year.gt": {"1900"}}
+	}
+
+	books, err := d.GetBooks(ctx, params())
+	assert.NoError(t, err)
+	streamed, err := collect(d.StreamBooks(ctx, params()))
+	assert.NoError(t, err)
+	assert.Equal(t, books, streamed, "StreamBooks")
+
+	extParams := url.Values{"author.last_name": {"Lem"}, "fields": {"title,genre.name"}}
+	booksExt, err := d.GetBooksExt(ctx, extParams)
+	assert.NoError(t, err)
+	streamedExt, err := collect(d.StreamBooksExt(ctx, extParams))
+	assert.NoError(t, err)
+	assert.Equal(t, booksExt, streamedExt, "StreamBooksExt")
+
+	authors, err := d.GetAuthors(ctx, url.Values{})
+	assert.NoError(t, err)
+	streamedAuthors, err := collect(d.StreamAuthors(ctx, url.Values{}))
+	assert.NoError(t, err)
+	assert.Equal(t, authors, streamedAuthors, "StreamAuthors")
+
+	genres, err := d.GetGenres(ctx, url.Values{"limit": {"3"}})
+	assert.NoError(t, err)
+	streamedGenres, err := collect(d.StreamGenres(ctx, url.Values{"limit": {"3"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, genres, streamedGenres, "StreamGenres")
+
+	languages, err := d.GetLanguages(ctx, url.Values{"name.startswith": {"P"}})
+	assert.NoError(t, err)
+	streamedLanguages, err := collect(d.StreamLanguages(ctx, url.Values{"name.startswith": {"P"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, languages, streamedLanguages, "StreamLanguages")
+
+	n := 0
+	for _, err := range d.StreamBooks(ctx, url.Values{}) {
+		assert.NoError(t, err)
+
+		if n++; n == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, 2, n)
+
+	_, err = d.GetBook(ctx, 1)
+	assert.NoError(t, err, "the database should be usable after stopping a stream")
+
+	streamed, err = collect(d.StreamBooks(ctx, url.Values{"isbn": {"1"}}))
+	assert.ErrorIs(t, err, db.ErrParam)
+	assert.Empty(t, streamed)
+}
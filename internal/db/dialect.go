@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationLockName identifies the advisory lock held while migrating.
+const migrationLockName = "pawrest_schema_migrations"
+
+// migrationLockTimeout limits how long a replica waits for another one
+// to finish migrating before giving up.
+const migrationLockTimeout = 5 * time.Minute
+
+// dialect covers the differences between the supported SQL backends.
+// Queries in this package, including the filters built by AssembleFilter,
+// are written with ? placeholders and LIMIT ? OFFSET ? pagination, which every
+// backend understands once the placeholders are rebound.
+type dialect interface {
+	// rebind rewrites ? placeholders into the syntax used by the backend.
+	rebind(query string) string
+	// returningID reports whether inserted ids are read with RETURNING id
+	// instead of sql.Result.LastInsertId.
+	returningID() bool
+	// fullText reports whether the books and authors have
+	// full-text indexes which can be searched with MATCH.
+	fullText() bool
+	// bufferStreams reports whether the Stream methods read all of the
+	// records before yielding them, as the backend has a single connection
+	// which a stream would hold for as long as the client reads it.
+	bufferStreams() bool
+	// like renders a case-sensitive match of column against the LIKE
+	// pattern, whose wildcards are escaped with LikeEscape.
+	like(column, pattern string) (string, []any)
+	// lockRows is appended to the reads of records by id inside
+	// transactions, locking the rows read until the transaction ends.
+	lockRows() string
+	// name is the directory with the backend's migrations.
+	name() string
+	// lock acquires the migration lock on conn, blocking until it is free.
+	lock(ctx context.Context, conn *sql.Conn) error
+	// unlock releases the lock acquired by lock.
+	unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) rebind(query string) string { return query }
+func (mysqlDialect) returningID() bool          { return false }
+func (mysqlDialect) name() string               { return "mysql" }
+func (mysqlDialect) fullText() bool             { return true }
+func (mysqlDialect) bufferStreams() bool        { return false }
+func (mysqlDialect) lockRows() string           { return " FOR UPDATE" }
+
+// The columns have case-insensitive collations, so the binary one is used.
+// The match by the collation of the column comes first, as it can use its
+// index for the prefix of the pattern, unlike the one with another collation.
+func (mysqlDialect) like(column, pattern string) (string, []any) {
+	like := " LIKE ? ESCAPE '" + string(LikeEscape) + "'"
+	return "(" + column + like + " AND " + column + " COLLATE utf8mb4_bin" + like + ")", []any{pattern, pattern}
+}
+
+func (mysqlDialect) lock(ctx context.Context, conn *sql.Conn) error {
+	var ok sql.NullInt64
+
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)",
+		migrationLockName, int(migrationLockTimeout.Seconds())).Scan(&ok)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if ok.Int64 != 1 {
+		return errors.New("timed out waiting for the migration lock")
+	}
+
+	return nil
+}
+
+func (mysqlDialect) unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "DO RELEASE_LOCK(?)", migrationLockName)
+	return err
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) rebind(query string) string { return query }
+func (sqliteDialect) returningID() bool          { return false }
+func (sqliteDialect) name() string               { return "sqlite" }
+func (sqliteDialect) fullText() bool             { return false }
+func (sqliteDialect) bufferStreams() bool        { return true }
+
+// The single connection of SQLite already runs one transaction at a time.
+func (sqliteDialect) lockRows() string { return "" }
+
+// LIKE ignores the case of ASCII letters on SQLite, so the pattern
+// is matched by GLOB, which doesn't.
+func (sqliteDialect) like(column, pattern string) (string, []any) {
+	return column + " GLOB ?", []any{likeToGlob(pattern)}
+}
+
+// likeToGlob translates the LIKE pattern, whose wildcards are escaped with
+// LikeEscape, into a GLOB pattern matching the same text. The characters
+// special to GLOB are matched literally by single character classes.
+func likeToGlob(pattern string) string {
+	var b strings.Builder
+
+	escaped := false
+
+	for _, r := range pattern {
+		switch {
+		case !escaped && r == LikeEscape:
+			escaped = true
+			continue
+		case !escaped && r == '%':
+			b.WriteByte('*')
+		case !escaped && r == '_':
+			b.WriteByte('?')
+		case r == '*' || r == '?' || r == '[':
+			b.WriteString("[" + string(r) + "]")
+		default:
+			b.WriteRune(r)
+		}
+
+		escaped = false
+	}
+
+	return b.String()
+}
+
+// SQLite has no advisory locks, but the pool holds a single connection
+// and the database file is locked by every writing transaction.
+func (sqliteDialect) lock(context.Context, *sql.Conn) error   { return nil }
+func (sqliteDialect) unlock(context.Context, *sql.Conn) error { return nil }
+
+type postgresDialect struct{}
+
+func (postgresDialect) returningID() bool   { return true }
+func (postgresDialect) name() string        { return "postgres" }
+func (postgresDialect) fullText() bool      { return false }
+func (postgresDialect) bufferStreams() bool { return false }
+func (postgresDialect) lockRows() string    { return " FOR UPDATE" }
+
+// LIKE ignores case on the CITEXT columns, but not on their text casts.
+func (postgresDialect) like(column, pattern string) (string, []any) {
+	return Condition{Column: column + "::text", Operator: "LIKE", Value: pattern}.SQL()
+}
+
+func (postgresDialect) lock(ctx context.Context, conn *sql.Conn) error {
+	ctx, cancel := context.WithTimeout(ctx, migrationLockTimeout)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return nil
+}
+
+func (postgresDialect) unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName)
+	return err
+}
+
+// rebind replaces every ? outside of a quoted literal with $1, $2, ...
+func (postgresDialect) rebind(query string) string {
+	var (
+		b     strings.Builder
+		n     int
+		quote rune
+	)
+
+	b.Grow(len(query) + 8)
+
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	tests := map[string]struct {
+		give string
+		want string
+	}{
+		"NoPlaceholders": {
+			give: "SELECT id FROM ksiazka",
+			want: "SELECT id FROM ksiazka",
+		},
+		"Filter": {
+			give: "SELECT id FROM ksiazka WHERE rok_wydania > ? AND tytul <> ? ORDER BY id LIMIT ? OFFSET ?",
+			want: "SELECT id FROM ksiazka WHERE rok_wydania > $1 AND tytul <> $2 ORDER BY id LIMIT $3 OFFSET $4",
+		},
+		"QuotedLiterals": {
+			give: `SELECT '?', "a?b" FROM ksiazka WHERE id = ?`,
+			want: `SELECT '?', "a?b" FROM ksiazka WHERE id = $1`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, postgresDialect{}.rebind(tt.give))
+			assert.Equal(t, tt.give, mysqlDialect{}.rebind(tt.give))
+			assert.Equal(t, tt.give, sqliteDialect{}.rebind(tt.give))
+		})
+	}
+}
+
+func TestLikeToGlob(t *testing.T) {
+	tests := map[string]struct {
+		give string
+		want string
+	}{
+		"Wildcards": {
+			give: "P_to%",
+			want: "P?to*",
+		},
+		"EscapedWildcards": {
+			give: "%50!%!_off!!%",
+			want: "*50%_off!*",
+		},
+		"GlobSpecialCharacters": {
+			give: "%a*b?[c]%",
+			want: "*a[*]b[?][[]c]*",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, likeToGlob(tt.give))
+		})
+	}
+}
+
+func TestDialectLike(t *testing.T) {
+	tests := map[string]struct {
+		give     dialect
+		wantSQL  string
+		wantArgs []any
+	}{
+		"MySQL": {
+			give:     mysqlDialect{},
+			wantSQL:  "(k.tytul LIKE ? ESCAPE '!' AND k.tytul COLLATE utf8mb4_bin LIKE ? ESCAPE '!')",
+			wantArgs: []any{"P_to%", "P_to%"},
+		},
+		"SQLite": {
+			give:     sqliteDialect{},
+			wantSQL:  "k.tytul GLOB ?",
+			wantArgs: []any{"P?to*"},
+		},
+		"Postgres": {
+			give:     postgresDialect{},
+			wantSQL:  "k.tytul::text LIKE ? ESCAPE '!'",
+			wantArgs: []any{"P_to%"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sql, args := tt.give.like("k.tytul", "P_to%")
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestIsErrForeignKey(t *testing.T) {
+	tests := map[string]struct {
+		give error
+		want bool
+	}{
+		"MySQLInsert": {
+			give: &mysql.MySQLError{Number: 1452},
+			want: true,
+		},
+		"MySQLDelete": {
+			give: &mysql.MySQLError{Number: 1451},
+			want: true,
+		},
+		"MySQLOther": {
+			give: &mysql.MySQLError{Number: 1062},
+		},
+		"PostgresWrapped": {
+			give: fmt.Errorf("exec: %w", &pq.Error{Code: "23503"}),
+			want: true,
+		},
+		"PostgresUniqueViolation": {
+			give: &pq.Error{Code: "23505"},
+		},
+		"Other": {
+			give: errors.New("foreign key"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isErrForeignKey(tt.give))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// column is a selectable field of T: the query parameter naming it
+// and the pointer to the field its value is scanned into.
+type column[T any] struct {
+	param string
+	dest  func(*T) any
+}
+
+// parseFields parses the comma-separated list of the fields parameter,
+// which may only name the allowed parameters.
+func parseFields(values []string, allowedParams map[string]string) ([]string, error) {
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%w: the fields parameter was provided more than once", ErrParam)
+	}
+
+	var fields []string
+
+	for field := range strings.SplitSeq(values[0], ",") {
+		field = strings.TrimSpace(field)
+
+		if field == "" {
+			return nil, fmt.Errorf("%w: the fields parameter has an empty field", ErrParam)
+		}
+
+		if _, allowed := allowedParams[field]; !allowed {
+			return nil, fmt.Errorf("%w: an unknown field %q was requested", ErrParam, field)
+		}
+
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,511 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Condition is a single comparison parsed from the query parameters.
+type Condition struct {
+	Param    string   // query parameter name without the operator suffix
+	Column   string   // column the parameter is mapped to
+	Operator string   // =, >, <, >=, <=, <>, IS, IS NOT, IN, BETWEEN, LIKE or ILIKE
+	Value    string   // unused by IS and IS NOT, which compare against NULL
+	Values   []string // list compared by IN, or the two bounds of BETWEEN
+}
+
+// Filter is the parsed form of the filtering, sorting
+// and pagination query parameters.
+type Filter struct {
+	Conditions []Condition
+	// OrGroups are satisfied when any of their conditions is.
+	// Like Conditions, all of the groups must be satisfied.
+	OrGroups [][]Condition
+	Sort     []SortKey
+	// IDColumn breaks ties when sorting, which keeps the order stable.
+	IDColumn string
+	// After continues keyset pagination from a cursor instead of an offset.
+	After *Cursor
+	// Fields lists the requested fields, all of them when empty.
+	Fields []string
+	Limit  int64 // -1 when no limit was provided
+	Offset int64
+	// dialect renders the LIKE conditions of the SQL backends, which
+	// differ in how they match case-sensitively. Without it they're
+	// rendered as standard LIKE, see Condition.SQL.
+	dialect dialect
+}
+
+// ParseFilter validates params against allowedParams, which maps query
+// parameter names to column names, and returns the parsed filter.
+func ParseFilter(params url.Values, allowedParams map[string]string) (Filter, error) {
+	f := Filter{Limit: -1, IDColumn: allowedParams["id"]}
+
+	limit, hasLimit := params["limit"]
+	offset, hasOffset := params["offset"]
+	after, hasAfter := params["after"]
+
+	if !hasLimit && hasOffset {
+		return Filter{}, fmt.Errorf("%w: a limit must be provided when using an offset", ErrParam)
+	}
+
+	if hasAfter && hasOffset {
+		return Filter{}, fmt.Errorf("%w: an offset can't be used together with a cursor", ErrParam)
+	}
+
+	for key, valSlice := range params {
+		if key == "limit" || key == "offset" || key == "sort_by" || key == "after" || key == "fields" ||
+			key == "extend" || key == "expand" || key == "envelope" || key == "format" {
+			continue
+		}
+
+		if key == "or" {
+			for _, v := range valSlice {
+				group, err := parseOrGroup(v, allowedParams)
+				if err != nil {
+					return Filter{}, err
+				}
+
+				f.OrGroups = append(f.OrGroups, group)
+			}
+
+			continue
+		}
+
+		value := ""
+		if len(valSlice) > 0 {
+			value = valSlice[0]
+		}
+
+		cond, err := parseCondition(key, value, allowedParams, false)
+		if err != nil {
+			return Filter{}, err
+		}
+
+		if len(valSlice) > 1 {
+			return Filter{}, fmt.Errorf("%w: too many parameters were provided for a single column", ErrParam)
+		}
+
+		f.Conditions = append(f.Conditions, cond)
+	}
+
+	if sort, hasSort := params["sort_by"]; hasSort {
+		if len(sort) == 0 {
+			return Filter{}, fmt.Errorf("%w: provided column is empty", ErrParam)
+		}
+
+		if len(sort) > 1 {
+			return Filter{}, fmt.Errorf("%w: the sort_by parameter was provided more than once", ErrParam)
+		}
+
+		keys, err := ParseSort(sort[0])
+		if err != nil {
+			return Filter{}, err
+		}
+
+		for i, k := range keys {
+			columnName, allowed := allowedParams[k.Param]
+			if !allowed {
+				return Filter{}, fmt.Errorf("%w: an unknown column was provided", ErrParam)
+			}
+
+			keys[i].Column = columnName
+		}
+
+		f.Sort = keys
+	}
+
+	if fields, ok := params["fields"]; ok {
+		var err error
+		if f.Fields, err = parseFields(fields, allowedParams); err != nil {
+			return Filter{}, err
+		}
+	}
+
+	if hasAfter {
+		if f.IDColumn == "" {
+			return Filter{}, fmt.Errorf("%w: cursors aren't supported here", ErrParam)
+		}
+
+		c, err := DecodeCursor(after[0])
+		if err != nil {
+			return Filter{}, err
+		}
+
+		if c.Sort != FormatSort(f.Sort) || len(c.Values) != len(f.Sort) {
+			return Filter{}, fmt.Errorf("%w: the cursor was created with a different sort_by parameter", ErrParam)
+		}
+
+		f.After = &c
+	}
+
+	if hasLimit {
+		n, err := strconv.ParseInt(limit[0], 10, 64)
+		if err != nil || n < 0 {
+			return Filter{}, fmt.Errorf("%w: limit must be a non-negative integer", ErrParam)
+		}
+
+		f.Limit = n
+	}
+
+	if hasOffset {
+		n, err := strconv.ParseInt(offset[0], 10, 64)
+		if err != nil || n < 0 {
+			return Filter{}, fmt.Errorf("%w: offset must be a non-negative integer", ErrParam)
+		}
+
+		f.Offset = n
+	}
+
+	return f, nil
+}
+
+// operators maps the query parameter suffixes to SQL operators.
+// Parameters without a suffix compare for equality.
+var operators = map[string]string{
+	".eq":      "=",
+	".gt":      ">",
+	".lt":      "<",
+	".gte":     ">=",
+	".lte":     "<=",
+	".neq":     "<>",
+	".in":      "IN",
+	".between": "BETWEEN",
+	// Text matching operators, which compare using LIKE patterns. LIKE
+	// matches case-sensitively on every backend, whatever the collation.
+	// ILIKE isn't valid SQL, it's rendered as LIKE on lowercased operands,
+	// which can't use the indexes of the column, so only .icontains does so.
+	".like":       "LIKE",
+	".contains":   "LIKE",
+	".startswith": "LIKE",
+	".endswith":   "LIKE",
+	".icontains":  "ILIKE",
+}
+
+// likePatterns build the LIKE patterns of the text matching operators.
+// Only .like accepts wildcards from the user.
+var likePatterns = map[string]func(value string) string{
+	".like":       func(v string) string { return v },
+	".contains":   func(v string) string { return "%" + EscapeLike(v) + "%" },
+	".startswith": func(v string) string { return EscapeLike(v) + "%" },
+	".endswith":   func(v string) string { return "%" + EscapeLike(v) },
+	".icontains":  func(v string) string { return "%" + EscapeLike(v) + "%" },
+}
+
+// textParams are the parameters which text matching operators can be used on.
+var textParams = map[string]bool{
+	"title":             true,
+	"name":              true,
+	"first_name":        true,
+	"last_name":         true,
+	"author.first_name": true,
+	"author.last_name":  true,
+	"genre.name":        true,
+	"language.name":     true,
+}
+
+// LikeEscape is the character escaping wildcards in LIKE patterns. Backslash
+// is avoided as MySQL and the other backends treat it differently in literals.
+const LikeEscape = '!'
+
+// EscapeLike escapes the LIKE wildcards in s so that it matches literally.
+func EscapeLike(s string) string {
+	r := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return r.Replace(s)
+}
+
+// parseCondition parses a single filtering parameter such as year.gt=1900.
+// IN and BETWEEN take a comma separated list of values, optionally wrapped
+// in parentheses, in which a backslash escapes the next character.
+// Escapes in single values are only resolved when escaped is set.
+func parseCondition(key, value string, allowedParams map[string]string, escaped bool) (Condition, error) {
+	operator := "="
+	opSuffix := ""
+
+	for suffix, sqlOp := range operators {
+		if before, found := strings.CutSuffix(key, suffix); found {
+			key = before
+			operator = sqlOp
+			opSuffix = suffix
+			break
+		}
+	}
+
+	columnName, allowed := allowedParams[key]
+	if !allowed {
+		return Condition{}, fmt.Errorf("%w: an unknown parameter was provided", ErrParam)
+	}
+
+	if value == "" {
+		return Condition{}, fmt.Errorf("%w: provided parameter is empty", ErrParam)
+	}
+
+	cond := Condition{Param: key, Column: columnName, Operator: operator}
+
+	if operator == "IN" || operator == "BETWEEN" {
+		if inner, ok := strings.CutPrefix(value, "("); ok {
+			value, ok = strings.CutSuffix(inner, ")")
+			if !ok {
+				return Condition{}, fmt.Errorf("%w: unbalanced parentheses in %q", ErrParam, key)
+			}
+		}
+
+		for _, v := range splitList(value) {
+			v = unescape(strings.TrimSpace(v))
+
+			if v == "" || strings.ToLower(v) == "null" {
+				return Condition{}, fmt.Errorf("%w: lists cannot contain empty or null values", ErrParam)
+			}
+
+			cond.Values = append(cond.Values, v)
+		}
+
+		if operator == "BETWEEN" && len(cond.Values) != 2 {
+			return Condition{}, fmt.Errorf("%w: between requires exactly two values", ErrParam)
+		}
+
+		return cond, nil
+	}
+
+	if escaped {
+		value = unescape(value)
+	}
+
+	if operator == "LIKE" || operator == "ILIKE" {
+		if !textParams[key] {
+			return Condition{}, fmt.Errorf("%w: text matching operators can only be used on text fields", ErrParam)
+		}
+
+		cond.Value = likePatterns[opSuffix](value)
+
+		return cond, nil
+	}
+
+	cond.Value = value
+
+	if strings.ToLower(value) == "null" {
+		if operator != "=" && operator != "<>" {
+			return Condition{}, fmt.Errorf("%w: cannot use other operations than equal or not equal on null", ErrParam)
+		}
+
+		if operator == "=" {
+			cond.Operator = "IS"
+		} else {
+			cond.Operator = "IS NOT"
+		}
+
+		cond.Value = ""
+	}
+
+	return cond, nil
+}
+
+// parseOrGroup parses the value of an or parameter, a parenthesized comma
+// separated list of conditions written as key:value,
+// e.g. (genre.name.eq:Dramat,year.in:(1990,1995)).
+func parseOrGroup(value string, allowedParams map[string]string) ([]Condition, error) {
+	inner, ok := strings.CutPrefix(value, "(")
+	if ok {
+		inner, ok = strings.CutSuffix(inner, ")")
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: or groups must be wrapped in parentheses", ErrParam)
+	}
+
+	var group []Condition
+
+	for _, item := range splitList(inner) {
+		key, val, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found {
+			return nil, fmt.Errorf("%w: or group conditions must be written as key:value", ErrParam)
+		}
+
+		cond, err := parseCondition(key, val, allowedParams, true)
+		if err != nil {
+			return nil, err
+		}
+
+		group = append(group, cond)
+	}
+
+	return group, nil
+}
+
+// splitList splits s on the commas which are neither escaped
+// with a backslash nor nested in parentheses. Escapes are kept.
+func splitList(s string) []string {
+	var (
+		items []string
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(items, s[start:])
+}
+
+// escapeListValue escapes the characters which splitList
+// and parseOrGroup would otherwise interpret.
+func escapeListValue(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ",", `\,`, "(", `\(`, ")", `\)`)
+	return r.Replace(s)
+}
+
+// unescape removes the backslashes escaping the following characters.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// Where renders the conditions of the filter as a WHERE clause
+// with ? placeholders and returns it with its arguments.
+func (f Filter) Where() (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	for _, c := range f.Conditions {
+		cond, condArgs := f.conditionSQL(c)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	for _, group := range f.OrGroups {
+		var alternatives []string
+
+		for _, c := range group {
+			cond, condArgs := f.conditionSQL(c)
+			alternatives = append(alternatives, cond)
+			args = append(args, condArgs...)
+		}
+
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// conditionSQL renders c like Condition.SQL, leaving LIKE to the dialect.
+func (f Filter) conditionSQL(c Condition) (string, []any) {
+	if c.Operator == "LIKE" && f.dialect != nil {
+		return f.dialect.like(c.Column, c.Value)
+	}
+
+	return c.SQL()
+}
+
+// SQL renders the filter as a WHERE, ORDER BY and LIMIT/OFFSET
+// clause with ? placeholders and returns it with its arguments.
+func (f Filter) SQL() (string, []any) {
+	filter, args := f.Where()
+
+	if f.After != nil {
+		cond, condArgs := f.keysetSQL()
+
+		if filter == "" {
+			filter = " WHERE " + cond
+		} else {
+			filter += " AND " + cond
+		}
+
+		args = append(args, condArgs...)
+	}
+
+	filter += f.orderBySQL()
+
+	page, pageArgs := f.pageSQL()
+
+	return filter + page, append(args, pageArgs...)
+}
+
+// pageSQL renders the LIMIT and OFFSET clause of the filter.
+func (f Filter) pageSQL() (string, []any) {
+	var (
+		page string
+		args []any
+	)
+
+	if f.Limit >= 0 {
+		page += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+
+	if f.Offset > 0 {
+		page += " OFFSET ?"
+		args = append(args, f.Offset)
+	}
+
+	return page, args
+}
+
+// SQL renders the condition with ? placeholders and returns it with its arguments.
+func (c Condition) SQL() (string, []any) {
+	switch c.Operator {
+	case "IS", "IS NOT":
+		return c.Column + " " + c.Operator + " NULL", nil
+	case "IN":
+		args := make([]any, len(c.Values))
+		for i, v := range c.Values {
+			args[i] = v
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+		return c.Column + " IN (" + placeholders + ")", args
+	case "BETWEEN":
+		return c.Column + " BETWEEN ? AND ?", []any{c.Values[0], c.Values[1]}
+	case "LIKE":
+		return c.Column + " LIKE ? ESCAPE '" + string(LikeEscape) + "'", []any{c.Value}
+	case "ILIKE":
+		return "LOWER(" + c.Column + ") LIKE LOWER(?) ESCAPE '" + string(LikeEscape) + "'", []any{c.Value}
+	default:
+		return c.Column + " " + c.Operator + " ?", []any{c.Value}
+	}
+}
+
+func AssembleFilter(params url.Values, allowedParams map[string]string) (string, []any, error) {
+	f, err := ParseFilter(params, allowedParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	filter, args := f.SQL()
+
+	return filter, args, nil
+}
@@ -0,0 +1,295 @@
+package db
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssembleFilter(t *testing.T) {
+	allowedParams := map[string]string{
+		"title": "tytul",
+		"year":  "rok_wydania",
+		"pages": "liczba_stron",
+	}
+
+	tests := map[string]struct {
+		giveParams url.Values
+		wantFilter string
+		wantArgs   []any
+		wantErrIs  error
+	}{
+		"Empty": {
+			giveParams: url.Values{},
+			wantFilter: "",
+		},
+		"In": {
+			giveParams: url.Values{"year.in": {"1990,1995, 2001"}},
+			wantFilter: " WHERE rok_wydania IN (?, ?, ?)",
+			wantArgs:   []any{"1990", "1995", "2001"},
+		},
+		"InSingleValue": {
+			giveParams: url.Values{"year.in": {"(1990)"}},
+			wantFilter: " WHERE rok_wydania IN (?)",
+			wantArgs:   []any{"1990"},
+		},
+		"InEscaped": {
+			giveParams: url.Values{"title.in": {`a\,b,c\)`}},
+			wantFilter: " WHERE tytul IN (?, ?)",
+			wantArgs:   []any{"a,b", "c)"},
+		},
+		"Between": {
+			giveParams: url.Values{"pages.between": {"100,300"}, "limit": {"5"}},
+			wantFilter: " WHERE liczba_stron BETWEEN ? AND ? LIMIT ?",
+			wantArgs:   []any{"100", "300", int64(5)},
+		},
+		"OrGroup": {
+			giveParams: url.Values{"or": {"(title:a\\,b,year.in:(1990,1995),pages:null)"}},
+			wantFilter: " WHERE (tytul = ? OR rok_wydania IN (?, ?) OR liczba_stron IS NULL)",
+			wantArgs:   []any{"a,b", "1990", "1995"},
+		},
+		"OrGroupAndCondition": {
+			giveParams: url.Values{"or": {"(year.lt:1900,year.gte:2000)"}, "pages.gt": {"100"}},
+			wantFilter: " WHERE liczba_stron > ? AND (rok_wydania < ? OR rok_wydania >= ?)",
+			wantArgs:   []any{"100", "1900", "2000"},
+		},
+		"Contains": {
+			giveParams: url.Values{"title.contains": {"50%_off!"}},
+			wantFilter: " WHERE tytul LIKE ? ESCAPE '!'",
+			wantArgs:   []any{"%50!%!_off!!%"},
+		},
+		"StartsWith": {
+			giveParams: url.Values{"title.startswith": {"Pan"}},
+			wantFilter: " WHERE tytul LIKE ? ESCAPE '!'",
+			wantArgs:   []any{"Pan%"},
+		},
+		"EndsWith": {
+			giveParams: url.Values{"title.endswith": {"null"}},
+			wantFilter: " WHERE tytul LIKE ? ESCAPE '!'",
+			wantArgs:   []any{"%null"},
+		},
+		"IContains": {
+			giveParams: url.Values{"title.icontains": {"Pan"}},
+			wantFilter: " WHERE LOWER(tytul) LIKE LOWER(?) ESCAPE '!'",
+			wantArgs:   []any{"%Pan%"},
+		},
+		"LikeKeepsWildcards": {
+			giveParams: url.Values{"title.like": {"P_n%"}},
+			wantFilter: " WHERE tytul LIKE ? ESCAPE '!'",
+			wantArgs:   []any{"P_n%"},
+		},
+		"FieldsDontChangeFilter": {
+			giveParams: url.Values{"fields": {"title, year"}, "year": {"1990"}},
+			wantFilter: " WHERE rok_wydania = ?",
+			wantArgs:   []any{"1990"},
+		},
+		"ErrFieldsUnknown": {
+			giveParams: url.Values{"fields": {"title,isbn"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrFieldsEmpty": {
+			giveParams: url.Values{"fields": {"title,"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrContainsOnNumber": {
+			giveParams: url.Values{"year.contains": {"19"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrInEmptyValue": {
+			giveParams: url.Values{"year.in": {"1990,,2001"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrBetweenThreeValues": {
+			giveParams: url.Values{"year.between": {"1,2,3"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrInUnbalanced": {
+			giveParams: url.Values{"year.in": {"(1990,1995"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrOrNotKeyValue": {
+			giveParams: url.Values{"or": {"(year)"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrOrEmptyValue": {
+			giveParams: url.Values{"or": {"(year:,title:a)"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrOrNullComparison": {
+			giveParams: url.Values{"or": {"(year.gt:null)"}},
+			wantErrIs:  ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, args, err := AssembleFilter(tt.giveParams, allowedParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFilter, filter)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestAssembleFilter_Sort(t *testing.T) {
+	allowedParams := map[string]string{
+		"id":         "id",
+		"title":      "tytul",
+		"year":       "rok_wydania",
+		"death_year": "rok_smierci",
+	}
+
+	tests := map[string]struct {
+		giveSort   string
+		wantFilter string
+		wantErrIs  error
+	}{
+		"Single": {
+			giveSort:   "year",
+			wantFilter: " ORDER BY rok_wydania IS NULL DESC, rok_wydania, id",
+		},
+		"MixedDirections": {
+			giveSort:   "-year, title",
+			wantFilter: " ORDER BY rok_wydania IS NULL, rok_wydania DESC, tytul IS NULL DESC, tytul, id",
+		},
+		"ByID": {
+			giveSort:   "-id,title",
+			wantFilter: " ORDER BY id DESC, tytul IS NULL DESC, tytul",
+		},
+		"NullsLast": {
+			giveSort:   "death_year.nullslast",
+			wantFilter: " ORDER BY rok_smierci IS NULL, rok_smierci, id",
+		},
+		"NullsFirstDesc": {
+			giveSort:   "-death_year.nullsfirst,-year",
+			wantFilter: " ORDER BY rok_smierci IS NULL DESC, rok_smierci DESC, rok_wydania IS NULL, rok_wydania DESC, id DESC",
+		},
+		"ErrEmptyField": {
+			giveSort:  "year,,title",
+			wantErrIs: ErrParam,
+		},
+		"ErrRepeated": {
+			giveSort:  "year,-year",
+			wantErrIs: ErrParam,
+		},
+		"ErrUnknownNulls": {
+			giveSort:  "death_year.nullsmiddle",
+			wantErrIs: ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, _, err := AssembleFilter(url.Values{"sort_by": {tt.giveSort}}, allowedParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFilter, filter)
+		})
+	}
+}
+
+func TestAssembleFilter_Cursor(t *testing.T) {
+	allowedParams := map[string]string{
+		"id":         "id",
+		"year":       "rok_wydania",
+		"pages":      "liczba_stron",
+		"death_year": "rok_smierci",
+	}
+
+	year := "1961"
+
+	tests := map[string]struct {
+		giveParams url.Values
+		wantFilter string
+		wantArgs   []any
+		wantErrIs  error
+	}{
+		"LimitOrdersByID": {
+			giveParams: url.Values{"limit": {"2"}},
+			wantFilter: " ORDER BY id LIMIT ?",
+			wantArgs:   []any{int64(2)},
+		},
+		"AfterID": {
+			giveParams: url.Values{"after": {Cursor{ID: 5}.Encode()}, "limit": {"2"}},
+			wantFilter: " WHERE id > ? ORDER BY id LIMIT ?",
+			wantArgs:   []any{int64(5), int64(2)},
+		},
+		"AfterAsc": {
+			giveParams: url.Values{"after": {Cursor{Sort: "year", Values: []*string{&year}, ID: 7}.Encode()}, "sort_by": {"year"}},
+			wantFilter: " WHERE (rok_wydania > ? OR (rok_wydania = ? AND id > ?)) ORDER BY rok_wydania IS NULL DESC, rok_wydania, id",
+			wantArgs:   []any{"1961", "1961", int64(7)},
+		},
+		"AfterDescAndCondition": {
+			giveParams: url.Values{
+				"after":    {Cursor{Sort: "-year", Values: []*string{&year}, ID: 7}.Encode()},
+				"sort_by":  {"-year"},
+				"pages.gt": {"100"},
+			},
+			wantFilter: " WHERE liczba_stron > ? AND ((rok_wydania < ? OR rok_wydania IS NULL) OR (rok_wydania = ? AND id < ?))" +
+				" ORDER BY rok_wydania IS NULL, rok_wydania DESC, id DESC",
+			wantArgs: []any{"100", "1961", "1961", int64(7)},
+		},
+		"AfterMultipleKeys": {
+			giveParams: url.Values{
+				"after":   {Cursor{Sort: "-year,pages", Values: []*string{&year, &year}, ID: 7}.Encode()},
+				"sort_by": {"-year,pages"},
+			},
+			wantFilter: " WHERE ((rok_wydania < ? OR rok_wydania IS NULL) OR (rok_wydania = ? AND liczba_stron > ?) OR" +
+				" (rok_wydania = ? AND liczba_stron = ? AND id > ?)) ORDER BY rok_wydania IS NULL, rok_wydania DESC, liczba_stron IS NULL DESC, liczba_stron, id",
+			wantArgs: []any{"1961", "1961", "1961", "1961", "1961", int64(7)},
+		},
+		"AfterNullAsc": {
+			giveParams: url.Values{"after": {Cursor{Sort: "death_year", Values: []*string{nil}, ID: 3}.Encode()}, "sort_by": {"death_year"}},
+			wantFilter: " WHERE (rok_smierci IS NOT NULL OR (rok_smierci IS NULL AND id > ?)) ORDER BY rok_smierci IS NULL DESC, rok_smierci, id",
+			wantArgs:   []any{int64(3)},
+		},
+		"AfterNullNullsLast": {
+			giveParams: url.Values{
+				"after":   {Cursor{Sort: "death_year.nullslast", Values: []*string{nil}, ID: 3}.Encode()},
+				"sort_by": {"death_year.nullslast"},
+			},
+			wantFilter: " WHERE rok_smierci IS NULL AND id > ? ORDER BY rok_smierci IS NULL, rok_smierci, id",
+			wantArgs:   []any{int64(3)},
+		},
+		"ErrAfterInvalid": {
+			giveParams: url.Values{"after": {"not a cursor"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrAfterDifferentSort": {
+			giveParams: url.Values{"after": {Cursor{Sort: "year", Values: []*string{&year}, ID: 7}.Encode()}, "sort_by": {"pages"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrAfterMissingValue": {
+			giveParams: url.Values{"after": {Cursor{Sort: "year", ID: 7}.Encode()}, "sort_by": {"year"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrAfterWithOffset": {
+			giveParams: url.Values{"after": {Cursor{ID: 5}.Encode()}, "limit": {"2"}, "offset": {"2"}},
+			wantErrIs:  ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, args, err := AssembleFilter(tt.giveParams, allowedParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFilter, filter)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
@@ -1,76 +1,112 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"iter"
 	"net/url"
 
 	"pawrest/internal/models"
 )
 
 type GenreDatabaseInterface interface {
-	GetGenres(params url.Values) ([]models.Genre, error)
-	GetGenre(id int64) (models.Genre, error)
-	InsertGenre(g models.Genre) (int64, error)
-	UpdateWholeGenre(id int64, g models.Genre) error
-	DelGenre(id int64) error
+	GetGenres(ctx context.Context, params url.Values) ([]models.Genre, error)
+	// StreamGenres is GetGenres yielding the records one at a time.
+	StreamGenres(ctx context.Context, params url.Values) iter.Seq2[models.Genre, error]
+	CountGenres(ctx context.Context, params url.Values) (int64, error)
+	GetGenre(ctx context.Context, id int64) (models.Genre, error)
+	// GetGenreByName returns the genre named name, ignoring case,
+	// the first one if there are many.
+	GetGenreByName(ctx context.Context, name string) (models.Genre, error)
+	InsertGenre(ctx context.Context, g models.Genre) (int64, error)
+	// InsertGenres inserts genres with a single statement and returns their ids in order.
+	InsertGenres(ctx context.Context, genres []models.Genre) ([]int64, error)
+	UpdateWholeGenre(ctx context.Context, id int64, g models.Genre) error
+	DelGenre(ctx context.Context, id int64) error
 }
 
-func (d *Database) GetGenres(params url.Values) ([]models.Genre, error) {
+// AllowedGenreParams maps the genre query parameters to gatunek columns.
+var AllowedGenreParams = map[string]string{
+	"id":   "id",
+	"name": "nazwa",
+}
+
+// genreColumns lists the gatunek columns in the order they're selected in.
+var genreColumns = []column[models.Genre]{
+	{"id", func(g *models.Genre) any { return &g.ID }},
+	{"name", func(g *models.Genre) any { return &g.Name }},
+}
+
+func (d *Database) GetGenres(ctx context.Context, params url.Values) ([]models.Genre, error) {
+	return queryWithParams(ctx, d, "gatunek", params, AllowedGenreParams, genreColumns)
+}
+
+func (d *Database) StreamGenres(ctx context.Context, params url.Values) iter.Seq2[models.Genre, error] {
+	return streamWithParams(ctx, d, "gatunek", params, AllowedGenreParams, genreColumns)
+}
+
+func (d *Database) CountGenres(ctx context.Context, params url.Values) (int64, error) {
+	return countWithParams(ctx, d, "gatunek", params, AllowedGenreParams)
+}
+
+func (d *Database) GetGenre(ctx context.Context, id int64) (models.Genre, error) {
 	query := `
 	SELECT id, nazwa
-	FROM gatunek`
-
-	allowedParams := map[string]string{
-		"id":   "id",
-		"name": "nazwa",
-	}
+	FROM gatunek
+	WHERE id = ?`
 
-	genreFunc := func(g *models.Genre, rows *sql.Rows) error {
-		return rows.Scan(&g.ID, &g.Name)
+	genreFunc := func(g *models.Genre, row *sql.Row) error {
+		return row.Scan(&g.ID, &g.Name)
 	}
 
-	return queryWithParams[models.Genre](
-		d,
-		query,
-		params,
-		allowedParams,
-		genreFunc,
-	)
+	return queryID[models.Genre](ctx, d, query, id, genreFunc)
 }
 
-func (d *Database) GetGenre(id int64) (models.Genre, error) {
+func (d *Database) GetGenreByName(ctx context.Context, name string) (models.Genre, error) {
 	query := `
 	SELECT id, nazwa
 	FROM gatunek
-	WHERE id = ?`
+	WHERE LOWER(nazwa) = LOWER(?)
+	ORDER BY id
+	LIMIT 1`
 
 	genreFunc := func(g *models.Genre, row *sql.Row) error {
 		return row.Scan(&g.ID, &g.Name)
 	}
 
-	return queryID[models.Genre](d, query, id, genreFunc)
+	return queryOne(ctx, d, fmt.Sprintf("named %q", name), query, genreFunc, name)
 }
 
-func (d *Database) InsertGenre(g models.Genre) (int64, error) {
+func (d *Database) InsertGenre(ctx context.Context, g models.Genre) (int64, error) {
 	query := `
 	INSERT INTO gatunek (nazwa)
 	VALUES (?)`
 
-	return d.insert(query, g.Name)
+	return d.insert(ctx, query, g.Name)
+}
+
+func (d *Database) InsertGenres(ctx context.Context, genres []models.Genre) ([]int64, error) {
+	rows := make([][]any, len(genres))
+	for i, g := range genres {
+		rows[i] = []any{g.Name}
+	}
+
+	return d.insertMany(ctx, "gatunek", []string{"nazwa"}, rows)
 }
 
-func (d *Database) UpdateWholeGenre(id int64, g models.Genre) error {
+func (d *Database) UpdateWholeGenre(ctx context.Context, id int64, g models.Genre) error {
 	query := `
 	UPDATE gatunek
 	SET
 		nazwa = ?
 	WHERE id = ?`
 
-	return d.updateWholeID(query, g.Name, id)
+	return d.updateWholeID(ctx, query, g.Name, id)
 }
 
-func (d *Database) DelGenre(id int64) error {
+func (d *Database) DelGenre(ctx context.Context, id int64) error {
 	query := "DELETE FROM gatunek WHERE id = ?"
 
-	return d.deleteID(query, id)
+	return d.deleteID(ctx, query, id)
 }
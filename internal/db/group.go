@@ -0,0 +1,474 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pawrest/internal/models"
+)
+
+type GroupDatabaseInterface interface {
+	// Group aggregates the records of resource (books, authors, genres or
+	// languages) matching the filtering conditions in params, grouped by
+	// the fields in the group_by parameter, computing the aggregates in
+	// the agg parameter and keeping the groups matching the having.*
+	// parameters. The groups can be sorted and paginated.
+	Group(ctx context.Context, resource string, params url.Values) ([]models.Group, error)
+	// CountGroups returns the number of groups Group returns
+	// for params, ignoring the sorting and pagination.
+	CountGroups(ctx context.Context, resource string, params url.Values) (int64, error)
+}
+
+// AllowedBookGroupParams maps the parameters books can be grouped and
+// aggregated by to the columns of the joined tables: the extended book
+// parameters along with the ids of the related records.
+var AllowedBookGroupParams = func() map[string]string {
+	params := maps.Clone(AllowedBookExtParams)
+	params["author"] = "id_autora"
+	params["genre"] = "id_gatunku"
+	params["language"] = "id_jezyka"
+
+	return params
+}()
+
+// GroupSource is the table a resource is aggregated over
+// and the parameters it can be filtered and grouped by.
+type GroupSource struct {
+	From          string
+	AllowedParams map[string]string
+}
+
+// GroupSources maps the resources which can be aggregated to their sources.
+var GroupSources = map[string]GroupSource{
+	"books":     {bookExtTables, AllowedBookGroupParams},
+	"authors":   {"autor", AllowedAuthorParams},
+	"genres":    {"gatunek", AllowedGenreParams},
+	"languages": {"jezyk", AllowedLanguageParams},
+}
+
+// aggregateFuncs are the functions the agg parameter accepts.
+var aggregateFuncs = []string{"count", "sum", "avg", "min", "max"}
+
+// Aggregate is a single aggregate of the agg parameter, e.g. avg(pages).
+type Aggregate struct {
+	Func   string // count, sum, avg, min or max
+	Param  string // aggregated parameter, empty when counting the records
+	Column string
+}
+
+// Name returns the aggregate the way the agg parameter writes it.
+func (a Aggregate) Name() string {
+	if a.Param == "" {
+		return a.Func
+	}
+
+	return a.Func + "(" + a.Param + ")"
+}
+
+// SQL renders the aggregate expression.
+func (a Aggregate) SQL() string {
+	if a.Param == "" {
+		return "COUNT(*)"
+	}
+
+	return strings.ToUpper(a.Func) + "(" + a.Column + ")"
+}
+
+// Numeric reports whether the values of the aggregate are numbers,
+// which is the case unless it's the minimum or maximum of a text field.
+func (a Aggregate) Numeric() bool {
+	return (a.Func != "min" && a.Func != "max") || !textParams[a.Param]
+}
+
+// GroupField is a field the records are grouped by.
+type GroupField struct {
+	Param  string
+	Column string
+}
+
+// Numeric reports whether the values of the field are numbers.
+func (g GroupField) Numeric() bool {
+	return !textParams[g.Param]
+}
+
+// Grouping is the parsed form of the aggregation parameters. The embedded
+// Filter holds the conditions on the records along with the sorting and
+// pagination of the groups, sorted by the grouped fields by default.
+type Grouping struct {
+	Filter
+	GroupBy    []GroupField
+	Aggregates []Aggregate
+	// Having are the conditions on the aggregates. Their Param
+	// is the name of the aggregate and Column its expression.
+	Having []Condition
+}
+
+// IsGrouping reports whether params request an aggregation.
+func IsGrouping(params url.Values) bool {
+	return params.Has("group_by") || params.Has("agg")
+}
+
+// ParseGrouping validates the aggregation parameters in params against
+// allowedParams and returns the parsed grouping. Only the fields in
+// allowedParams can be grouped by and aggregated. Without the agg
+// parameter the records of each group are counted.
+func ParseGrouping(params url.Values, allowedParams map[string]string) (Grouping, error) {
+	for _, key := range []string{"after", "fields"} {
+		if params.Has(key) {
+			return Grouping{}, fmt.Errorf("%w: the %s parameter can't be used with group_by or agg", ErrParam, key)
+		}
+	}
+
+	rest := url.Values{}
+	having := url.Values{}
+
+	for key, values := range params {
+		if name, ok := strings.CutPrefix(key, "having."); ok {
+			having[name] = values
+		} else if key != "group_by" && key != "agg" && key != "sort_by" {
+			rest[key] = values
+		}
+	}
+
+	f, err := ParseFilter(rest, allowedParams)
+	if err != nil {
+		return Grouping{}, err
+	}
+
+	// Groups have no id breaking the ties.
+	f.IDColumn = ""
+
+	g := Grouping{Filter: f}
+
+	if list, ok := params["group_by"]; ok {
+		if g.GroupBy, err = parseGroupBy(list, allowedParams); err != nil {
+			return Grouping{}, err
+		}
+	}
+
+	agg := []string{"count"}
+	if list, ok := params["agg"]; ok {
+		agg = list
+	}
+
+	if g.Aggregates, err = parseAggregates(agg, allowedParams); err != nil {
+		return Grouping{}, err
+	}
+
+	aggregates := map[string]string{}
+	for _, a := range g.Aggregates {
+		aggregates[a.Name()] = a.SQL()
+	}
+
+	for key, values := range having {
+		if len(values) > 1 {
+			return Grouping{}, fmt.Errorf("%w: too many parameters were provided for a single aggregate", ErrParam)
+		}
+
+		cond, err := parseCondition(key, values[0], aggregates, false)
+		if err != nil {
+			return Grouping{}, fmt.Errorf("%w (having filters must use the requested aggregates)", err)
+		}
+
+		g.Having = append(g.Having, cond)
+	}
+
+	if g.Sort, err = g.parseSort(params); err != nil {
+		return Grouping{}, err
+	}
+
+	return g, nil
+}
+
+// parseGroupBy parses the comma-separated fields of the group_by parameter.
+func parseGroupBy(values []string, allowedParams map[string]string) ([]GroupField, error) {
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%w: the group_by parameter was provided more than once", ErrParam)
+	}
+
+	var fields []GroupField
+
+	for param := range strings.SplitSeq(values[0], ",") {
+		param = strings.TrimSpace(param)
+
+		column, allowed := allowedParams[param]
+		if !allowed {
+			return nil, fmt.Errorf("%w: an unknown field %q was provided for grouping", ErrParam, param)
+		}
+
+		if slices.ContainsFunc(fields, func(g GroupField) bool { return g.Param == param }) {
+			return nil, fmt.Errorf("%w: field %q was provided for grouping more than once", ErrParam, param)
+		}
+
+		fields = append(fields, GroupField{Param: param, Column: column})
+	}
+
+	return fields, nil
+}
+
+// parseAggregates parses the comma-separated aggregates of the agg
+// parameter, written as count or function(field), e.g. avg(pages).
+func parseAggregates(values []string, allowedParams map[string]string) ([]Aggregate, error) {
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%w: the agg parameter was provided more than once", ErrParam)
+	}
+
+	var aggregates []Aggregate
+
+	for _, item := range splitList(values[0]) {
+		item = strings.TrimSpace(item)
+
+		var a Aggregate
+
+		if item == "count" {
+			a.Func = "count"
+		} else {
+			fn, rest, _ := strings.Cut(item, "(")
+			param, ok := strings.CutSuffix(rest, ")")
+
+			if !ok || !slices.Contains(aggregateFuncs, fn) {
+				return nil, fmt.Errorf("%w: invalid aggregate %q", ErrParam, item)
+			}
+
+			column, allowed := allowedParams[param]
+			if !allowed {
+				return nil, fmt.Errorf("%w: an unknown field %q was provided for aggregation", ErrParam, param)
+			}
+
+			if (fn == "sum" || fn == "avg") && textParams[param] {
+				return nil, fmt.Errorf("%w: %s can only be used on numeric fields", ErrParam, fn)
+			}
+
+			a = Aggregate{Func: fn, Param: param, Column: column}
+		}
+
+		if slices.ContainsFunc(aggregates, func(other Aggregate) bool { return other.Name() == a.Name() }) {
+			return nil, fmt.Errorf("%w: aggregate %q was provided more than once", ErrParam, item)
+		}
+
+		aggregates = append(aggregates, a)
+	}
+
+	return aggregates, nil
+}
+
+// parseSort parses the sort_by parameter of a grouping, which
+// may name the grouped fields and the requested aggregates.
+func (g Grouping) parseSort(params url.Values) ([]SortKey, error) {
+	sort, ok := params["sort_by"]
+	if !ok {
+		keys := make([]SortKey, len(g.GroupBy))
+		for i, field := range g.GroupBy {
+			keys[i] = SortKey{Param: field.Param, Column: field.Column}
+		}
+
+		return keys, nil
+	}
+
+	if len(sort) > 1 {
+		return nil, fmt.Errorf("%w: the sort_by parameter was provided more than once", ErrParam)
+	}
+
+	keys, err := ParseSort(sort[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i, k := range keys {
+		if j := slices.IndexFunc(g.GroupBy, func(f GroupField) bool { return f.Param == k.Param }); j >= 0 {
+			keys[i].Column = g.GroupBy[j].Column
+		} else if j := slices.IndexFunc(g.Aggregates, func(a Aggregate) bool { return a.Name() == k.Param }); j >= 0 {
+			keys[i].Column = g.Aggregates[j].SQL()
+		} else {
+			return nil, fmt.Errorf("%w: groups can only be sorted by the grouped fields and the aggregates", ErrParam)
+		}
+	}
+
+	return keys, nil
+}
+
+// groupSQL renders the GROUP BY and HAVING clauses of the grouping
+// and returns them with their arguments. The values compared with
+// numeric aggregates are passed as numbers, as SQLite wouldn't
+// convert them from text.
+func (g Grouping) groupSQL() (string, []any, error) {
+	var (
+		clause string
+		args   []any
+	)
+
+	if len(g.GroupBy) > 0 {
+		columns := make([]string, len(g.GroupBy))
+		for i, field := range g.GroupBy {
+			columns[i] = field.Column
+		}
+
+		clause = " GROUP BY " + strings.Join(columns, ", ")
+	}
+
+	var conditions []string
+
+	for _, c := range g.Having {
+		cond, condArgs := c.SQL()
+
+		i := slices.IndexFunc(g.Aggregates, func(a Aggregate) bool { return a.Name() == c.Param })
+		if g.Aggregates[i].Numeric() {
+			for j, arg := range condArgs {
+				n, err := strconv.ParseFloat(arg.(string), 64)
+				if err != nil {
+					return "", nil, fmt.Errorf("%w: %q is not a number", ErrParam, arg)
+				}
+
+				condArgs[j] = n
+			}
+		}
+
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	if len(conditions) > 0 {
+		clause += " HAVING " + strings.Join(conditions, " AND ")
+	}
+
+	return clause, args, nil
+}
+
+func (d *Database) Group(ctx context.Context, resource string, params url.Values) ([]models.Group, error) {
+	src, ok := GroupSources[resource]
+	if !ok {
+		return nil, fmt.Errorf("resource %q can't be aggregated", resource)
+	}
+
+	g, err := ParseGrouping(params, src.AllowedParams)
+	if err != nil {
+		return nil, err
+	}
+
+	g.dialect = d.dialect
+
+	var exprs []string
+	for _, field := range g.GroupBy {
+		exprs = append(exprs, field.Column)
+	}
+
+	for _, a := range g.Aggregates {
+		exprs = append(exprs, a.SQL())
+	}
+
+	where, args := g.Where()
+
+	group, groupArgs, err := g.groupSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageArgs := g.pageSQL()
+
+	query := "SELECT " + strings.Join(exprs, ", ") + " FROM " + src.From + where + group + g.orderBySQL() + page
+	args = slices.Concat(args, groupArgs, pageArgs)
+
+	groupFunc := func(r *models.Group, rows *sql.Rows) error {
+		dests := make([]any, len(exprs))
+		for i := range g.GroupBy {
+			dests[i] = groupDest(g.GroupBy[i].Numeric(), false)
+		}
+
+		for i, a := range g.Aggregates {
+			dests[len(g.GroupBy)+i] = groupDest(a.Numeric(), a.Func == "avg")
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		r.Group = map[string]any{}
+		for i, field := range g.GroupBy {
+			r.Group[field.Param] = groupValue(dests[i])
+		}
+
+		r.Aggregates = map[string]any{}
+		for i, a := range g.Aggregates {
+			r.Aggregates[a.Name()] = groupValue(dests[len(g.GroupBy)+i])
+		}
+
+		return nil
+	}
+
+	return queryRows(ctx, d, query, args, groupFunc)
+}
+
+// groupDest returns the destination a grouped field or an aggregate is
+// scanned into. All of the numeric fields are integers, while averages
+// are fractions.
+func groupDest(numeric, fraction bool) any {
+	switch {
+	case fraction:
+		return &sql.NullFloat64{}
+	case numeric:
+		return &sql.NullInt64{}
+	default:
+		return &sql.NullString{}
+	}
+}
+
+// groupValue returns the value scanned into dest, nil for NULL.
+func groupValue(dest any) any {
+	switch v := dest.(type) {
+	case *sql.NullFloat64:
+		if v.Valid {
+			return v.Float64
+		}
+	case *sql.NullInt64:
+		if v.Valid {
+			return v.Int64
+		}
+	case *sql.NullString:
+		if v.Valid {
+			return v.String
+		}
+	}
+
+	return nil
+}
+
+func (d *Database) CountGroups(ctx context.Context, resource string, params url.Values) (int64, error) {
+	src, ok := GroupSources[resource]
+	if !ok {
+		return 0, fmt.Errorf("resource %q can't be aggregated", resource)
+	}
+
+	g, err := ParseGrouping(params, src.AllowedParams)
+	if err != nil {
+		return 0, err
+	}
+
+	g.dialect = d.dialect
+
+	where, args := g.Where()
+
+	group, groupArgs, err := g.groupSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	query := "SELECT COUNT(*) FROM (SELECT 1 AS n FROM " + src.From + where + group + ") g"
+	args = append(args, groupArgs...)
+
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	var n int64
+
+	if err := d.conn.QueryRowContext(ctx, d.dialect.rebind(query), args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("Count error (%w)", err)
+	}
+
+	return n, nil
+}
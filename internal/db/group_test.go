@@ -0,0 +1,119 @@
+package db
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGrouping(t *testing.T) {
+	allowedParams := map[string]string{
+		"title": "tytul",
+		"year":  "rok_wydania",
+		"pages": "liczba_stron",
+	}
+
+	tests := map[string]struct {
+		giveParams  url.Values
+		wantSelect  []string
+		wantClauses string
+		wantArgs    []any
+		wantErrIs   error
+	}{
+		"CountByDefault": {
+			giveParams:  url.Values{"group_by": {"year"}},
+			wantSelect:  []string{"rok_wydania", "COUNT(*)"},
+			wantClauses: " GROUP BY rok_wydania ORDER BY rok_wydania IS NULL DESC, rok_wydania",
+		},
+		"NoGroupBy": {
+			giveParams:  url.Values{"agg": {"min(title),sum(pages)"}, "year.gt": {"1900"}},
+			wantSelect:  []string{"MIN(tytul)", "SUM(liczba_stron)"},
+			wantClauses: " WHERE rok_wydania > ?",
+			wantArgs:    []any{"1900"},
+		},
+		"Having": {
+			giveParams: url.Values{
+				"group_by":              {"year,title"},
+				"agg":                   {"count,avg(pages)"},
+				"having.avg(pages).gte": {"150.5"},
+				"sort_by":               {"-count,title"},
+				"limit":                 {"3"},
+			},
+			wantSelect:  []string{"rok_wydania", "tytul", "COUNT(*)", "AVG(liczba_stron)"},
+			wantClauses: " GROUP BY rok_wydania, tytul HAVING AVG(liczba_stron) >= ? ORDER BY COUNT(*) IS NULL, COUNT(*) DESC, tytul IS NULL DESC, tytul LIMIT ?",
+			wantArgs:    []any{150.5, int64(3)},
+		},
+		"HavingText": {
+			giveParams:  url.Values{"agg": {"max(title)"}, "having.max(title).neq": {"Lalka"}},
+			wantSelect:  []string{"MAX(tytul)"},
+			wantClauses: " HAVING MAX(tytul) <> ?",
+			wantArgs:    []any{"Lalka"},
+		},
+		"ErrUnknownGroupField": {
+			giveParams: url.Values{"group_by": {"isbn"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrRepeatedGroupField": {
+			giveParams: url.Values{"group_by": {"year,year"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrInvalidAggregate": {
+			giveParams: url.Values{"agg": {"avg(pages"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrAvgOfText": {
+			giveParams: url.Values{"agg": {"avg(title)"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrHavingUnrequested": {
+			giveParams: url.Values{"having.sum(pages).gt": {"100"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrHavingNotNumber": {
+			giveParams: url.Values{"having.count.gt": {"many"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrSortUngrouped": {
+			giveParams: url.Values{"group_by": {"year"}, "sort_by": {"pages"}},
+			wantErrIs:  ErrParam,
+		},
+		"ErrAfter": {
+			giveParams: url.Values{"group_by": {"year"}, "after": {Cursor{ID: 5}.Encode()}},
+			wantErrIs:  ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := ParseGrouping(tt.giveParams, allowedParams)
+			if err == nil {
+				_, _, err = g.groupSQL()
+			}
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var exprs []string
+			for _, field := range g.GroupBy {
+				exprs = append(exprs, field.Column)
+			}
+
+			for _, a := range g.Aggregates {
+				exprs = append(exprs, a.SQL())
+			}
+
+			where, args := g.Where()
+			group, groupArgs, _ := g.groupSQL()
+			page, pageArgs := g.pageSQL()
+
+			assert.Equal(t, tt.wantSelect, exprs)
+			assert.Equal(t, tt.wantClauses, where+group+g.orderBySQL()+page)
+			assert.Equal(t, tt.wantArgs, append(append(args, groupArgs...), pageArgs...))
+		})
+	}
+}
@@ -1,44 +1,56 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"iter"
 	"net/url"
 
 	"pawrest/internal/models"
 )
 
 type LanguageDatabaseInterface interface {
-	GetLanguages(params url.Values) ([]models.Language, error)
-	GetLanguage(id int64) (models.Language, error)
-	InsertLanguage(l models.Language) (int64, error)
-	UpdateWholeLanguage(id int64, l models.Language) error
-	DelLanguage(id int64) error
+	GetLanguages(ctx context.Context, params url.Values) ([]models.Language, error)
+	// StreamLanguages is GetLanguages yielding the records one at a time.
+	StreamLanguages(ctx context.Context, params url.Values) iter.Seq2[models.Language, error]
+	CountLanguages(ctx context.Context, params url.Values) (int64, error)
+	GetLanguage(ctx context.Context, id int64) (models.Language, error)
+	// GetLanguageByName returns the language named name, ignoring case,
+	// the first one if there are many.
+	GetLanguageByName(ctx context.Context, name string) (models.Language, error)
+	InsertLanguage(ctx context.Context, l models.Language) (int64, error)
+	// InsertLanguages inserts languages with a single statement and returns their ids in order.
+	InsertLanguages(ctx context.Context, languages []models.Language) ([]int64, error)
+	UpdateWholeLanguage(ctx context.Context, id int64, l models.Language) error
+	DelLanguage(ctx context.Context, id int64) error
 }
 
-func (d *Database) GetLanguages(params url.Values) ([]models.Language, error) {
-	query := `
-	SELECT id, nazwa
-	FROM jezyk`
+// AllowedLanguageParams maps the language query parameters to jezyk columns.
+var AllowedLanguageParams = map[string]string{
+	"id":   "id",
+	"name": "nazwa",
+}
 
-	allowedParams := map[string]string{
-		"id":   "id",
-		"name": "nazwa",
-	}
+// languageColumns lists the jezyk columns in the order they're selected in.
+var languageColumns = []column[models.Language]{
+	{"id", func(l *models.Language) any { return &l.ID }},
+	{"name", func(l *models.Language) any { return &l.Name }},
+}
 
-	langFunc := func(l *models.Language, rows *sql.Rows) error {
-		return rows.Scan(&l.ID, &l.Name)
-	}
+func (d *Database) GetLanguages(ctx context.Context, params url.Values) ([]models.Language, error) {
+	return queryWithParams(ctx, d, "jezyk", params, AllowedLanguageParams, languageColumns)
+}
+
+func (d *Database) StreamLanguages(ctx context.Context, params url.Values) iter.Seq2[models.Language, error] {
+	return streamWithParams(ctx, d, "jezyk", params, AllowedLanguageParams, languageColumns)
+}
 
-	return queryWithParams[models.Language](
-		d,
-		query,
-		params,
-		allowedParams,
-		langFunc,
-	)
+func (d *Database) CountLanguages(ctx context.Context, params url.Values) (int64, error) {
+	return countWithParams(ctx, d, "jezyk", params, AllowedLanguageParams)
 }
 
-func (d *Database) GetLanguage(id int64) (models.Language, error) {
+func (d *Database) GetLanguage(ctx context.Context, id int64) (models.Language, error) {
 	query := `
 	SELECT id, nazwa
 	FROM jezyk
@@ -48,29 +60,53 @@ func (d *Database) GetLanguage(id int64) (models.Language, error) {
 		return row.Scan(&l.ID, &l.Name)
 	}
 
-	return queryID[models.Language](d, query, id, langFunc)
+	return queryID[models.Language](ctx, d, query, id, langFunc)
+}
+
+func (d *Database) GetLanguageByName(ctx context.Context, name string) (models.Language, error) {
+	query := `
+	SELECT id, nazwa
+	FROM jezyk
+	WHERE LOWER(nazwa) = LOWER(?)
+	ORDER BY id
+	LIMIT 1`
+
+	languageFunc := func(l *models.Language, row *sql.Row) error {
+		return row.Scan(&l.ID, &l.Name)
+	}
+
+	return queryOne(ctx, d, fmt.Sprintf("named %q", name), query, languageFunc, name)
 }
 
-func (d *Database) InsertLanguage(l models.Language) (int64, error) {
+func (d *Database) InsertLanguage(ctx context.Context, l models.Language) (int64, error) {
 	query := `
 	INSERT INTO jezyk (nazwa)
 	VALUES (?)`
 
-	return d.insert(query, l.Name)
+	return d.insert(ctx, query, l.Name)
+}
+
+func (d *Database) InsertLanguages(ctx context.Context, languages []models.Language) ([]int64, error) {
+	rows := make([][]any, len(languages))
+	for i, l := range languages {
+		rows[i] = []any{l.Name}
+	}
+
+	return d.insertMany(ctx, "jezyk", []string{"nazwa"}, rows)
 }
 
-func (d *Database) UpdateWholeLanguage(id int64, l models.Language) error {
+func (d *Database) UpdateWholeLanguage(ctx context.Context, id int64, l models.Language) error {
 	query := `
 	UPDATE jezyk
 	SET
 		nazwa = ?
 	WHERE id = ?`
 
-	return d.updateWholeID(query, l.Name, id)
+	return d.updateWholeID(ctx, query, l.Name, id)
 }
 
-func (d *Database) DelLanguage(id int64) error {
+func (d *Database) DelLanguage(ctx context.Context, id int64) error {
 	query := "DELETE FROM jezyk WHERE id = ?"
 
-	return d.deleteID(query, id)
+	return d.deleteID(ctx, query, id)
 }
@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+	"strings"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+var authorFields = fields[models.Author]{
+	"id":         func(a *models.Author) any { return a.ID },
+	"first_name": func(a *models.Author) any { return a.FirstName },
+	"last_name":  func(a *models.Author) any { return a.LastName },
+	"birth_year": func(a *models.Author) any { return a.BirthYear },
+	"death_year": func(a *models.Author) any { return nullable(a.DeathYear) },
+}
+
+func (s *Store) GetAuthors(ctx context.Context, params url.Values) ([]models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	authors, err := query(s.authors, params, db.AllowedAuthorParams, authorFields)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range authors {
+		authors[i] = copyAuthor(authors[i])
+	}
+
+	return authors, nil
+}
+
+func (s *Store) StreamAuthors(ctx context.Context, params url.Values) iter.Seq2[models.Author, error] {
+	return db.StreamList(ctx, params, s.GetAuthors)
+}
+
+func (s *Store) CountAuthors(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return count(s.authors, params, db.AllowedAuthorParams, authorFields)
+}
+
+func (s *Store) GetAuthor(ctx context.Context, id int64) (models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Author{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := indexByID(s.authors, id, authorID)
+	if i < 0 {
+		return models.Author{}, notFound(id)
+	}
+
+	return copyAuthor(s.authors[i]), nil
+}
+
+func (s *Store) GetAuthorByName(ctx context.Context, firstName, lastName string) (models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Author{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := slices.IndexFunc(s.authors, func(a models.Author) bool {
+		return strings.EqualFold(a.FirstName, firstName) && strings.EqualFold(a.LastName, lastName)
+	})
+	if i < 0 {
+		return models.Author{}, notFoundNamed(firstName + " " + lastName)
+	}
+
+	return copyAuthor(s.authors[i]), nil
+}
+
+func (s *Store) InsertAuthor(ctx context.Context, a models.Author) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq.authors++
+	a.ID = s.seq.authors
+	s.authors = append(s.authors, copyAuthor(a))
+
+	return a.ID, nil
+}
+
+func (s *Store) InsertAuthors(ctx context.Context, authors []models.Author) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, len(authors))
+	for i, a := range authors {
+		s.seq.authors++
+		a.ID = s.seq.authors
+		s.authors = append(s.authors, copyAuthor(a))
+		ids[i] = a.ID
+	}
+
+	return ids, nil
+}
+
+func (s *Store) UpdateWholeAuthor(ctx context.Context, id int64, a models.Author) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.authors, id, authorID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	if err := s.checkBirthYear(id, a.BirthYear); err != nil {
+		return err
+	}
+
+	a.ID = id
+	s.authors[i] = copyAuthor(a)
+
+	return nil
+}
+
+func (s *Store) UpdateAuthor(ctx context.Context, id int64, a models.Author) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if a.FirstName == "" && a.LastName == "" && a.BirthYear == 0 && a.DeathYear == nil {
+		return fmt.Errorf("%w: no columns to update", db.ErrParam)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.authors, id, authorID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	updated := copyAuthor(s.authors[i])
+
+	if a.FirstName != "" {
+		updated.FirstName = a.FirstName
+	}
+	if a.LastName != "" {
+		updated.LastName = a.LastName
+	}
+	if a.BirthYear != 0 {
+		updated.BirthYear = a.BirthYear
+	}
+	if a.DeathYear != nil {
+		updated.DeathYear = models.I64Ptr(*a.DeathYear)
+	}
+
+	if v := updated.Validate(); v != nil {
+		return fmt.Errorf("%w: %w", db.ErrParam, v)
+	}
+
+	if a.BirthYear != 0 {
+		if err := s.checkBirthYear(id, a.BirthYear); err != nil {
+			return err
+		}
+	}
+
+	s.authors[i] = updated
+
+	return nil
+}
+
+func (s *Store) DelAuthor(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.authors, id, authorID)
+	if i < 0 {
+		return notFound(id)
+	}
+
+	if slices.ContainsFunc(s.books, func(b models.Book) bool { return b.Author == id }) {
+		return db.ErrForeignKey
+	}
+
+	s.authors = slices.Delete(s.authors, i, i+1)
+
+	return nil
+}
+
+// copyAuthor returns a copy of a which doesn't share the death year pointer.
+// checkBirthYear returns db.ErrParam wrapping the violations when the
+// author with id, born in birthYear, is younger than any of their books.
+func (s *Store) checkBirthYear(id, birthYear int64) error {
+	var years []int64
+	for _, b := range s.books {
+		if b.Author == id {
+			years = append(years, b.Year)
+		}
+	}
+
+	if len(years) == 0 {
+		return nil
+	}
+
+	if v := models.ValidateBirthYear(birthYear, slices.Min(years)); v != nil {
+		return fmt.Errorf("%w: %w", db.ErrParam, v)
+	}
+
+	return nil
+}
+
+func copyAuthor(a models.Author) models.Author {
+	if a.DeathYear != nil {
+		a.DeathYear = models.I64Ptr(*a.DeathYear)
+	}
+
+	return a
+}
+
+func authorID(a *models.Author) int64 { return a.ID }
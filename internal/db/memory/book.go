@@ -0,0 +1,316 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+var bookFields = fields[models.Book]{
+	"id":       func(b *models.Book) any { return b.ID },
+	"title":    func(b *models.Book) any { return b.Title },
+	"year":     func(b *models.Book) any { return b.Year },
+	"pages":    func(b *models.Book) any { return b.Pages },
+	"author":   func(b *models.Book) any { return b.Author },
+	"genre":    func(b *models.Book) any { return b.Genre },
+	"language": func(b *models.Book) any { return b.Language },
+}
+
+var bookExtFields = fields[models.BookExt]{
+	"id":                func(b *models.BookExt) any { return b.ID },
+	"title":             func(b *models.BookExt) any { return b.Title },
+	"year":              func(b *models.BookExt) any { return b.Year },
+	"pages":             func(b *models.BookExt) any { return b.Pages },
+	"author.id":         func(b *models.BookExt) any { return b.Author.ID },
+	"author.first_name": func(b *models.BookExt) any { return b.Author.FirstName },
+	"author.last_name":  func(b *models.BookExt) any { return b.Author.LastName },
+	"author.birth_year": func(b *models.BookExt) any { return b.Author.BirthYear },
+	"author.death_year": func(b *models.BookExt) any { return nullable(b.Author.DeathYear) },
+	"genre.id":          func(b *models.BookExt) any { return b.Genre.ID },
+	"genre.name":        func(b *models.BookExt) any { return b.Genre.Name },
+	"language.id":       func(b *models.BookExt) any { return b.Language.ID },
+	"language.name":     func(b *models.BookExt) any { return b.Language.Name },
+}
+
+func (s *Store) GetBooks(ctx context.Context, params url.Values) ([]models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return query(s.books, params, db.AllowedBookParams, bookFields)
+}
+
+func (s *Store) StreamBooks(ctx context.Context, params url.Values) iter.Seq2[models.Book, error] {
+	return db.StreamList(ctx, params, s.GetBooks)
+}
+
+func (s *Store) GetBooksExt(ctx context.Context, params url.Values) ([]models.BookExt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	books := make([]models.BookExt, 0, len(s.books))
+	for _, b := range s.books {
+		books = append(books, s.extendBook(b))
+	}
+
+	return query(books, params, db.AllowedBookExtParams, bookExtFields)
+}
+
+func (s *Store) StreamBooksExt(ctx context.Context, params url.Values) iter.Seq2[models.BookExt, error] {
+	return db.StreamList(ctx, params, s.GetBooksExt)
+}
+
+func (s *Store) CountBooks(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return count(s.books, params, db.AllowedBookParams, bookFields)
+}
+
+func (s *Store) CountBooksExt(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	books := make([]models.BookExt, 0, len(s.books))
+	for _, b := range s.books {
+		books = append(books, s.extendBook(b))
+	}
+
+	return count(books, params, db.AllowedBookExtParams, bookExtFields)
+}
+
+func (s *Store) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := indexByID(s.books, id, bookID)
+	if i < 0 {
+		return models.Book{}, notFound(id)
+	}
+
+	return s.books[i], nil
+}
+
+func (s *Store) InsertBook(ctx context.Context, b models.Book) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkBookRefs(b); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkBookYear(b); err != nil {
+		return 0, err
+	}
+
+	s.seq.books++
+	b.ID = s.seq.books
+	s.books = append(s.books, b)
+
+	return b.ID, nil
+}
+
+func (s *Store) InsertBooks(ctx context.Context, books []models.Book) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range books {
+		if err := s.checkBookRefs(b); err != nil {
+			return nil, err
+		}
+
+		if err := s.checkBookYear(b); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int64, len(books))
+	for i, b := range books {
+		s.seq.books++
+		b.ID = s.seq.books
+		s.books = append(s.books, b)
+		ids[i] = b.ID
+	}
+
+	return ids, nil
+}
+
+func (s *Store) UpdateWholeBook(ctx context.Context, id int64, b models.Book) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.books, id, bookID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	if err := s.checkBookRefs(b); err != nil {
+		return err
+	}
+
+	if err := s.checkBookYear(b); err != nil {
+		return err
+	}
+
+	b.ID = id
+	s.books[i] = b
+
+	return nil
+}
+
+func (s *Store) UpdateBook(ctx context.Context, id int64, b models.Book) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if b == (models.Book{ID: b.ID}) {
+		return fmt.Errorf("%w: no columns to update", db.ErrParam)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.books, id, bookID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	updated := s.books[i]
+
+	if b.Title != "" {
+		updated.Title = b.Title
+	}
+	if b.Year != 0 {
+		updated.Year = b.Year
+	}
+	if b.Pages != 0 {
+		updated.Pages = b.Pages
+	}
+	if b.Author != 0 {
+		updated.Author = b.Author
+	}
+	if b.Genre != 0 {
+		updated.Genre = b.Genre
+	}
+	if b.Language != 0 {
+		updated.Language = b.Language
+	}
+
+	if err := s.checkBookRefs(updated); err != nil {
+		return err
+	}
+
+	if err := s.checkBookYear(updated); err != nil {
+		return err
+	}
+
+	s.books[i] = updated
+
+	return nil
+}
+
+func (s *Store) DelBook(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.books, id, bookID)
+	if i < 0 {
+		return notFound(id)
+	}
+
+	s.books = slices.Delete(s.books, i, i+1)
+
+	return nil
+}
+
+// checkBookRefs returns db.ErrForeignKey when the book references
+// an author, genre or language which does not exist.
+func (s *Store) checkBookRefs(b models.Book) error {
+	if indexByID(s.authors, b.Author, authorID) < 0 ||
+		indexByID(s.genres, b.Genre, genreID) < 0 ||
+		indexByID(s.languages, b.Language, languageID) < 0 {
+		return db.ErrForeignKey
+	}
+
+	return nil
+}
+
+// checkBookYear returns db.ErrParam wrapping the violations when
+// the book was published before its author was born.
+func (s *Store) checkBookYear(b models.Book) error {
+	i := indexByID(s.authors, b.Author, authorID)
+	if i < 0 {
+		return nil
+	}
+
+	if v := models.ValidateBookYear(b.Year, s.authors[i].BirthYear); v != nil {
+		return fmt.Errorf("%w: %w", db.ErrParam, v)
+	}
+
+	return nil
+}
+
+func (s *Store) extendBook(b models.Book) models.BookExt {
+	ext := models.BookExt{
+		ID:    b.ID,
+		Title: b.Title,
+		Year:  b.Year,
+		Pages: b.Pages,
+	}
+
+	if i := indexByID(s.authors, b.Author, authorID); i >= 0 {
+		ext.Author = copyAuthor(s.authors[i])
+	}
+
+	if i := indexByID(s.genres, b.Genre, genreID); i >= 0 {
+		ext.Genre = s.genres[i]
+	}
+
+	if i := indexByID(s.languages, b.Language, languageID); i >= 0 {
+		ext.Language = s.languages[i]
+	}
+
+	return ext
+}
+
+func bookID(b *models.Book) int64 { return b.ID }
@@ -0,0 +1,382 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pawrest/internal/db"
+)
+
+// fields maps query parameter names to accessors returning the value of the
+// matching column: an int64, a string or nil for NULL.
+type fields[T any] map[string]func(*T) any
+
+// query applies the filtering, sorting and pagination described by params to
+// records the same way the SQL backends do and returns the matching copies.
+func query[T any](records []T, params url.Values, allowedParams map[string]string, fs fields[T]) ([]T, error) {
+	f, err := db.ParseFilter(params, allowedParams)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := filter(records, f, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(out, f, fs)
+}
+
+// paginate sorts records in place and returns the page of them
+// described by the sorting and pagination of f.
+func paginate[T any](out []T, f db.Filter, fs fields[T]) ([]T, error) {
+	keys := f.OrderKeys()
+
+	if len(keys) > 0 {
+		slices.SortStableFunc(out, func(a, b T) int {
+			for _, k := range keys {
+				get := fs[k.Param]
+				va, vb := get(&a), get(&b)
+
+				c := 0
+				if va != nil && vb != nil {
+					c = compareValues(va, vb)
+				}
+
+				if c = orderKey(k, va == nil, vb == nil, c); c != 0 {
+					return c
+				}
+			}
+
+			return 0
+		})
+	}
+
+	if f.After != nil {
+		i := 0
+		for i < len(out) {
+			ok, err := afterCursor(&out[i], f, fs)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				break
+			}
+
+			i++
+		}
+
+		out = out[i:]
+	}
+
+	if f.Offset >= int64(len(out)) {
+		return []T{}, nil
+	}
+
+	out = out[f.Offset:]
+
+	if f.Limit >= 0 && f.Limit < int64(len(out)) {
+		out = out[:f.Limit]
+	}
+
+	return out, nil
+}
+
+// count returns the number of records matching the conditions
+// in params, ignoring the sorting and pagination.
+func count[T any](records []T, params url.Values, allowedParams map[string]string, fs fields[T]) (int64, error) {
+	f, err := db.ParseFilter(params, allowedParams)
+	if err != nil {
+		return 0, err
+	}
+
+	out, err := filter(records, f, fs)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(out)), nil
+}
+
+// filter returns copies of the records matching the conditions of f.
+func filter[T any](records []T, f db.Filter, fs fields[T]) ([]T, error) {
+	out := []T{}
+
+	for i := range records {
+		ok, err := matches(&records[i], f, fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			out = append(out, records[i])
+		}
+	}
+
+	return out, nil
+}
+
+// afterCursor reports whether r comes after the cursor of f in the order of f.
+func afterCursor[T any](r *T, f db.Filter, fs fields[T]) (bool, error) {
+	for i, k := range f.OrderKeys() {
+		v := fs[k.Param](r)
+
+		cursor := strconv.FormatInt(f.After.ID, 10)
+		cursorNull := false
+
+		if i < len(f.After.Values) {
+			if p := f.After.Values[i]; p != nil {
+				cursor = *p
+			} else {
+				cursorNull = true
+			}
+		}
+
+		c := 0
+		if v != nil && !cursorNull {
+			var err error
+			if c, err = compareRaw(v, cursor); err != nil {
+				return false, err
+			}
+		}
+
+		if c = orderKey(k, v == nil, cursorNull, c); c != 0 {
+			return c > 0, nil
+		}
+	}
+
+	return false, nil
+}
+
+// orderKey orders two values by the sort key k, given whether they're NULL
+// and the result c of comparing them, which is ignored if any of them is.
+func orderKey(k db.SortKey, aNull, bNull bool, c int) int {
+	switch {
+	case aNull && bNull:
+		return 0
+	case aNull && k.NullsFirst(), bNull && !k.NullsFirst():
+		return -1
+	case aNull, bNull:
+		return 1
+	}
+
+	if k.Desc {
+		return -c
+	}
+
+	return c
+}
+
+func matches[T any](r *T, f db.Filter, fs fields[T]) (bool, error) {
+	for _, c := range f.Conditions {
+		ok, err := matchCondition(r, c, fs)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	for _, group := range f.OrGroups {
+		matched := false
+
+		for _, c := range group {
+			ok, err := matchCondition(r, c, fs)
+			if err != nil {
+				return false, err
+			}
+
+			if ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func matchCondition[T any](r *T, c db.Condition, fs fields[T]) (bool, error) {
+	v := fs[c.Param](r)
+
+	switch c.Operator {
+	case "IS":
+		return v == nil, nil
+	case "IS NOT":
+		return v != nil, nil
+	}
+
+	// Comparing NULL with anything is never true in SQL.
+	if v == nil {
+		return false, nil
+	}
+
+	switch c.Operator {
+	case "IN":
+		for _, raw := range c.Values {
+			res, err := compareRaw(v, raw)
+			if err != nil {
+				return false, err
+			}
+
+			if res == 0 {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case "BETWEEN":
+		lo, err := compareRaw(v, c.Values[0])
+		if err != nil {
+			return false, err
+		}
+
+		hi, err := compareRaw(v, c.Values[1])
+		if err != nil {
+			return false, err
+		}
+
+		return lo >= 0 && hi <= 0, nil
+	}
+
+	if c.Operator == "LIKE" || c.Operator == "ILIKE" {
+		text, ok := v.(string)
+		if !ok {
+			return false, fmt.Errorf("%w: text matching operators can only be used on text fields", db.ErrParam)
+		}
+
+		pattern := c.Value
+		if c.Operator == "ILIKE" {
+			text, pattern = strings.ToLower(text), strings.ToLower(pattern)
+		}
+
+		return like([]rune(text), []rune(pattern)), nil
+	}
+
+	res, err := compareRaw(v, c.Value)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.Operator {
+	case "=":
+		return res == 0, nil
+	case "<>":
+		return res != 0, nil
+	case ">":
+		return res > 0, nil
+	case "<":
+		return res < 0, nil
+	case ">=":
+		return res >= 0, nil
+	case "<=":
+		return res <= 0, nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", c.Operator)
+}
+
+// like reports whether s matches the LIKE pattern, in which % matches
+// any sequence of characters, _ matches a single one and db.LikeEscape
+// makes the next character match literally.
+func like(s, pattern []rune) bool {
+	for len(pattern) > 0 {
+		switch p := pattern[0]; {
+		case p == '%':
+			for len(pattern) > 0 && pattern[0] == '%' {
+				pattern = pattern[1:]
+			}
+
+			for i := 0; i <= len(s); i++ {
+				if like(s[i:], pattern) {
+					return true
+				}
+			}
+
+			return false
+		case p == '_':
+			if len(s) == 0 {
+				return false
+			}
+		case p == db.LikeEscape && len(pattern) > 1:
+			pattern = pattern[1:]
+
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != p {
+				return false
+			}
+		}
+
+		s = s[1:]
+		pattern = pattern[1:]
+	}
+
+	return len(s) == 0
+}
+
+// compareRaw compares a column value with a raw query parameter value.
+// Text is compared ignoring case, like the collations of the text columns
+// of the SQL backends do (see dbtest.testEqualityCase).
+func compareRaw(v any, raw string) (int, error) {
+	switch v := v.(type) {
+	case int64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", db.ErrParam, raw)
+		}
+
+		return cmp.Compare(float64(v), n), nil
+	case float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", db.ErrParam, raw)
+		}
+
+		return cmp.Compare(v, n), nil
+	case string:
+		return strings.Compare(strings.ToLower(v), strings.ToLower(raw)), nil
+	default:
+		return 0, fmt.Errorf("unsupported column type %T", v)
+	}
+}
+
+// compareValues orders two column values, sorting NULL first like MariaDB.
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch a := a.(type) {
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case string:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b.(string)))
+	}
+
+	return 0
+}
+
+func nullable(p *int64) any {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"slices"
+	"strings"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+var genreFields = fields[models.Genre]{
+	"id":   func(g *models.Genre) any { return g.ID },
+	"name": func(g *models.Genre) any { return g.Name },
+}
+
+func (s *Store) GetGenres(ctx context.Context, params url.Values) ([]models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return query(s.genres, params, db.AllowedGenreParams, genreFields)
+}
+
+func (s *Store) StreamGenres(ctx context.Context, params url.Values) iter.Seq2[models.Genre, error] {
+	return db.StreamList(ctx, params, s.GetGenres)
+}
+
+func (s *Store) CountGenres(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return count(s.genres, params, db.AllowedGenreParams, genreFields)
+}
+
+func (s *Store) GetGenre(ctx context.Context, id int64) (models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Genre{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := indexByID(s.genres, id, genreID)
+	if i < 0 {
+		return models.Genre{}, notFound(id)
+	}
+
+	return s.genres[i], nil
+}
+
+func (s *Store) GetGenreByName(ctx context.Context, name string) (models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Genre{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := slices.IndexFunc(s.genres, func(r models.Genre) bool { return strings.EqualFold(r.Name, name) })
+	if i < 0 {
+		return models.Genre{}, notFoundNamed(name)
+	}
+
+	return s.genres[i], nil
+}
+
+func (s *Store) InsertGenre(ctx context.Context, g models.Genre) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq.genres++
+	g.ID = s.seq.genres
+	s.genres = append(s.genres, g)
+
+	return g.ID, nil
+}
+
+func (s *Store) InsertGenres(ctx context.Context, genres []models.Genre) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, len(genres))
+	for i, g := range genres {
+		s.seq.genres++
+		g.ID = s.seq.genres
+		s.genres = append(s.genres, g)
+		ids[i] = g.ID
+	}
+
+	return ids, nil
+}
+
+func (s *Store) UpdateWholeGenre(ctx context.Context, id int64, g models.Genre) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.genres, id, genreID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	g.ID = id
+	s.genres[i] = g
+
+	return nil
+}
+
+func (s *Store) DelGenre(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.genres, id, genreID)
+	if i < 0 {
+		return notFound(id)
+	}
+
+	if slices.ContainsFunc(s.books, func(b models.Book) bool { return b.Genre == id }) {
+		return db.ErrForeignKey
+	}
+
+	s.genres = slices.Delete(s.genres, i, i+1)
+
+	return nil
+}
+
+func genreID(g *models.Genre) int64 { return g.ID }
@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+// bookGroupFields are the fields the extended books can be grouped by,
+// including the ids of the related records.
+var bookGroupFields = func() fields[models.BookExt] {
+	fs := fields[models.BookExt]{
+		"author":   func(b *models.BookExt) any { return b.Author.ID },
+		"genre":    func(b *models.BookExt) any { return b.Genre.ID },
+		"language": func(b *models.BookExt) any { return b.Language.ID },
+	}
+
+	for param, get := range bookExtFields {
+		fs[param] = get
+	}
+
+	return fs
+}()
+
+func (s *Store) Group(ctx context.Context, resource string, params url.Values) ([]models.Group, error) {
+	groups, _, err := s.group(ctx, resource, params)
+	return groups, err
+}
+
+func (s *Store) CountGroups(ctx context.Context, resource string, params url.Values) (int64, error) {
+	_, total, err := s.group(ctx, resource, params)
+	return total, err
+}
+
+// group returns the page of groups of resource requested by params
+// and the number of all of the groups.
+func (s *Store) group(ctx context.Context, resource string, params url.Values) ([]models.Group, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	src, ok := db.GroupSources[resource]
+	if !ok {
+		return nil, 0, fmt.Errorf("resource %q can't be aggregated", resource)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switch resource {
+	case "books":
+		books := make([]models.BookExt, 0, len(s.books))
+		for _, b := range s.books {
+			books = append(books, s.extendBook(b))
+		}
+
+		return group(books, params, src.AllowedParams, bookGroupFields)
+	case "authors":
+		return group(s.authors, params, src.AllowedParams, authorFields)
+	case "genres":
+		return group(s.genres, params, src.AllowedParams, genreFields)
+	default:
+		return group(s.languages, params, src.AllowedParams, languageFields)
+	}
+}
+
+// group aggregates records the way the SQL backends do.
+func group[T any](records []T, params url.Values, allowedParams map[string]string, fs fields[T]) ([]models.Group, int64, error) {
+	g, err := db.ParseGrouping(params, allowedParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := filter(records, g.Filter, fs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		keys    [][]any
+		buckets [][]T
+	)
+
+	// Without grouped fields every record belongs to a single group,
+	// which exists even if there are no records.
+	if len(g.GroupBy) == 0 {
+		keys, buckets = [][]any{{}}, [][]T{rows}
+	} else {
+		for i := range rows {
+			key := make([]any, len(g.GroupBy))
+			for j, field := range g.GroupBy {
+				key[j] = fs[field.Param](&rows[i])
+			}
+
+			j := slices.IndexFunc(keys, func(k []any) bool { return slices.Equal(k, key) })
+			if j < 0 {
+				j = len(keys)
+				keys = append(keys, key)
+				buckets = append(buckets, nil)
+			}
+
+			buckets[j] = append(buckets[j], rows[i])
+		}
+	}
+
+	groups := make([]models.Group, len(keys))
+
+	for i, key := range keys {
+		groups[i] = models.Group{Group: map[string]any{}, Aggregates: map[string]any{}}
+
+		for j, field := range g.GroupBy {
+			groups[i].Group[field.Param] = key[j]
+		}
+
+		for _, a := range g.Aggregates {
+			groups[i].Aggregates[a.Name()] = aggregate(buckets[i], a, fs)
+		}
+	}
+
+	gfs := fields[models.Group]{}
+
+	for _, field := range g.GroupBy {
+		gfs[field.Param] = func(r *models.Group) any { return r.Group[field.Param] }
+	}
+
+	for _, a := range g.Aggregates {
+		gfs[a.Name()] = func(r *models.Group) any { return r.Aggregates[a.Name()] }
+	}
+
+	groups, err = filter(groups, db.Filter{Conditions: g.Having}, gfs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, err := paginate(groups, g.Filter, gfs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return page, int64(len(groups)), nil
+}
+
+// aggregate computes a over rows. Like in SQL, NULL values are
+// skipped and aggregates other than count of no values are NULL.
+func aggregate[T any](rows []T, a db.Aggregate, fs fields[T]) any {
+	if a.Param == "" {
+		return int64(len(rows))
+	}
+
+	var values []any
+
+	for i := range rows {
+		if v := fs[a.Param](&rows[i]); v != nil {
+			values = append(values, v)
+		}
+	}
+
+	if a.Func == "count" {
+		return int64(len(values))
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	switch a.Func {
+	case "min":
+		return slices.MinFunc(values, compareValues)
+	case "max":
+		return slices.MaxFunc(values, compareValues)
+	}
+
+	var sum int64
+	for _, v := range values {
+		sum += v.(int64)
+	}
+
+	if a.Func == "avg" {
+		return float64(sum) / float64(len(values))
+	}
+
+	return sum
+}
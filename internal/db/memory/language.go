@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"slices"
+	"strings"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+var languageFields = fields[models.Language]{
+	"id":   func(l *models.Language) any { return l.ID },
+	"name": func(l *models.Language) any { return l.Name },
+}
+
+func (s *Store) GetLanguages(ctx context.Context, params url.Values) ([]models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return query(s.languages, params, db.AllowedLanguageParams, languageFields)
+}
+
+func (s *Store) StreamLanguages(ctx context.Context, params url.Values) iter.Seq2[models.Language, error] {
+	return db.StreamList(ctx, params, s.GetLanguages)
+}
+
+func (s *Store) CountLanguages(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return count(s.languages, params, db.AllowedLanguageParams, languageFields)
+}
+
+func (s *Store) GetLanguage(ctx context.Context, id int64) (models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Language{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := indexByID(s.languages, id, languageID)
+	if i < 0 {
+		return models.Language{}, notFound(id)
+	}
+
+	return s.languages[i], nil
+}
+
+func (s *Store) GetLanguageByName(ctx context.Context, name string) (models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Language{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := slices.IndexFunc(s.languages, func(r models.Language) bool { return strings.EqualFold(r.Name, name) })
+	if i < 0 {
+		return models.Language{}, notFoundNamed(name)
+	}
+
+	return s.languages[i], nil
+}
+
+func (s *Store) InsertLanguage(ctx context.Context, l models.Language) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq.languages++
+	l.ID = s.seq.languages
+	s.languages = append(s.languages, l)
+
+	return l.ID, nil
+}
+
+func (s *Store) InsertLanguages(ctx context.Context, languages []models.Language) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, len(languages))
+	for i, l := range languages {
+		s.seq.languages++
+		l.ID = s.seq.languages
+		s.languages = append(s.languages, l)
+		ids[i] = l.ID
+	}
+
+	return ids, nil
+}
+
+func (s *Store) UpdateWholeLanguage(ctx context.Context, id int64, l models.Language) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.languages, id, languageID)
+	if i < 0 {
+		return db.ErrNotFound
+	}
+
+	l.ID = id
+	s.languages[i] = l
+
+	return nil
+}
+
+func (s *Store) DelLanguage(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexByID(s.languages, id, languageID)
+	if i < 0 {
+		return notFound(id)
+	}
+
+	if slices.ContainsFunc(s.books, func(b models.Book) bool { return b.Language == id }) {
+		return db.ErrForeignKey
+	}
+
+	s.languages = slices.Delete(s.languages, i, i+1)
+
+	return nil
+}
+
+func languageID(l *models.Language) int64 { return l.ID }
@@ -0,0 +1,145 @@
+// Package memory implements db.DatabaseInterface on top of plain Go slices.
+// It follows the semantics of the SQL backends (filtering, sorting,
+// pagination, auto-incremented ids and foreign keys) and is safe for
+// concurrent use, which makes it suitable for tests and demos.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+type Store struct {
+	mu        sync.RWMutex
+	inTx      bool
+	books     []models.Book
+	authors   []models.Author
+	genres    []models.Genre
+	languages []models.Language
+	seq       sequences
+}
+
+// sequences hold the last ids handed out, mirroring AUTO_INCREMENT
+// counters which are never decremented when records are deleted.
+type sequences struct {
+	books     int64
+	authors   int64
+	genres    int64
+	languages int64
+}
+
+var _ db.DatabaseInterface = (*Store)(nil)
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{}
+}
+
+// NewSeeded returns a store filled with the sample data
+// that the seed data migration loads into the SQL databases.
+func NewSeeded() *Store {
+	s := New()
+
+	for _, l := range seedLanguages {
+		s.seq.languages++
+		s.languages = append(s.languages, models.Language{ID: s.seq.languages, Name: l})
+	}
+
+	for _, g := range seedGenres {
+		s.seq.genres++
+		s.genres = append(s.genres, models.Genre{ID: s.seq.genres, Name: g})
+	}
+
+	for _, a := range seedAuthors {
+		s.seq.authors++
+		a.ID = s.seq.authors
+		s.authors = append(s.authors, a)
+	}
+
+	for _, b := range seedBooks {
+		s.seq.books++
+		b.ID = s.seq.books
+		s.books = append(s.books, b)
+	}
+
+	return s
+}
+
+// WithTx runs fn against a copy of the store and replaces the store contents
+// with the copy once fn succeeds. Other callers are blocked until the
+// transaction finishes, so fn must only use the tx value it is given.
+// Nested calls restore the contents of the transaction when fn fails,
+// like a rollback to a savepoint.
+func (s *Store) WithTx(ctx context.Context, fn func(tx db.DatabaseInterface) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.inTx {
+		savepoint := s.clone()
+
+		if err := fn(s); err != nil {
+			s.replace(savepoint)
+			return err
+		}
+
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := s.clone()
+	tx.inTx = true
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.replace(tx)
+
+	return nil
+}
+
+// replace replaces the contents of the store with the ones of other.
+func (s *Store) replace(other *Store) {
+	s.books = other.books
+	s.authors = other.authors
+	s.genres = other.genres
+	s.languages = other.languages
+	s.seq = other.seq
+}
+
+func (s *Store) clone() *Store {
+	authors := make([]models.Author, len(s.authors))
+	for i, a := range s.authors {
+		authors[i] = copyAuthor(a)
+	}
+
+	return &Store{
+		books:     slices.Clone(s.books),
+		authors:   authors,
+		genres:    slices.Clone(s.genres),
+		languages: slices.Clone(s.languages),
+		seq:       s.seq,
+	}
+}
+
+func notFound(id int64) error {
+	return fmt.Errorf("%w with id %v", db.ErrNotFound, id)
+}
+
+func notFoundNamed(name string) error {
+	return fmt.Errorf("%w named %q", db.ErrNotFound, name)
+}
+
+func indexByID[T any](records []T, id int64, idOf func(*T) int64) int {
+	return slices.IndexFunc(records, func(r T) bool {
+		return idOf(&r) == id
+	})
+}
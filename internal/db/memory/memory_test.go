@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/db"
+	"pawrest/internal/db/dbtest"
+	"pawrest/internal/models"
+)
+
+func TestConformance(t *testing.T) {
+	dbtest.RunConformance(t, func(t *testing.T) db.DatabaseInterface {
+		return NewSeeded()
+	})
+}
+
+func TestGetBooks(t *testing.T) {
+	s := NewSeeded()
+
+	tests := map[string]struct {
+		giveParams url.Values
+		wantTitles []string
+		wantLen    int
+		wantErrIs  error
+	}{
+		"NoParams": {
+			giveParams: url.Values{},
+			wantLen:    17,
+		},
+		"TitleCaseInsensitive": {
+			giveParams: url.Values{"title": {"dziady"}},
+			wantTitles: []string{"Dziady"},
+		},
+		"YearGtSortDesc": {
+			giveParams: url.Values{"year.gt": {"1950"}, "sort_by": {"-pages"}},
+			wantTitles: []string{"Powrót z gwiazd", "Pokój na Ziemi", "Solaris", "Brzechwa dzieciom", "Stary człowiek i morze"},
+		},
+		"PagesLteNeqAuthor": {
+			giveParams: url.Values{"pages.lte": {"100"}, "author.neq": {"8"}, "sort_by": {"pages"}},
+			wantTitles: []string{"Kamizelka", "Stary człowiek i morze"},
+		},
+		"LimitOffset": {
+			giveParams: url.Values{"author": {"8"}, "sort_by": {"pages"}, "limit": {"2"}, "offset": {"1"}},
+			wantTitles: []string{"Quo vadis", "Ogniem i mieczem"},
+		},
+		"OffsetPastEnd": {
+			giveParams: url.Values{"limit": {"5"}, "offset": {"100"}},
+			wantLen:    0,
+		},
+		"ErrUnknownParam": {
+			giveParams: url.Values{"foo": {"bar"}},
+			wantErrIs:  db.ErrParam,
+		},
+		"ErrNotANumber": {
+			giveParams: url.Values{"year.gt": {"abc"}},
+			wantErrIs:  db.ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			books, err := s.GetBooks(context.Background(), tt.giveParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantTitles == nil {
+				assert.Len(t, books, tt.wantLen)
+				return
+			}
+
+			var titles []string
+			for _, b := range books {
+				titles = append(titles, b.Title)
+			}
+
+			assert.Equal(t, tt.wantTitles, titles)
+		})
+	}
+}
+
+func TestLike(t *testing.T) {
+	tests := map[string]struct {
+		give    string
+		pattern string
+		want    bool
+	}{
+		"Exact":            {give: "lalka", pattern: "lalka", want: true},
+		"Prefix":           {give: "lalka", pattern: "la%", want: true},
+		"Suffix":           {give: "lalka", pattern: "%ka", want: true},
+		"Infix":            {give: "lalka", pattern: "%lk%", want: true},
+		"Underscore":       {give: "lalka", pattern: "l_lka", want: true},
+		"UnderscoreLength": {give: "lalka", pattern: "l_ka", want: false},
+		"Percents":         {give: "lalka", pattern: "%%%", want: true},
+		"EmptyPercent":     {give: "", pattern: "%", want: true},
+		"Mismatch":         {give: "lalka", pattern: "potop", want: false},
+		"EscapedPercent":   {give: "50%", pattern: "50!%", want: true},
+		"EscapedLiteral":   {give: "500", pattern: "50!%", want: false},
+		"EscapedEscape":    {give: "a!", pattern: "%!!", want: true},
+		"Unicode":          {give: "żółw", pattern: "_ół_", want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, like([]rune(tt.give), []rune(tt.pattern)))
+		})
+	}
+}
+
+func TestGetBooksExt(t *testing.T) {
+	s := NewSeeded()
+
+	books, err := s.GetBooksExt(context.Background(), url.Values{"author.last_name": {"Orwell"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, "Rok 1984", books[0].Title)
+		assert.Equal(t, "George", books[0].Author.FirstName)
+		assert.Equal(t, "Dystopia", books[0].Genre.Name)
+		assert.Equal(t, "Angielski", books[0].Language.Name)
+	}
+}
+
+func TestAuthorCRUD(t *testing.T) {
+	ctx := context.Background()
+	s := NewSeeded()
+
+	id, err := s.InsertAuthor(ctx, models.Author{FirstName: "Wisława", LastName: "Szymborska", BirthYear: 1923})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), id)
+
+	alive, err := s.GetAuthors(ctx, url.Values{"death_year": {"null"}})
+	assert.NoError(t, err)
+	assert.Len(t, alive, 1)
+
+	err = s.UpdateAuthor(ctx, id, models.Author{DeathYear: models.I64Ptr(2012)})
+	assert.NoError(t, err)
+
+	author, err := s.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Szymborska", author.LastName)
+	assert.Equal(t, int64(2012), *author.DeathYear)
+
+	err = s.UpdateWholeAuthor(ctx, id, models.Author{FirstName: "Maria", LastName: "Szymborska", BirthYear: 1923})
+	assert.NoError(t, err)
+
+	author, err = s.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Maria", author.FirstName)
+	assert.Nil(t, author.DeathYear)
+
+	assert.NoError(t, s.DelAuthor(ctx, id))
+
+	_, err = s.GetAuthor(ctx, id)
+	assert.ErrorIs(t, err, db.ErrNotFound)
+	assert.ErrorIs(t, s.DelAuthor(ctx, id), db.ErrNotFound)
+	assert.ErrorIs(t, s.UpdateWholeAuthor(ctx, id, author), db.ErrNotFound)
+
+	// Ids of deleted records are never reused.
+	id, err = s.InsertAuthor(ctx, models.Author{FirstName: "Olga", LastName: "Tokarczuk", BirthYear: 1962})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), id)
+}
+
+func TestForeignKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewSeeded()
+
+	_, err := s.InsertBook(ctx, models.Book{Title: "FK", Year: 2000, Pages: 10, Author: 1, Genre: 1, Language: 999})
+	assert.ErrorIs(t, err, db.ErrForeignKey)
+
+	err = s.UpdateBook(ctx, 1, models.Book{Genre: 999})
+	assert.ErrorIs(t, err, db.ErrForeignKey)
+
+	err = s.UpdateBook(ctx, 999, models.Book{Genre: 999})
+	assert.ErrorIs(t, err, db.ErrNotFound)
+
+	assert.ErrorIs(t, s.DelAuthor(ctx, 1), db.ErrForeignKey)
+	assert.ErrorIs(t, s.DelGenre(ctx, 2), db.ErrForeignKey)
+	assert.ErrorIs(t, s.DelLanguage(ctx, 2), db.ErrForeignKey)
+	assert.NoError(t, s.DelLanguage(ctx, 11))
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	s := NewSeeded()
+	errRollback := errors.New("rollback")
+
+	err := s.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		if _, err := tx.InsertLanguage(ctx, models.Language{Name: "Czeski"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	err = s.WithTx(ctx, func(tx db.DatabaseInterface) error {
+		_, err := tx.InsertLanguage(ctx, models.Language{Name: "Słowacki"})
+		return err
+	})
+	assert.NoError(t, err)
+
+	langs, err := s.GetLanguages(ctx, url.Values{"name": {"Czeski"}})
+	assert.NoError(t, err)
+	assert.Empty(t, langs)
+
+	langs, err = s.GetLanguages(ctx, url.Values{"name": {"Słowacki"}})
+	assert.NoError(t, err)
+	assert.Len(t, langs, 1)
+}
+
+func TestCanceledContext(t *testing.T) {
+	s := NewSeeded()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.GetBooks(ctx, url.Values{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.InsertGenre(ctx, models.Genre{Name: "Reportaż"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConcurrentInserts(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.InsertGenre(ctx, models.Genre{Name: "Reportaż"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	genres, err := s.GetGenres(ctx, url.Values{"sort_by": {"-id"}})
+	assert.NoError(t, err)
+	if assert.Len(t, genres, 50) {
+		assert.Equal(t, int64(50), genres[0].ID)
+	}
+}
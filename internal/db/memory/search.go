@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"context"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func (s *Store) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	return db.SearchLike(ctx, s, query, limit)
+}
@@ -0,0 +1,64 @@
+package memory
+
+import "pawrest/internal/models"
+
+// Sample data kept in sync with the 0002_seed_data migrations.
+// Ids are assigned in order, starting from 1.
+
+var seedLanguages = []string{
+	"Łaciński",
+	"Polski",
+	"Angielski",
+	"Niemiecki",
+	"Rosyjski",
+	"Francuski",
+	"Włoski",
+	"Hiszpański",
+	"Arabski",
+	"Chiński",
+	"Japoński",
+}
+
+var seedGenres = []string{
+	"Nowela",
+	"Epopeja",
+	"Opowiadanie",
+	"Biografia",
+	"Dramat",
+	"Powieść",
+	"Opowieść",
+	"Zbiór poezji",
+	"Dystopia",
+}
+
+var seedAuthors = []models.Author{
+	{FirstName: "Adam", LastName: "Mickiewicz", BirthYear: 1798, DeathYear: models.I64Ptr(1855)},
+	{FirstName: "Witold", LastName: "Gombrowicz", BirthYear: 1904, DeathYear: models.I64Ptr(1969)},
+	{FirstName: "Bolesław", LastName: "Prus", BirthYear: 1847, DeathYear: models.I64Ptr(1912)},
+	{FirstName: "Fiodor", LastName: "Dostojewski", BirthYear: 1821, DeathYear: models.I64Ptr(1881)},
+	{FirstName: "Stanisław", LastName: "Lem", BirthYear: 1921, DeathYear: models.I64Ptr(2006)},
+	{FirstName: "Jan", LastName: "Brzechwa", BirthYear: 1898, DeathYear: models.I64Ptr(1966)},
+	{FirstName: "Ernest", LastName: "Hemingway", BirthYear: 1899, DeathYear: models.I64Ptr(1961)},
+	{FirstName: "Henryk", LastName: "Sienkiewicz", BirthYear: 1846, DeathYear: models.I64Ptr(1916)},
+	{FirstName: "George", LastName: "Orwell", BirthYear: 1903, DeathYear: models.I64Ptr(1950)},
+}
+
+var seedBooks = []models.Book{
+	{Title: "Pan Tadeusz, czyli ostatni zajazd na Litwie", Year: 1834, Pages: 344, Author: 1, Genre: 2, Language: 2},
+	{Title: "Dziady", Year: 1822, Pages: 304, Author: 1, Genre: 5, Language: 2},
+	{Title: "Ferdydurke", Year: 1937, Pages: 296, Author: 2, Genre: 6, Language: 2},
+	{Title: "Lalka", Year: 1890, Pages: 676, Author: 3, Genre: 6, Language: 2},
+	{Title: "Kamizelka", Year: 1882, Pages: 24, Author: 3, Genre: 1, Language: 2},
+	{Title: "Zbrodnia i kara", Year: 1867, Pages: 496, Author: 4, Genre: 6, Language: 5},
+	{Title: "Solaris", Year: 1961, Pages: 340, Author: 5, Genre: 6, Language: 2},
+	{Title: "Powrót z gwiazd", Year: 1961, Pages: 400, Author: 5, Genre: 6, Language: 2},
+	{Title: "Pokój na Ziemi", Year: 1987, Pages: 376, Author: 5, Genre: 6, Language: 2},
+	{Title: "Akademia pana Kleksa", Year: 1946, Pages: 136, Author: 6, Genre: 7, Language: 2},
+	{Title: "Brzechwa dzieciom", Year: 1953, Pages: 176, Author: 6, Genre: 8, Language: 2},
+	{Title: "Latarnik", Year: 1881, Pages: 32, Author: 8, Genre: 1, Language: 2},
+	{Title: "Ogniem i mieczem", Year: 1884, Pages: 560, Author: 8, Genre: 6, Language: 2},
+	{Title: "Potop", Year: 1886, Pages: 936, Author: 8, Genre: 6, Language: 2},
+	{Title: "Quo vadis", Year: 1896, Pages: 448, Author: 8, Genre: 6, Language: 2},
+	{Title: "Stary człowiek i morze", Year: 1951, Pages: 100, Author: 7, Genre: 3, Language: 3},
+	{Title: "Rok 1984", Year: 1949, Pages: 312, Author: 9, Genre: 9, Language: 3},
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func (s *Store) BooksPerGenre(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	return s.bookCounts(ctx, params, func(b models.BookExt) (int64, string) { return b.Genre.ID, b.Genre.Name })
+}
+
+func (s *Store) BooksPerLanguage(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	return s.bookCounts(ctx, params, func(b models.BookExt) (int64, string) { return b.Language.ID, b.Language.Name })
+}
+
+// bookCounts counts the matching books per the id and the name returned by group.
+func (s *Store) bookCounts(ctx context.Context, params url.Values, group func(models.BookExt) (int64, string)) ([]models.BookCount, error) {
+	books, err := s.statsBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []models.BookCount{}
+
+	for _, b := range books {
+		id, name := group(b)
+
+		i := slices.IndexFunc(counts, func(c models.BookCount) bool { return c.ID == id })
+		if i < 0 {
+			i = len(counts)
+			counts = append(counts, models.BookCount{ID: id, Name: name})
+		}
+
+		counts[i].Books++
+	}
+
+	slices.SortFunc(counts, func(a, b models.BookCount) int {
+		return cmp.Or(cmp.Compare(b.Books, a.Books), cmp.Compare(a.ID, b.ID))
+	})
+
+	return counts, nil
+}
+
+func (s *Store) BooksPerAuthor(ctx context.Context, params url.Values) ([]models.AuthorStats, error) {
+	books, err := s.statsBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []models.AuthorStats{}
+
+	for _, b := range books {
+		i := slices.IndexFunc(authors, func(a models.AuthorStats) bool { return a.ID == b.Author.ID })
+		if i < 0 {
+			i = len(authors)
+			authors = append(authors, models.AuthorStats{
+				ID:        b.Author.ID,
+				FirstName: b.Author.FirstName,
+				LastName:  b.Author.LastName,
+				FirstYear: b.Year,
+				LastYear:  b.Year,
+			})
+		}
+
+		authors[i].Books++
+		authors[i].FirstYear = min(authors[i].FirstYear, b.Year)
+		authors[i].LastYear = max(authors[i].LastYear, b.Year)
+	}
+
+	slices.SortFunc(authors, func(a, b models.AuthorStats) int {
+		return cmp.Or(cmp.Compare(b.Books, a.Books), cmp.Compare(a.ID, b.ID))
+	})
+
+	return authors, nil
+}
+
+func (s *Store) BooksPerDecade(ctx context.Context, params url.Values) ([]models.DecadeCount, error) {
+	books, err := s.statsBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	decades := []models.DecadeCount{}
+
+	for _, b := range books {
+		decade := b.Year - (b.Year%10+10)%10
+
+		i := slices.IndexFunc(decades, func(d models.DecadeCount) bool { return d.Decade == decade })
+		if i < 0 {
+			i = len(decades)
+			decades = append(decades, models.DecadeCount{Decade: decade})
+		}
+
+		decades[i].Books++
+	}
+
+	slices.SortFunc(decades, func(a, b models.DecadeCount) int {
+		return cmp.Compare(a.Decade, b.Decade)
+	})
+
+	return decades, nil
+}
+
+func (s *Store) PageStats(ctx context.Context, params url.Values) (models.PageStats, error) {
+	books, err := s.statsBooks(ctx, params)
+	if err != nil {
+		return models.PageStats{}, err
+	}
+
+	if len(books) == 0 {
+		return models.PageStats{}, nil
+	}
+
+	pages := make([]int64, len(books))
+	for i, b := range books {
+		pages[i] = b.Pages
+	}
+
+	slices.Sort(pages)
+
+	stats := models.PageStats{
+		Books: int64(len(pages)),
+		Min:   pages[0],
+		Max:   pages[len(pages)-1],
+	}
+
+	var sum int64
+	for _, p := range pages {
+		sum += p
+	}
+
+	stats.Average = float64(sum) / float64(len(pages))
+
+	middle := pages[(len(pages)-1)/2 : len(pages)/2+1]
+	for _, p := range middle {
+		stats.Median += float64(p) / float64(len(middle))
+	}
+
+	return stats, nil
+}
+
+// statsBooks returns the extended books matching the filtering conditions in params.
+func (s *Store) statsBooks(ctx context.Context, params url.Values) ([]models.BookExt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := db.ParseStatsFilter(params, db.AllowedBookParams)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	books, err := filter(s.books, f, bookFields)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := make([]models.BookExt, len(books))
+	for i, b := range books {
+		ext[i] = s.extendBook(b)
+	}
+
+	return ext, nil
+}
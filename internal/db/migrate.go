@@ -0,0 +1,371 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+const createMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version     BIGINT NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    applied_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (version)
+)`
+
+// Migration is a numbered schema change with the scripts
+// applying (up) and reverting (down) it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes a migration and when it was applied.
+// AppliedAt is empty for pending migrations.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt string
+}
+
+// baselineVersion is the last migration whose changes were made by the
+// former sql/02-schema.sql script, which created the tables and inserted
+// the sample data before the migrations were versioned.
+const baselineVersion = 2
+
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// loadMigrations reads the migrations stored in dir,
+// named like 0001_create_tables.up.sql, sorted by version.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, e := range entries {
+		m := migrationFileRegexp.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration: %w", err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(data)
+		} else {
+			mig.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down scripts", mig.Version, mig.Name)
+		}
+
+		migrations = append(migrations, *mig)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+func (d *Database) migrations() ([]Migration, error) {
+	return loadMigrations(migrationsFS, path.Join("migrations", d.dialect.name()))
+}
+
+// MigrateUp applies every pending migration in order
+// and returns how many of them were applied.
+func (d *Database) MigrateUp(ctx context.Context) (int, error) {
+	migrations, err := d.migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+
+	err = d.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			if err := d.baseline(ctx, conn, migrations, applied); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+
+			insert := d.dialect.rebind("INSERT INTO schema_migrations (version, name) VALUES (?, ?)")
+
+			if err := runMigration(ctx, conn, m.Up, insert, m.Version, m.Name); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			n++
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first,
+// and returns how many of them were reverted. A steps value lower than 1
+// reverts every applied migration.
+func (d *Database) MigrateDown(ctx context.Context, steps int) (int, error) {
+	migrations, err := d.migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+
+	err = d.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range slices.Backward(migrations) {
+			if steps > 0 && n == steps {
+				break
+			}
+
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+
+			del := d.dialect.rebind("DELETE FROM schema_migrations WHERE version = ?")
+
+			if err := runMigration(ctx, conn, m.Down, del, m.Version); err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			n++
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+// MigrationStatus lists every known migration in order.
+func (d *Database) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := d.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+
+	err = d.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			status = append(status, MigrationStatus{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: applied[m.Version],
+			})
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+// withMigrationLock runs fn on a dedicated connection while holding the
+// dialect's advisory lock, so that replicas starting at the same time
+// don't apply the same migrations concurrently.
+func (d *Database) withMigrationLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := d.pool.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := d.dialect.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer d.dialect.unlock(context.WithoutCancel(ctx), conn)
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// baseline records the migrations up to baselineVersion as applied, adding
+// them to applied, when the tables already exist although no migration has
+// been applied, as in the databases created by the former schema script.
+func (d *Database) baseline(ctx context.Context, conn *sql.Conn, migrations []Migration, applied map[int64]string) error {
+	if _, err := conn.ExecContext(ctx, "SELECT 1 FROM jezyk WHERE 1 = 0"); err != nil {
+		// There are no tables to baseline.
+		return nil
+	}
+
+	insert := d.dialect.rebind("INSERT INTO schema_migrations (version, name) VALUES (?, ?)")
+
+	for _, m := range migrations {
+		if m.Version > baselineVersion {
+			break
+		}
+
+		if _, err := conn.ExecContext(ctx, insert, m.Version, m.Name); err != nil {
+			return fmt.Errorf("failed to baseline migration %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		applied[m.Version] = "baseline"
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the applied migration versions
+// mapped to the time they were applied at.
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int64]string, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int64]string{}
+
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt string
+		)
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// runMigration executes script followed by the bookkeeping query in a
+// transaction. MySQL commits DDL statements implicitly, so a failing
+// migration may leave some of its changes behind on that backend.
+func runMigration(ctx context.Context, conn *sql.Conn, script, query string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, st := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, st); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a script on semicolons which aren't
+// a part of a quoted literal or a comment.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		b          strings.Builder
+		quote      rune
+		comment    bool
+	)
+
+	flush := func() {
+		if st := strings.TrimSpace(b.String()); st != "" {
+			statements = append(statements, st)
+		}
+		b.Reset()
+	}
+
+	runes := []rune(script)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case comment:
+			if r == '\n' {
+				comment = false
+				b.WriteRune(r)
+			}
+			continue
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			comment = true
+			continue
+		case r == ';':
+			flush()
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	flush()
+
+	return statements
+}
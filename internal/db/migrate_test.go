@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := map[string]struct {
+		give string
+		want []string
+	}{
+		"Empty": {
+			give: " \n ",
+			want: nil,
+		},
+		"Multiple": {
+			give: "DELETE FROM ksiazka;\nDELETE FROM autor;\n",
+			want: []string{"DELETE FROM ksiazka", "DELETE FROM autor"},
+		},
+		"NoTrailingSemicolon": {
+			give: "DELETE FROM ksiazka",
+			want: []string{"DELETE FROM ksiazka"},
+		},
+		"QuotedSemicolons": {
+			give: `INSERT INTO gatunek (nazwa) VALUES ('a;b'), ("c;d");`,
+			want: []string{`INSERT INTO gatunek (nazwa) VALUES ('a;b'), ("c;d")`},
+		},
+		"Comments": {
+			give: "-- drop; everything\nDELETE FROM ksiazka; -- trailing;\n",
+			want: []string{"DELETE FROM ksiazka"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.give))
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := map[string]struct {
+		giveFS      fstest.MapFS
+		wantVersion []int64
+		wantErr     bool
+	}{
+		"Sorted": {
+			giveFS: fstest.MapFS{
+				"m/0010_b.up.sql":   {Data: []byte("b")},
+				"m/0010_b.down.sql": {Data: []byte("b")},
+				"m/0002_a.up.sql":   {Data: []byte("a")},
+				"m/0002_a.down.sql": {Data: []byte("a")},
+			},
+			wantVersion: []int64{2, 10},
+		},
+		"MissingDown": {
+			giveFS: fstest.MapFS{
+				"m/0001_a.up.sql": {Data: []byte("a")},
+			},
+			wantErr: true,
+		},
+		"ConflictingNames": {
+			giveFS: fstest.MapFS{
+				"m/0001_a.up.sql":   {Data: []byte("a")},
+				"m/0001_b.down.sql": {Data: []byte("b")},
+			},
+			wantErr: true,
+		},
+		"InvalidName": {
+			giveFS: fstest.MapFS{
+				"m/create_tables.sql": {Data: []byte("a")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.giveFS, "m")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var versions []int64
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+
+			assert.Equal(t, tt.wantVersion, versions)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, d := range []dialect{mysqlDialect{}, sqliteDialect{}, postgresDialect{}} {
+		t.Run(d.name(), func(t *testing.T) {
+			migrations, err := loadMigrations(migrationsFS, "migrations/"+d.name())
+			assert.NoError(t, err)
+			assert.NotEmpty(t, migrations)
+		})
+	}
+}
+
+func TestSQLite_Migrate(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+
+	status, err := d.MigrationStatus(ctx)
+	assert.NoError(t, err)
+
+	for _, s := range status {
+		assert.NotEmpty(t, s.AppliedAt, "migration %d should be applied", s.Version)
+	}
+
+	n, err := d.MigrateDown(ctx, len(status)-1)
+	assert.NoError(t, err)
+	assert.Equal(t, len(status)-1, n)
+
+	books, err := d.GetBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Empty(t, books)
+
+	status, err = d.MigrationStatus(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, status[0].AppliedAt)
+	assert.Empty(t, status[len(status)-1].AppliedAt)
+
+	n, err = d.MigrateDown(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = d.GetBooks(ctx, url.Values{})
+	assert.Error(t, err)
+
+	n, err = d.MigrateUp(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(status), n)
+
+	book, err := d.GetBook(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pan Tadeusz, czyli ostatni zajazd na Litwie", book.Title)
+}
+
+func TestSQLite_MigrateBaseline(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+
+	// A database created by the former schema script has the tables
+	// and the sample data, but no record of the migrations.
+	_, err := d.MigrateDown(ctx, 1)
+	assert.NoError(t, err)
+
+	_, err = d.Pool().Exec("DELETE FROM schema_migrations")
+	assert.NoError(t, err)
+
+	n, err := d.MigrateUp(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n, "only the migrations after the baseline should be applied")
+
+	status, err := d.MigrationStatus(ctx)
+	assert.NoError(t, err)
+
+	for _, s := range status {
+		assert.NotEmpty(t, s.AppliedAt, "migration %d should be applied", s.Version)
+	}
+
+	books, err := d.GetBooks(ctx, url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, books, 17, "the sample data shouldn't be inserted again")
+}
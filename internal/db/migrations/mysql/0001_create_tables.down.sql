@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS ksiazka;
+DROP TABLE IF EXISTS jezyk;
+DROP TABLE IF EXISTS gatunek;
+DROP TABLE IF EXISTS autor;
@@ -0,0 +1,9 @@
+DELETE FROM ksiazka;
+DELETE FROM autor;
+DELETE FROM gatunek;
+DELETE FROM jezyk;
+
+ALTER TABLE ksiazka AUTO_INCREMENT = 1;
+ALTER TABLE autor AUTO_INCREMENT = 1;
+ALTER TABLE gatunek AUTO_INCREMENT = 1;
+ALTER TABLE jezyk AUTO_INCREMENT = 1;
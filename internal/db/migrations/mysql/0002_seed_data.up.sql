@@ -1,48 +1,3 @@
-DROP TABLE IF EXISTS ksiazka;
-DROP TABLE IF EXISTS jezyk;
-DROP TABLE IF EXISTS gatunek;
-DROP TABLE IF EXISTS autor;
-
-CREATE TABLE jezyk (
-    id      INT AUTO_INCREMENT,
-    nazwa   VARCHAR(64) NOT NULL,
-    PRIMARY KEY (id)
-);
-
-CREATE TABLE gatunek (
-    id      INT AUTO_INCREMENT,
-    nazwa   VARCHAR(128) NOT NULL,
-    PRIMARY KEY (id)
-);
-
-CREATE TABLE autor (
-    id              INT AUTO_INCREMENT,
-    imie            VARCHAR(128) NOT NULL,
-    nazwisko        VARCHAR(128) NOT NULL,
-    rok_urodzenia   DECIMAL(5) NOT NULL,
-    rok_smierci     DECIMAL(5),
-    PRIMARY KEY (id)
-);
-
-CREATE TABLE ksiazka (
-    id              INT AUTO_INCREMENT,
-    tytul           VARCHAR(256) NOT NULL,
-    rok_wydania     DECIMAL(5) NOT NULL,
-    liczba_stron    INT,
-    id_autora       INT NOT NULL,
-    id_gatunku      INT NOT NULL,
-    id_jezyka       INT NOT NULL,
-    PRIMARY KEY (id),
-    FOREIGN KEY (id_jezyka) REFERENCES jezyk(id),
-    FOREIGN KEY (id_autora) REFERENCES autor(id),
-    FOREIGN KEY (id_gatunku) REFERENCES gatunek(id)
-);
-
-ALTER TABLE jezyk CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
-ALTER TABLE gatunek CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
-ALTER TABLE autor CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
-ALTER TABLE ksiazka CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
-
 INSERT INTO jezyk (nazwa) VALUES
     ("Łaciński"),
     ("Polski"),
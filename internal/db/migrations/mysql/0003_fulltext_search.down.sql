@@ -0,0 +1,2 @@
+ALTER TABLE ksiazka DROP INDEX ft_ksiazka_tytul;
+ALTER TABLE autor DROP INDEX ft_autor_imie_nazwisko;
@@ -0,0 +1,2 @@
+ALTER TABLE ksiazka ADD FULLTEXT INDEX ft_ksiazka_tytul (tytul);
+ALTER TABLE autor ADD FULLTEXT INDEX ft_autor_imie_nazwisko (imie, nazwisko);
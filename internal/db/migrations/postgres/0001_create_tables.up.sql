@@ -0,0 +1,36 @@
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TABLE jezyk (
+    id      INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    nazwa   CITEXT NOT NULL CHECK (length(nazwa) <= 64),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE gatunek (
+    id      INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    nazwa   CITEXT NOT NULL CHECK (length(nazwa) <= 128),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE autor (
+    id              INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    imie            CITEXT NOT NULL CHECK (length(imie) <= 128),
+    nazwisko        CITEXT NOT NULL CHECK (length(nazwisko) <= 128),
+    rok_urodzenia   INTEGER NOT NULL,
+    rok_smierci     INTEGER,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE ksiazka (
+    id              INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    tytul           CITEXT NOT NULL CHECK (length(tytul) <= 256),
+    rok_wydania     INTEGER NOT NULL,
+    liczba_stron    INTEGER,
+    id_autora       INTEGER NOT NULL,
+    id_gatunku      INTEGER NOT NULL,
+    id_jezyka       INTEGER NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (id_jezyka) REFERENCES jezyk(id),
+    FOREIGN KEY (id_autora) REFERENCES autor(id),
+    FOREIGN KEY (id_gatunku) REFERENCES gatunek(id)
+);
@@ -0,0 +1 @@
+TRUNCATE ksiazka, autor, gatunek, jezyk RESTART IDENTITY;
@@ -0,0 +1,30 @@
+CREATE TABLE jezyk (
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    nazwa   VARCHAR(64) NOT NULL COLLATE NOCASE
+);
+
+CREATE TABLE gatunek (
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    nazwa   VARCHAR(128) NOT NULL COLLATE NOCASE
+);
+
+CREATE TABLE autor (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    imie            VARCHAR(128) NOT NULL COLLATE NOCASE,
+    nazwisko        VARCHAR(128) NOT NULL COLLATE NOCASE,
+    rok_urodzenia   INTEGER NOT NULL,
+    rok_smierci     INTEGER
+);
+
+CREATE TABLE ksiazka (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    tytul           VARCHAR(256) NOT NULL COLLATE NOCASE,
+    rok_wydania     INTEGER NOT NULL,
+    liczba_stron    INTEGER,
+    id_autora       INTEGER NOT NULL,
+    id_gatunku      INTEGER NOT NULL,
+    id_jezyka       INTEGER NOT NULL,
+    FOREIGN KEY (id_jezyka) REFERENCES jezyk(id),
+    FOREIGN KEY (id_autora) REFERENCES autor(id),
+    FOREIGN KEY (id_gatunku) REFERENCES gatunek(id)
+);
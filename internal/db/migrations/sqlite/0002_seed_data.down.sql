@@ -0,0 +1,6 @@
+DELETE FROM ksiazka;
+DELETE FROM autor;
+DELETE FROM gatunek;
+DELETE FROM jezyk;
+
+DELETE FROM sqlite_sequence WHERE name IN ('ksiazka', 'autor', 'gatunek', 'jezyk');
@@ -0,0 +1,2 @@
+-- Keeps the versions aligned with MariaDB, which adds full-text indexes here.
+-- Searching this backend falls back to LIKE matching.
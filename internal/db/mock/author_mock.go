@@ -1,23 +1,22 @@
 package mock
 
 import (
+	"context"
+	"iter"
 	"net/url"
+	"strings"
 
 	"pawrest/internal/db"
 	"pawrest/internal/models"
 )
 
-func (m *MockDatabase) GetAuthors(params url.Values) ([]models.Author, error) {
-	allowedParams := map[string]string{
-		"id":         "id",
-		"first_name": "imie",
-		"last_name":  "nazwisko",
-		"birth_year": "rok_urodzenia",
-		"death_year": "rok_smierci",
+func (m *MockDatabase) GetAuthors(ctx context.Context, params url.Values) ([]models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return []models.Author{}, err
 	}
 
 	if len(params) > 0 {
-		_, _, err := db.AssembleFilter(params, allowedParams)
+		_, _, err := db.AssembleFilter(params, db.AllowedAuthorParams)
 		if err != nil {
 			return []models.Author{}, err
 		}
@@ -26,7 +25,30 @@ func (m *MockDatabase) GetAuthors(params url.Values) ([]models.Author, error) {
 	return m.Authors, nil
 }
 
-func (m *MockDatabase) GetAuthor(id int64) (models.Author, error) {
+func (m *MockDatabase) StreamAuthors(ctx context.Context, params url.Values) iter.Seq2[models.Author, error] {
+	return db.StreamList(ctx, params, m.GetAuthors)
+}
+
+func (m *MockDatabase) CountAuthors(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(params) > 0 {
+		_, _, err := db.AssembleFilter(params, db.AllowedAuthorParams)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(m.Authors)), nil
+}
+
+func (m *MockDatabase) GetAuthor(ctx context.Context, id int64) (models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Author{}, err
+	}
+
 	for _, author := range m.Authors {
 		if author.ID == id {
 			return author, nil
@@ -36,14 +58,49 @@ func (m *MockDatabase) GetAuthor(id int64) (models.Author, error) {
 	return models.Author{}, db.ErrNotFound
 }
 
-func (m *MockDatabase) InsertAuthor(a models.Author) (int64, error) {
+func (m *MockDatabase) GetAuthorByName(ctx context.Context, firstName, lastName string) (models.Author, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Author{}, err
+	}
+
+	for _, author := range m.Authors {
+		if strings.EqualFold(author.FirstName, firstName) && strings.EqualFold(author.LastName, lastName) {
+			return author, nil
+		}
+	}
+
+	return models.Author{}, db.ErrNotFound
+}
+
+func (m *MockDatabase) InsertAuthor(ctx context.Context, a models.Author) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	a.ID = int64(len(m.Authors) + 1)
 	m.Authors = append(m.Authors, a)
 
 	return a.ID, nil
 }
 
-func (m *MockDatabase) UpdateWholeAuthor(id int64, a models.Author) error {
+func (m *MockDatabase) InsertAuthors(ctx context.Context, authors []models.Author) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(authors))
+	for i, a := range authors {
+		ids[i], _ = m.InsertAuthor(ctx, a)
+	}
+
+	return ids, nil
+}
+
+func (m *MockDatabase) UpdateWholeAuthor(ctx context.Context, id int64, a models.Author) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, author := range m.Authors {
 		if author.ID == id {
 			m.Authors[i] = a
@@ -55,7 +112,11 @@ func (m *MockDatabase) UpdateWholeAuthor(id int64, a models.Author) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) UpdateAuthor(id int64, a models.Author) error {
+func (m *MockDatabase) UpdateAuthor(ctx context.Context, id int64, a models.Author) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, author := range m.Authors {
 		if author.ID == id {
 			if a.FirstName != "" {
@@ -78,7 +139,11 @@ func (m *MockDatabase) UpdateAuthor(id int64, a models.Author) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) DelAuthor(id int64) error {
+func (m *MockDatabase) DelAuthor(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, author := range m.Authors {
 		if author.ID == id {
 			m.Authors = append(m.Authors[:i], m.Authors[i+1:]...)
@@ -1,25 +1,21 @@
 package mock
 
 import (
+	"context"
+	"iter"
 	"net/url"
 
 	"pawrest/internal/db"
 	"pawrest/internal/models"
 )
 
-func (m *MockDatabase) GetBooks(params url.Values) ([]models.Book, error) {
-	allowedParams := map[string]string{
-		"id":       "id",
-		"title":    "tytul",
-		"year":     "rok_wydania",
-		"pages":    "liczba_stron",
-		"author":   "id_autora",
-		"genre":    "id_gatunku",
-		"language": "id_jezyka",
+func (m *MockDatabase) GetBooks(ctx context.Context, params url.Values) ([]models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return []models.Book{}, err
 	}
 
 	if len(params) > 0 {
-		_, _, err := db.AssembleFilter(params, allowedParams)
+		_, _, err := db.AssembleFilter(params, db.AllowedBookParams)
 		if err != nil {
 			return []models.Book{}, err
 		}
@@ -28,25 +24,17 @@ func (m *MockDatabase) GetBooks(params url.Values) ([]models.Book, error) {
 	return m.Books, nil
 }
 
-func (m *MockDatabase) GetBooksExt(params url.Values) ([]models.BookExt, error) {
-	allowedParams := map[string]string{
-		"id":                "k.id",
-		"title":             "tytul",
-		"year":              "rok_wydania",
-		"pages":             "liczba_stron",
-		"author.id":         "id_autora",
-		"author.first_name": "a.imie",
-		"author.last_name":  "a.nazwisko",
-		"author.birth_year": "a.rok_urodzenia",
-		"author.death_year": "a.rok_smierci",
-		"genre.id":          "id_gatunku",
-		"genre.name":        "g.nazwa",
-		"language.id":       "id_jezyka",
-		"language.name":     "j.nazwa",
+func (m *MockDatabase) StreamBooks(ctx context.Context, params url.Values) iter.Seq2[models.Book, error] {
+	return db.StreamList(ctx, params, m.GetBooks)
+}
+
+func (m *MockDatabase) GetBooksExt(ctx context.Context, params url.Values) ([]models.BookExt, error) {
+	if err := ctx.Err(); err != nil {
+		return []models.BookExt{}, err
 	}
 
 	if len(params) > 0 {
-		_, _, err := db.AssembleFilter(params, allowedParams)
+		_, _, err := db.AssembleFilter(params, db.AllowedBookExtParams)
 		if err != nil {
 			return []models.BookExt{}, err
 		}
@@ -55,7 +43,45 @@ func (m *MockDatabase) GetBooksExt(params url.Values) ([]models.BookExt, error)
 	return m.BooksExt, nil
 }
 
-func (m *MockDatabase) GetBook(id int64) (models.Book, error) {
+func (m *MockDatabase) StreamBooksExt(ctx context.Context, params url.Values) iter.Seq2[models.BookExt, error] {
+	return db.StreamList(ctx, params, m.GetBooksExt)
+}
+
+func (m *MockDatabase) CountBooks(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(params) > 0 {
+		_, _, err := db.AssembleFilter(params, db.AllowedBookParams)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(m.Books)), nil
+}
+
+func (m *MockDatabase) CountBooksExt(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(params) > 0 {
+		_, _, err := db.AssembleFilter(params, db.AllowedBookExtParams)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(m.BooksExt)), nil
+}
+
+func (m *MockDatabase) GetBook(ctx context.Context, id int64) (models.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Book{}, err
+	}
+
 	for _, book := range m.Books {
 		if book.ID == id {
 			return book, nil
@@ -65,7 +91,11 @@ func (m *MockDatabase) GetBook(id int64) (models.Book, error) {
 	return models.Book{}, db.ErrNotFound
 }
 
-func (m *MockDatabase) InsertBook(b models.Book) (int64, error) {
+func (m *MockDatabase) InsertBook(ctx context.Context, b models.Book) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	if b.Language == 999 {
 		return 0, db.ErrForeignKey
 	}
@@ -76,7 +106,30 @@ func (m *MockDatabase) InsertBook(b models.Book) (int64, error) {
 	return b.ID, nil
 }
 
-func (m *MockDatabase) UpdateWholeBook(id int64, b models.Book) error {
+// InsertBooks inserts the books one by one, restoring
+// the mocked books when any of them fails.
+func (m *MockDatabase) InsertBooks(ctx context.Context, books []models.Book) ([]int64, error) {
+	snapshot := m.clone()
+
+	ids := make([]int64, len(books))
+	for i, b := range books {
+		id, err := m.InsertBook(ctx, b)
+		if err != nil {
+			*m = snapshot
+			return nil, err
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+func (m *MockDatabase) UpdateWholeBook(ctx context.Context, id int64, b models.Book) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, book := range m.Books {
 		if b.Language == 999 {
 			return db.ErrForeignKey
@@ -92,7 +145,11 @@ func (m *MockDatabase) UpdateWholeBook(id int64, b models.Book) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) UpdateBook(id int64, b models.Book) error {
+func (m *MockDatabase) UpdateBook(ctx context.Context, id int64, b models.Book) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, book := range m.Books {
 		if b.Language == 999 {
 			return db.ErrForeignKey
@@ -125,7 +182,11 @@ func (m *MockDatabase) UpdateBook(id int64, b models.Book) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) DelBook(id int64) error {
+func (m *MockDatabase) DelBook(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, book := range m.Books {
 		if book.ID == id {
 			m.Books = append(m.Books[:i], m.Books[i+1:]...)
@@ -1,6 +1,10 @@
 package mock
 
 import (
+	"context"
+	"slices"
+
+	"pawrest/internal/db"
 	"pawrest/internal/models"
 )
 
@@ -61,3 +65,37 @@ func NewMockDatabase() *MockDatabase {
 		},
 	}
 }
+
+// WithTx emulates a transaction by restoring a snapshot
+// of the mocked data when fn fails or panics.
+func (m *MockDatabase) WithTx(ctx context.Context, fn func(tx db.DatabaseInterface) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := m.clone()
+
+	defer func() {
+		if p := recover(); p != nil {
+			*m = snapshot
+			panic(p)
+		}
+	}()
+
+	if err := fn(m); err != nil {
+		*m = snapshot
+		return err
+	}
+
+	return nil
+}
+
+func (m *MockDatabase) clone() MockDatabase {
+	return MockDatabase{
+		Books:     slices.Clone(m.Books),
+		BooksExt:  slices.Clone(m.BooksExt),
+		Authors:   slices.Clone(m.Authors),
+		Genres:    slices.Clone(m.Genres),
+		Languages: slices.Clone(m.Languages),
+	}
+}
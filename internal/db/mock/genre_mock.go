@@ -1,20 +1,22 @@
 package mock
 
 import (
+	"context"
+	"iter"
 	"net/url"
+	"strings"
 
 	"pawrest/internal/db"
 	"pawrest/internal/models"
 )
 
-func (m *MockDatabase) GetGenres(params url.Values) ([]models.Genre, error) {
-	allowedParams := map[string]string{
-		"id":   "id",
-		"name": "nazwa",
+func (m *MockDatabase) GetGenres(ctx context.Context, params url.Values) ([]models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return []models.Genre{}, err
 	}
 
 	if len(params) > 0 {
-		_, _, err := db.AssembleFilter(params, allowedParams)
+		_, _, err := db.AssembleFilter(params, db.AllowedGenreParams)
 		if err != nil {
 			return []models.Genre{}, err
 		}
@@ -23,7 +25,30 @@ func (m *MockDatabase) GetGenres(params url.Values) ([]models.Genre, error) {
 	return m.Genres, nil
 }
 
-func (m *MockDatabase) GetGenre(id int64) (models.Genre, error) {
+func (m *MockDatabase) StreamGenres(ctx context.Context, params url.Values) iter.Seq2[models.Genre, error] {
+	return db.StreamList(ctx, params, m.GetGenres)
+}
+
+func (m *MockDatabase) CountGenres(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(params) > 0 {
+		_, _, err := db.AssembleFilter(params, db.AllowedGenreParams)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(m.Genres)), nil
+}
+
+func (m *MockDatabase) GetGenre(ctx context.Context, id int64) (models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Genre{}, err
+	}
+
 	for _, genre := range m.Genres {
 		if genre.ID == id {
 			return genre, nil
@@ -33,14 +58,49 @@ func (m *MockDatabase) GetGenre(id int64) (models.Genre, error) {
 	return models.Genre{}, db.ErrNotFound
 }
 
-func (m *MockDatabase) InsertGenre(g models.Genre) (int64, error) {
+func (m *MockDatabase) GetGenreByName(ctx context.Context, name string) (models.Genre, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Genre{}, err
+	}
+
+	for _, genre := range m.Genres {
+		if strings.EqualFold(genre.Name, name) {
+			return genre, nil
+		}
+	}
+
+	return models.Genre{}, db.ErrNotFound
+}
+
+func (m *MockDatabase) InsertGenre(ctx context.Context, g models.Genre) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	g.ID = int64(len(m.Genres) + 1)
 	m.Genres = append(m.Genres, g)
 
 	return g.ID, nil
 }
 
-func (m *MockDatabase) UpdateWholeGenre(id int64, g models.Genre) error {
+func (m *MockDatabase) InsertGenres(ctx context.Context, genres []models.Genre) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(genres))
+	for i, g := range genres {
+		ids[i], _ = m.InsertGenre(ctx, g)
+	}
+
+	return ids, nil
+}
+
+func (m *MockDatabase) UpdateWholeGenre(ctx context.Context, id int64, g models.Genre) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, genre := range m.Genres {
 		if genre.ID == id {
 			m.Genres[i] = g
@@ -52,7 +112,11 @@ func (m *MockDatabase) UpdateWholeGenre(id int64, g models.Genre) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) DelGenre(id int64) error {
+func (m *MockDatabase) DelGenre(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, genre := range m.Genres {
 		if genre.ID == id {
 			m.Genres = append(m.Genres[:i], m.Genres[i+1:]...)
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func (m *MockDatabase) Group(ctx context.Context, resource string, params url.Values) ([]models.Group, error) {
+	src, ok := db.GroupSources[resource]
+	if !ok {
+		return nil, fmt.Errorf("resource %q can't be aggregated", resource)
+	}
+
+	if _, err := db.ParseGrouping(params, src.AllowedParams); err != nil {
+		return nil, err
+	}
+
+	return []models.Group{
+		{Group: map[string]any{}, Aggregates: map[string]any{"count": int64(3)}},
+	}, ctx.Err()
+}
+
+func (m *MockDatabase) CountGroups(ctx context.Context, resource string, params url.Values) (int64, error) {
+	src, ok := db.GroupSources[resource]
+	if !ok {
+		return 0, fmt.Errorf("resource %q can't be aggregated", resource)
+	}
+
+	if _, err := db.ParseGrouping(params, src.AllowedParams); err != nil {
+		return 0, err
+	}
+
+	return 1, ctx.Err()
+}
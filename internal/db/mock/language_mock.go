@@ -1,20 +1,22 @@
 package mock
 
 import (
+	"context"
+	"iter"
 	"net/url"
+	"strings"
 
 	"pawrest/internal/db"
 	"pawrest/internal/models"
 )
 
-func (m *MockDatabase) GetLanguages(params url.Values) ([]models.Language, error) {
-	allowedParams := map[string]string{
-		"id":   "id",
-		"name": "nazwa",
+func (m *MockDatabase) GetLanguages(ctx context.Context, params url.Values) ([]models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return []models.Language{}, err
 	}
 
 	if len(params) > 0 {
-		_, _, err := db.AssembleFilter(params, allowedParams)
+		_, _, err := db.AssembleFilter(params, db.AllowedLanguageParams)
 		if err != nil {
 			return []models.Language{}, err
 		}
@@ -23,7 +25,30 @@ func (m *MockDatabase) GetLanguages(params url.Values) ([]models.Language, error
 	return m.Languages, nil
 }
 
-func (m *MockDatabase) GetLanguage(id int64) (models.Language, error) {
+func (m *MockDatabase) StreamLanguages(ctx context.Context, params url.Values) iter.Seq2[models.Language, error] {
+	return db.StreamList(ctx, params, m.GetLanguages)
+}
+
+func (m *MockDatabase) CountLanguages(ctx context.Context, params url.Values) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(params) > 0 {
+		_, _, err := db.AssembleFilter(params, db.AllowedLanguageParams)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(m.Languages)), nil
+}
+
+func (m *MockDatabase) GetLanguage(ctx context.Context, id int64) (models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Language{}, err
+	}
+
 	for _, language := range m.Languages {
 		if language.ID == id {
 			return language, nil
@@ -33,14 +58,49 @@ func (m *MockDatabase) GetLanguage(id int64) (models.Language, error) {
 	return models.Language{}, db.ErrNotFound
 }
 
-func (m *MockDatabase) InsertLanguage(l models.Language) (int64, error) {
+func (m *MockDatabase) GetLanguageByName(ctx context.Context, name string) (models.Language, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Language{}, err
+	}
+
+	for _, language := range m.Languages {
+		if strings.EqualFold(language.Name, name) {
+			return language, nil
+		}
+	}
+
+	return models.Language{}, db.ErrNotFound
+}
+
+func (m *MockDatabase) InsertLanguage(ctx context.Context, l models.Language) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	l.ID = int64(len(m.Languages) + 1)
 	m.Languages = append(m.Languages, l)
 
 	return l.ID, nil
 }
 
-func (m *MockDatabase) UpdateWholeLanguage(id int64, l models.Language) error {
+func (m *MockDatabase) InsertLanguages(ctx context.Context, languages []models.Language) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(languages))
+	for i, l := range languages {
+		ids[i], _ = m.InsertLanguage(ctx, l)
+	}
+
+	return ids, nil
+}
+
+func (m *MockDatabase) UpdateWholeLanguage(ctx context.Context, id int64, l models.Language) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, language := range m.Languages {
 		if language.ID == id {
 			m.Languages[i] = l
@@ -52,7 +112,11 @@ func (m *MockDatabase) UpdateWholeLanguage(id int64, l models.Language) error {
 	return db.ErrNotFound
 }
 
-func (m *MockDatabase) DelLanguage(id int64) error {
+func (m *MockDatabase) DelLanguage(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	for i, language := range m.Languages {
 		if language.ID == id {
 			m.Languages = append(m.Languages[:i], m.Languages[i+1:]...)
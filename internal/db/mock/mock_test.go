@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"testing"
+
+	"pawrest/internal/db"
+	"pawrest/internal/db/dbtest"
+)
+
+// TestContract runs the data independent part of the conformance suite, as
+// the mock holds its own records and doesn't filter, sort or paginate them.
+func TestContract(t *testing.T) {
+	dbtest.RunContract(t, func(t *testing.T) db.DatabaseInterface {
+		return NewMockDatabase()
+	})
+}
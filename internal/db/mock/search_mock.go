@@ -0,0 +1,12 @@
+package mock
+
+import (
+	"context"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func (m *MockDatabase) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	return db.SearchLike(ctx, m, query, limit)
+}
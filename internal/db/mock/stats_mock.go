@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"net/url"
+
+	"pawrest/internal/db"
+	"pawrest/internal/models"
+)
+
+func (m *MockDatabase) BooksPerGenre(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	if _, err := db.ParseStatsFilter(params, db.AllowedBookParams); err != nil {
+		return nil, err
+	}
+
+	return []models.BookCount{{ID: 1, Name: "Nowela", Books: 2}, {ID: 2, Name: "Epopeja", Books: 1}}, ctx.Err()
+}
+
+func (m *MockDatabase) BooksPerLanguage(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	if _, err := db.ParseStatsFilter(params, db.AllowedBookParams); err != nil {
+		return nil, err
+	}
+
+	return []models.BookCount{{ID: 1, Name: "Łaciński", Books: 1}, {ID: 2, Name: "Polski", Books: 1}}, ctx.Err()
+}
+
+func (m *MockDatabase) BooksPerAuthor(ctx context.Context, params url.Values) ([]models.AuthorStats, error) {
+	if _, err := db.ParseStatsFilter(params, db.AllowedBookParams); err != nil {
+		return nil, err
+	}
+
+	return []models.AuthorStats{
+		{ID: 1, FirstName: "Adam", LastName: "Mickiewicz", Books: 1, FirstYear: 1999, LastYear: 1999},
+	}, ctx.Err()
+}
+
+func (m *MockDatabase) BooksPerDecade(ctx context.Context, params url.Values) ([]models.DecadeCount, error) {
+	if _, err := db.ParseStatsFilter(params, db.AllowedBookParams); err != nil {
+		return nil, err
+	}
+
+	return []models.DecadeCount{{Decade: 1860, Books: 1}, {Decade: 1990, Books: 1}, {Decade: 2000, Books: 1}}, ctx.Err()
+}
+
+func (m *MockDatabase) PageStats(ctx context.Context, params url.Values) (models.PageStats, error) {
+	if _, err := db.ParseStatsFilter(params, db.AllowedBookParams); err != nil {
+		return models.PageStats{}, err
+	}
+
+	return models.PageStats{Books: 3, Min: 48, Max: 300, Average: 161, Median: 135}, ctx.Err()
+}
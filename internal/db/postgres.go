@@ -0,0 +1,40 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"pawrest/internal/yamlconfig"
+)
+
+// ConnectToPostgres opens a PostgreSQL database.
+func ConnectToPostgres(cfg *yamlconfig.Config) (*Database, error) {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.DBUser, cfg.DBPass),
+		Host:   cfg.DBHost + ":" + cfg.DBPort,
+		Path:   cfg.DBName,
+	}
+
+	if cfg.DBSSLMode != "" {
+		dsn.RawQuery = url.Values{"sslmode": {cfg.DBSSLMode}}.Encode()
+	}
+
+	db, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetConnMaxLifetime(4 * time.Minute)
+	db.SetMaxOpenConns(150)
+	db.SetMaxIdleConns(150)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &Database{pool: db, conn: db, dialect: postgresDialect{}, timeout: cfg.DBTimeout}, nil
+}
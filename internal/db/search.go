@@ -0,0 +1,340 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"pawrest/internal/models"
+)
+
+type SearchDatabaseInterface interface {
+	// Search returns up to limit hits across books, authors, genres and
+	// languages matching any word of query, the best matches first.
+	// A limit lower than 1 returns every hit.
+	Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+}
+
+// maxSearchTerms limits the number of words of a query which are searched for.
+const maxSearchTerms = 10
+
+// Search uses the full-text indexes of the books and authors when the
+// backend has them. Genres and languages are always matched with LIKE.
+func (d *Database) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	if !d.dialect.fullText() {
+		return SearchLike(ctx, d, query, limit)
+	}
+
+	terms, err := searchTerms(query)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := d.searchBooksFullText(ctx, query, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := d.searchAuthorsFullText(ctx, query, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	genres, err := searchGenresLike(ctx, d, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := searchLanguagesLike(ctx, d, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankHits(slices.Concat(books, authors, genres, languages), limit), nil
+}
+
+// fullTextMatch is a record found by MATCH with its relevance.
+type fullTextMatch struct {
+	ID        int64
+	Relevance float64
+}
+
+// matchFullText returns the ids of up to limit records of table matching
+// query in the full-text indexed columns, mapped to their relevance.
+func (d *Database) matchFullText(ctx context.Context, table, columns, query string, limit int) (map[int64]float64, error) {
+	match := "MATCH(" + columns + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	q := "SELECT id, " + match + " AS relevance FROM " + table + " WHERE " + match + " ORDER BY relevance DESC"
+	args := []any{query, query}
+
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	matchFunc := func(m *fullTextMatch, rows *sql.Rows) error {
+		return rows.Scan(&m.ID, &m.Relevance)
+	}
+
+	matches, err := queryRows(ctx, d, q, args, matchFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	relevance := make(map[int64]float64, len(matches))
+	for _, m := range matches {
+		relevance[m.ID] = m.Relevance
+	}
+
+	return relevance, nil
+}
+
+// The full-text relevance is added to the score of the matched terms,
+// which keeps the scores comparable with the ones of the LIKE matches.
+
+func (d *Database) searchBooksFullText(ctx context.Context, query string, terms []string, limit int) ([]models.SearchHit, error) {
+	relevance, err := d.matchFullText(ctx, "ksiazka", "tytul", query, limit)
+	if err != nil || len(relevance) == 0 {
+		return nil, err
+	}
+
+	books, err := d.GetBooksExt(ctx, url.Values{"id.in": {idList(relevance)}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(books))
+	for i := range books {
+		score := searchScore(books[i].Title, terms) + relevance[books[i].ID]
+		hits[i] = models.SearchHit{Type: models.HitBook, Score: score, Book: &books[i]}
+	}
+
+	return hits, nil
+}
+
+func (d *Database) searchAuthorsFullText(ctx context.Context, query string, terms []string, limit int) ([]models.SearchHit, error) {
+	relevance, err := d.matchFullText(ctx, "autor", "imie, nazwisko", query, limit)
+	if err != nil || len(relevance) == 0 {
+		return nil, err
+	}
+
+	authors, err := d.GetAuthors(ctx, url.Values{"id.in": {idList(relevance)}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(authors))
+	for i := range authors {
+		score := searchScore(authors[i].FirstName+" "+authors[i].LastName, terms) + relevance[authors[i].ID]
+		hits[i] = models.SearchHit{Type: models.HitAuthor, Score: score, Author: &authors[i]}
+	}
+
+	return hits, nil
+}
+
+// SearchLike implements Search using only the filtering of d, for backends
+// without full-text indexes. Records containing any of the words of query
+// are found with the .icontains operator and ranked by searchScore.
+func SearchLike(ctx context.Context, d DatabaseInterface, query string, limit int) ([]models.SearchHit, error) {
+	terms, err := searchTerms(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []models.SearchHit
+
+	searches := []func(context.Context, DatabaseInterface, []string) ([]models.SearchHit, error){
+		searchBooksLike,
+		searchAuthorsLike,
+		searchGenresLike,
+		searchLanguagesLike,
+	}
+
+	for _, search := range searches {
+		h, err := search(ctx, d, terms)
+		if err != nil {
+			return nil, err
+		}
+
+		hits = append(hits, h...)
+	}
+
+	return rankHits(hits, limit), nil
+}
+
+func searchBooksLike(ctx context.Context, d DatabaseInterface, terms []string) ([]models.SearchHit, error) {
+	books, err := d.GetBooksExt(ctx, url.Values{"or": {anyContains(terms, "title")}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(books))
+	for i := range books {
+		hits[i] = models.SearchHit{Type: models.HitBook, Score: searchScore(books[i].Title, terms), Book: &books[i]}
+	}
+
+	return hits, nil
+}
+
+func searchAuthorsLike(ctx context.Context, d DatabaseInterface, terms []string) ([]models.SearchHit, error) {
+	authors, err := d.GetAuthors(ctx, url.Values{"or": {anyContains(terms, "first_name", "last_name")}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(authors))
+	for i := range authors {
+		score := searchScore(authors[i].FirstName+" "+authors[i].LastName, terms)
+		hits[i] = models.SearchHit{Type: models.HitAuthor, Score: score, Author: &authors[i]}
+	}
+
+	return hits, nil
+}
+
+func searchGenresLike(ctx context.Context, d DatabaseInterface, terms []string) ([]models.SearchHit, error) {
+	genres, err := d.GetGenres(ctx, url.Values{"or": {anyContains(terms, "name")}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(genres))
+	for i := range genres {
+		hits[i] = models.SearchHit{Type: models.HitGenre, Score: searchScore(genres[i].Name, terms), Genre: &genres[i]}
+	}
+
+	return hits, nil
+}
+
+func searchLanguagesLike(ctx context.Context, d DatabaseInterface, terms []string) ([]models.SearchHit, error) {
+	languages, err := d.GetLanguages(ctx, url.Values{"or": {anyContains(terms, "name")}})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(languages))
+	for i := range languages {
+		hits[i] = models.SearchHit{Type: models.HitLanguage, Score: searchScore(languages[i].Name, terms), Language: &languages[i]}
+	}
+
+	return hits, nil
+}
+
+// searchTerms splits query into distinct lowercase words.
+func searchTerms(query string) ([]string, error) {
+	var terms []string
+
+	for _, t := range strings.Fields(strings.ToLower(query)) {
+		if !slices.Contains(terms, t) {
+			terms = append(terms, t)
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: the search query is empty", ErrParam)
+	}
+
+	if len(terms) > maxSearchTerms {
+		return nil, fmt.Errorf("%w: the search query can have at most %d words", ErrParam, maxSearchTerms)
+	}
+
+	return terms, nil
+}
+
+// anyContains builds an or group matching the records
+// in which any of the params contains any of the terms.
+func anyContains(terms []string, params ...string) string {
+	var conds []string
+
+	for _, p := range params {
+		for _, t := range terms {
+			conds = append(conds, p+".icontains:"+escapeListValue(t))
+		}
+	}
+
+	return "(" + strings.Join(conds, ",") + ")"
+}
+
+// searchScore rates how well text matches the terms. A term equal to the
+// whole text scores 3, one starting a word of the text 2 and one contained
+// anywhere else in it 1.
+func searchScore(text string, terms []string) float64 {
+	text = strings.ToLower(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	score := 0.0
+
+	for _, t := range terms {
+		switch {
+		case text == t:
+			score += 3
+		case slices.ContainsFunc(words, func(w string) bool { return strings.HasPrefix(w, t) }):
+			score += 2
+		case strings.Contains(text, t):
+			score++
+		}
+	}
+
+	return score
+}
+
+var hitTypeOrder = map[string]int{
+	models.HitBook:     0,
+	models.HitAuthor:   1,
+	models.HitGenre:    2,
+	models.HitLanguage: 3,
+}
+
+// rankHits drops the hits which don't match any term and sorts the rest
+// by score, then by type and id, keeping at most limit of them.
+func rankHits(hits []models.SearchHit, limit int) []models.SearchHit {
+	hits = slices.DeleteFunc(hits, func(h models.SearchHit) bool { return h.Score <= 0 })
+
+	slices.SortFunc(hits, func(a, b models.SearchHit) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(hitTypeOrder[a.Type], hitTypeOrder[b.Type]),
+			cmp.Compare(hitID(a), hitID(b)),
+		)
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	if hits == nil {
+		return []models.SearchHit{}
+	}
+
+	return hits
+}
+
+func hitID(h models.SearchHit) int64 {
+	switch {
+	case h.Book != nil:
+		return h.Book.ID
+	case h.Author != nil:
+		return h.Author.ID
+	case h.Genre != nil:
+		return h.Genre.ID
+	case h.Language != nil:
+		return h.Language.ID
+	}
+
+	return 0
+}
+
+func idList(ids map[int64]float64) string {
+	list := make([]string, 0, len(ids))
+	for id := range ids {
+		list = append(list, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(list, ",")
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := map[string]struct {
+		give      string
+		want      []string
+		wantErrIs error
+	}{
+		"Single": {
+			give: "Lem",
+			want: []string{"lem"},
+		},
+		"Deduplicated": {
+			give: " Pan  pan\ttadeusz ",
+			want: []string{"pan", "tadeusz"},
+		},
+		"ErrEmpty": {
+			give:      " \t ",
+			wantErrIs: ErrParam,
+		},
+		"ErrTooManyWords": {
+			give:      "a b c d e f g h i j k",
+			wantErrIs: ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			terms, err := searchTerms(tt.give)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, terms)
+		})
+	}
+}
+
+func TestSearchScore(t *testing.T) {
+	tests := map[string]struct {
+		giveText  string
+		giveTerms []string
+		want      float64
+	}{
+		"WholeText": {
+			giveText:  "Solaris",
+			giveTerms: []string{"solaris"},
+			want:      3,
+		},
+		"WordPrefix": {
+			giveText:  "Akademia pana Kleksa",
+			giveTerms: []string{"pan"},
+			want:      2,
+		},
+		"Contained": {
+			giveText:  "Hemingway",
+			giveTerms: []string{"ming"},
+			want:      1,
+		},
+		"Summed": {
+			giveText:  "Pan Tadeusz, czyli ostatni zajazd na Litwie",
+			giveTerms: []string{"tadeusz", "litwie", "xyz"},
+			want:      4,
+		},
+		"NoMatch": {
+			giveText:  "Lalka",
+			giveTerms: []string{"pan"},
+			want:      0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchScore(tt.giveText, tt.giveTerms))
+		})
+	}
+}
+
+func TestRankHits(t *testing.T) {
+	hits := []models.SearchHit{
+		{Type: models.HitLanguage, Score: 2, Language: &models.Language{ID: 1}},
+		{Type: models.HitBook, Score: 2, Book: &models.BookExt{ID: 7}},
+		{Type: models.HitGenre, Score: 0, Genre: &models.Genre{ID: 1}},
+		{Type: models.HitBook, Score: 2, Book: &models.BookExt{ID: 3}},
+		{Type: models.HitAuthor, Score: 3, Author: &models.Author{ID: 5}},
+	}
+
+	ranked := rankHits(hits, 3)
+
+	var got []string
+	for _, h := range ranked {
+		got = append(got, h.Type)
+	}
+
+	assert.Equal(t, []string{models.HitAuthor, models.HitBook, models.HitBook}, got)
+	assert.Equal(t, int64(3), ranked[1].Book.ID)
+	assert.Equal(t, int64(7), ranked[2].Book.ID)
+
+	assert.Equal(t, []models.SearchHit{}, rankHits(nil, 0))
+}
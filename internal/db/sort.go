@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Suffixes of the sort_by fields placing NULLs before or after the other values.
+const (
+	nullsFirst = "nullsfirst"
+	nullsLast  = "nullslast"
+)
+
+// SortKey is a single field of the sort_by parameter.
+type SortKey struct {
+	Param  string
+	Column string
+	Desc   bool
+	Nulls  string // nullsfirst, nullslast or empty for the default placement
+}
+
+// NullsFirst reports whether NULLs come before the other values. By default
+// NULL is the lowest value like in MariaDB, so NULLs come first in the
+// ascending order and last in the descending one.
+func (k SortKey) NullsFirst() bool {
+	switch k.Nulls {
+	case nullsFirst:
+		return true
+	case nullsLast:
+		return false
+	}
+
+	return !k.Desc
+}
+
+// String formats the key the way the sort_by parameter does.
+func (k SortKey) String() string {
+	s := k.Param
+	if k.Desc {
+		s = "-" + s
+	}
+
+	if k.Nulls != "" {
+		s += "." + k.Nulls
+	}
+
+	return s
+}
+
+// ParseSort parses the comma-separated fields of the sort_by parameter. Each
+// of them can be prefixed with - for the descending order and suffixed with
+// .nullsfirst or .nullslast. The columns of the keys are left empty.
+func ParseSort(sortBy string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for field := range strings.SplitSeq(sortBy, ",") {
+		var k SortKey
+
+		field = strings.TrimSpace(field)
+		field, k.Desc = strings.CutPrefix(field, "-")
+
+		for _, nulls := range []string{nullsFirst, nullsLast} {
+			if param, ok := strings.CutSuffix(field, "."+nulls); ok {
+				field, k.Nulls = param, nulls
+			}
+		}
+
+		if field == "" {
+			return nil, fmt.Errorf("%w: provided column is empty", ErrParam)
+		}
+
+		if slices.ContainsFunc(keys, func(other SortKey) bool { return other.Param == field }) {
+			return nil, fmt.Errorf("%w: column %q was provided for sorting more than once", ErrParam, field)
+		}
+
+		k.Param = field
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// FormatSort formats keys the way the sort_by parameter does.
+func FormatSort(keys []SortKey) string {
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = k.String()
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// OrderKeys returns the keys the records are ordered by: the sort keys
+// followed by the id, which breaks ties so that pages are well defined.
+// The id follows the direction of the last sort key. Unsorted records
+// are ordered by id only when they're paginated.
+func (f Filter) OrderKeys() []SortKey {
+	if f.IDColumn == "" || slices.ContainsFunc(f.Sort, func(k SortKey) bool { return k.Column == f.IDColumn }) {
+		return f.Sort
+	}
+
+	if len(f.Sort) == 0 && f.Limit < 0 && f.After == nil {
+		return nil
+	}
+
+	desc := len(f.Sort) > 0 && f.Sort[len(f.Sort)-1].Desc
+
+	return append(slices.Clone(f.Sort), SortKey{Param: "id", Column: f.IDColumn, Desc: desc})
+}
+
+// orderBySQL renders the ORDER BY clause of the filter. NULLs are placed
+// with an IS NULL key, as MySQL doesn't support NULLS FIRST and LAST. The
+// key is rendered for the default placement as well, since PostgreSQL puts
+// NULLs last in the ascending order, unlike MariaDB and SQLite. The id is
+// never NULL, so it doesn't get one.
+func (f Filter) orderBySQL() string {
+	keys := f.OrderKeys()
+	if len(keys) == 0 {
+		return ""
+	}
+
+	var exprs []string
+
+	for _, k := range keys {
+		if k.Column != f.IDColumn {
+			nulls := k.Column + " IS NULL"
+			if k.NullsFirst() {
+				nulls += " DESC"
+			}
+
+			exprs = append(exprs, nulls)
+		}
+
+		if k.Desc {
+			exprs = append(exprs, k.Column+" DESC")
+		} else {
+			exprs = append(exprs, k.Column)
+		}
+	}
+
+	return " ORDER BY " + strings.Join(exprs, ", ")
+}
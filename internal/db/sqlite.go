@@ -0,0 +1,32 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pawrest/internal/yamlconfig"
+)
+
+// ConnectToSQLite opens an embedded SQLite database stored in the file named
+// by cfg.DBName (":memory:" keeps it in memory).
+func ConnectToSQLite(cfg *yamlconfig.Config) (*Database, error) {
+	dsn := "file:" + cfg.DBName + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer at a time and an in-memory database
+	// lives only as long as its connection, so keep exactly one around.
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &Database{pool: db, conn: db, dialect: sqliteDialect{}, timeout: cfg.DBTimeout}, nil
+}
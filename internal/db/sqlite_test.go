@@ -0,0 +1,283 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+	"pawrest/internal/yamlconfig"
+)
+
+func newSQLiteDB(t *testing.T) *Database {
+	t.Helper()
+
+	d, err := Connect(&yamlconfig.Config{DBDriver: "sqlite", DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(d.CloseDB)
+
+	if _, err := d.MigrateUp(context.Background()); err != nil {
+		t.Fatalf("Failed to migrate SQLite database: %v", err)
+	}
+
+	return d
+}
+
+func TestConnect_UnsupportedDriver(t *testing.T) {
+	_, err := Connect(&yamlconfig.Config{DBDriver: "oracle"})
+	assert.Error(t, err)
+}
+
+func TestSQLite_MigrationsAppliedOnce(t *testing.T) {
+	cfg := &yamlconfig.Config{DBDriver: "sqlite", DBName: filepath.Join(t.TempDir(), "paw.db")}
+
+	d, err := Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+
+	n, err := d.MigrateUp(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	_, err = d.InsertGenre(context.Background(), models.Genre{Name: "Reportaż"})
+	assert.NoError(t, err)
+	d.CloseDB()
+
+	d, err = Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to reopen SQLite database: %v", err)
+	}
+	defer d.CloseDB()
+
+	n, err = d.MigrateUp(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	genres, err := d.GetGenres(context.Background(), url.Values{"name": {"Reportaż"}})
+	assert.NoError(t, err)
+	assert.Len(t, genres, 1)
+
+	books, err := d.GetBooks(context.Background(), url.Values{})
+	assert.NoError(t, err)
+	assert.Len(t, books, 17)
+}
+
+func TestSQLite_GetBooks(t *testing.T) {
+	d := newSQLiteDB(t)
+
+	tests := map[string]struct {
+		giveParams url.Values
+		wantTitles []string
+		wantLen    int
+		wantErrIs  error
+	}{
+		"NoParams": {
+			giveParams: url.Values{},
+			wantLen:    17,
+		},
+		"TitleCaseInsensitive": {
+			giveParams: url.Values{"title": {"dziady"}},
+			wantTitles: []string{"Dziady"},
+		},
+		"YearGtSortDesc": {
+			giveParams: url.Values{"year.gt": {"1950"}, "sort_by": {"-pages"}},
+			wantTitles: []string{"Powrót z gwiazd", "Pokój na Ziemi", "Solaris", "Brzechwa dzieciom", "Stary człowiek i morze"},
+		},
+		"LimitOffset": {
+			giveParams: url.Values{"author": {"8"}, "sort_by": {"pages"}, "limit": {"2"}, "offset": {"1"}},
+			wantTitles: []string{"Quo vadis", "Ogniem i mieczem"},
+		},
+		"ErrUnknownParam": {
+			giveParams: url.Values{"foo": {"bar"}},
+			wantErrIs:  ErrParam,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			books, err := d.GetBooks(context.Background(), tt.giveParams)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			if tt.wantTitles == nil {
+				assert.Len(t, books, tt.wantLen)
+				return
+			}
+
+			var titles []string
+			for _, b := range books {
+				titles = append(titles, b.Title)
+			}
+
+			assert.Equal(t, tt.wantTitles, titles)
+		})
+	}
+}
+
+func TestSQLite_GetBooksExt(t *testing.T) {
+	d := newSQLiteDB(t)
+
+	books, err := d.GetBooksExt(context.Background(), url.Values{"author.last_name": {"Orwell"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, "Rok 1984", books[0].Title)
+		assert.Equal(t, "George", books[0].Author.FirstName)
+		assert.Equal(t, "Dystopia", books[0].Genre.Name)
+		assert.Equal(t, "Angielski", books[0].Language.Name)
+	}
+}
+
+func TestSQLite_GetBooksExtFields(t *testing.T) {
+	d := newSQLiteDB(t)
+
+	params := url.Values{"fields": {"title,author.last_name"}, "sort_by": {"year"}, "limit": {"1"}}
+
+	books, err := d.GetBooksExt(context.Background(), params)
+	assert.NoError(t, err)
+
+	want := models.BookExt{
+		ID:     2,
+		Title:  "Dziady",
+		Year:   1822,
+		Author: models.Author{LastName: "Mickiewicz"},
+	}
+	assert.Equal(t, []models.BookExt{want}, books, "only the requested, id and sorted columns should be selected")
+}
+
+func TestSQLite_AuthorCRUD(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+
+	id, err := d.InsertAuthor(ctx, models.Author{FirstName: "Wisława", LastName: "Szymborska", BirthYear: 1923})
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	alive, err := d.GetAuthors(ctx, url.Values{"death_year": {"null"}})
+	assert.NoError(t, err)
+	assert.Len(t, alive, 1)
+
+	err = d.UpdateAuthor(ctx, id, models.Author{DeathYear: models.I64Ptr(2012)})
+	assert.NoError(t, err)
+
+	author, err := d.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Szymborska", author.LastName)
+	assert.Equal(t, int64(2012), *author.DeathYear)
+
+	err = d.UpdateWholeAuthor(ctx, id, models.Author{FirstName: "Maria", LastName: "Szymborska", BirthYear: 1923})
+	assert.NoError(t, err)
+
+	author, err = d.GetAuthor(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Maria", author.FirstName)
+	assert.Nil(t, author.DeathYear)
+
+	assert.NoError(t, d.DelAuthor(ctx, id))
+
+	_, err = d.GetAuthor(ctx, id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, d.DelAuthor(ctx, id), ErrNotFound)
+	assert.ErrorIs(t, d.UpdateWholeAuthor(ctx, id, author), ErrNotFound)
+}
+
+func TestSQLite_ForeignKey(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+
+	_, err := d.InsertBook(ctx, models.Book{Title: "FK", Year: 2000, Pages: 10, Author: 1, Genre: 1, Language: 999})
+	assert.ErrorIs(t, err, ErrForeignKey)
+
+	err = d.UpdateBook(ctx, 1, models.Book{Genre: 999})
+	assert.ErrorIs(t, err, ErrForeignKey)
+
+	assert.ErrorIs(t, d.DelAuthor(ctx, 1), ErrForeignKey)
+	assert.ErrorIs(t, d.DelGenre(ctx, 2), ErrForeignKey)
+	assert.ErrorIs(t, d.DelLanguage(ctx, 2), ErrForeignKey)
+}
+
+func TestSQLite_WithTx(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+	errRollback := errors.New("rollback")
+
+	err := d.WithTx(ctx, func(tx DatabaseInterface) error {
+		if _, err := tx.InsertLanguage(ctx, models.Language{Name: "Czeski"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	err = d.WithTx(ctx, func(tx DatabaseInterface) error {
+		_, err := tx.InsertLanguage(ctx, models.Language{Name: "Słowacki"})
+		return err
+	})
+	assert.NoError(t, err)
+
+	langs, err := d.GetLanguages(ctx, url.Values{"name": {"Czeski"}})
+	assert.NoError(t, err)
+	assert.Empty(t, langs)
+
+	langs, err = d.GetLanguages(ctx, url.Values{"name": {"Słowacki"}})
+	assert.NoError(t, err)
+	assert.Len(t, langs, 1)
+}
+
+func TestSQLite_StreamReleasesConnection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d := newSQLiteDB(t)
+
+	n := 0
+
+	for b, err := range d.StreamBooks(ctx, url.Values{"limit": {"3"}}) {
+		if !assert.NoError(t, err) {
+			break
+		}
+
+		// The only connection would still be held by an unbuffered stream.
+		_, err := d.GetAuthor(ctx, b.Author)
+		assert.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 3, n)
+}
+
+func TestSQLite_PageStatsNullPages(t *testing.T) {
+	ctx := context.Background()
+	d := newSQLiteDB(t)
+
+	// Books without a page count can't be added through the API,
+	// but the column is nullable.
+	_, err := d.conn.ExecContext(ctx, "UPDATE ksiazka SET liczba_stron = NULL WHERE id IN (1, 2)")
+	if err != nil {
+		t.Fatalf("Failed to clear page counts: %v", err)
+	}
+
+	all, err := d.PageStats(ctx, url.Values{})
+	assert.NoError(t, err)
+
+	want, err := d.PageStats(ctx, url.Values{"pages.neq": {"null"}})
+	assert.NoError(t, err)
+	assert.Equal(t, want, all, "books without a page count should be skipped")
+	assert.Equal(t, int64(15), all.Books)
+
+	none, err := d.PageStats(ctx, url.Values{"pages": {"null"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PageStats{}, none)
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"pawrest/internal/models"
+)
+
+type StatsDatabaseInterface interface {
+	// BooksPerGenre returns the number of books matching the filtering
+	// conditions in params per genre, the most numerous genres first.
+	// Genres without any matching books are left out.
+	BooksPerGenre(ctx context.Context, params url.Values) ([]models.BookCount, error)
+	// BooksPerLanguage is BooksPerGenre for the languages.
+	BooksPerLanguage(ctx context.Context, params url.Values) ([]models.BookCount, error)
+	// BooksPerAuthor returns the number of matching books of each author and
+	// the years the first and last of them were published in, the most
+	// productive authors first.
+	BooksPerAuthor(ctx context.Context, params url.Values) ([]models.AuthorStats, error)
+	// BooksPerDecade returns the number of matching books published
+	// in each decade, in chronological order.
+	BooksPerDecade(ctx context.Context, params url.Values) ([]models.DecadeCount, error)
+	// PageStats describes the page counts of the matching books.
+	PageStats(ctx context.Context, params url.Values) (models.PageStats, error)
+}
+
+// ParseStatsFilter parses the filtering conditions in params which scope
+// an aggregation. As the aggregations return every group, the sorting,
+// pagination and field selection parameters aren't accepted.
+func ParseStatsFilter(params url.Values, allowedParams map[string]string) (Filter, error) {
+	for _, key := range []string{"sort_by", "limit", "offset", "after", "fields"} {
+		if params.Has(key) {
+			return Filter{}, fmt.Errorf("%w: the %s parameter can't be used with statistics", ErrParam, key)
+		}
+	}
+
+	return ParseFilter(params, allowedParams)
+}
+
+// statsBooks returns the subquery selecting the books matching the
+// filtering conditions in params, aliased as k, with its arguments.
+func (d *Database) statsBooks(params url.Values) (string, []any, error) {
+	f, err := ParseStatsFilter(params, AllowedBookParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	f.dialect = d.dialect
+	where, args := f.Where()
+
+	return "(SELECT * FROM ksiazka" + where + ") k", args, nil
+}
+
+func (d *Database) BooksPerGenre(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	return d.bookCounts(ctx, "gatunek", "id_gatunku", params)
+}
+
+func (d *Database) BooksPerLanguage(ctx context.Context, params url.Values) ([]models.BookCount, error) {
+	return d.bookCounts(ctx, "jezyk", "id_jezyka", params)
+}
+
+// bookCounts counts the matching books per record of table,
+// referenced by the fk column of ksiazka.
+func (d *Database) bookCounts(ctx context.Context, table, fk string, params url.Values) ([]models.BookCount, error) {
+	books, args, err := d.statsBooks(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT t.id, t.nazwa, COUNT(*) AS n
+	FROM ` + books + `
+		JOIN ` + table + ` t ON k.` + fk + ` = t.id
+	GROUP BY t.id, t.nazwa
+	ORDER BY n DESC, t.id`
+
+	countFunc := func(c *models.BookCount, rows *sql.Rows) error {
+		return rows.Scan(&c.ID, &c.Name, &c.Books)
+	}
+
+	return queryRows(ctx, d, query, args, countFunc)
+}
+
+func (d *Database) BooksPerAuthor(ctx context.Context, params url.Values) ([]models.AuthorStats, error) {
+	books, args, err := d.statsBooks(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT a.id, a.imie, a.nazwisko, COUNT(*) AS n, MIN(k.rok_wydania), MAX(k.rok_wydania)
+	FROM ` + books + `
+		JOIN autor a ON k.id_autora = a.id
+	GROUP BY a.id, a.imie, a.nazwisko
+	ORDER BY n DESC, a.id`
+
+	authorFunc := func(a *models.AuthorStats, rows *sql.Rows) error {
+		return rows.Scan(&a.ID, &a.FirstName, &a.LastName, &a.Books, &a.FirstYear, &a.LastYear)
+	}
+
+	return queryRows(ctx, d, query, args, authorFunc)
+}
+
+func (d *Database) BooksPerDecade(ctx context.Context, params url.Values) ([]models.DecadeCount, error) {
+	books, args, err := d.statsBooks(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// % truncates towards zero on every backend, so the
+	// decades of years before our era are fixed up with + 10.
+	query := `
+	SELECT decade, COUNT(*)
+	FROM (SELECT rok_wydania - (rok_wydania % 10 + 10) % 10 AS decade FROM ` + books + `) d
+	GROUP BY decade
+	ORDER BY decade`
+
+	decadeFunc := func(c *models.DecadeCount, rows *sql.Rows) error {
+		return rows.Scan(&c.Decade, &c.Books)
+	}
+
+	return queryRows(ctx, d, query, args, decadeFunc)
+}
+
+func (d *Database) PageStats(ctx context.Context, params url.Values) (models.PageStats, error) {
+	var s models.PageStats
+
+	books, args, err := d.statsBooks(params)
+	if err != nil {
+		return s, err
+	}
+
+	query := `
+	SELECT COUNT(liczba_stron), COALESCE(MIN(liczba_stron), 0), COALESCE(MAX(liczba_stron), 0), COALESCE(AVG(liczba_stron), 0)
+	FROM ` + books
+
+	qctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	row := d.conn.QueryRowContext(qctx, d.dialect.rebind(query), args...)
+	if err := row.Scan(&s.Books, &s.Min, &s.Max, &s.Average); err != nil {
+		return s, fmt.Errorf("Scan error (%w)", err)
+	}
+
+	if s.Books == 0 {
+		return s, nil
+	}
+
+	// The median is the middle page count, or the average of the two middle
+	// ones if there's an even number of books. Like the aggregates above,
+	// it skips the books without a page count.
+	query = "SELECT liczba_stron FROM " + books + " WHERE liczba_stron IS NOT NULL ORDER BY liczba_stron LIMIT ? OFFSET ?"
+	args = append(args, 2-s.Books%2, (s.Books-1)/2)
+
+	pagesFunc := func(p *int64, rows *sql.Rows) error {
+		return rows.Scan(p)
+	}
+
+	middle, err := queryRows(ctx, d, query, args, pagesFunc)
+	if err != nil {
+		return s, err
+	}
+
+	for _, p := range middle {
+		s.Median += float64(p) / float64(len(middle))
+	}
+
+	return s, nil
+}
@@ -1,17 +1,22 @@
 package models
 
 type Author struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	BirthYear int64  `json:"birth_year"`
-	DeathYear *int64 `json:"death_year"`
+	ID        int64  `json:"id" xml:"id"`
+	FirstName string `json:"first_name" xml:"first_name" validate:"required,max=128"`
+	LastName  string `json:"last_name" xml:"last_name" validate:"required,max=128"`
+	BirthYear int64  `json:"birth_year" xml:"birth_year" validate:"min=-99999,max=99999"`
+	DeathYear *int64 `json:"death_year" xml:"death_year" validate:"min=-99999,max=99999,gtefield=birth_year"`
 } // @Name Author
 
-func (a *Author) IsNotValid() bool {
-	return a.FirstName == "" ||
-		a.LastName == "" ||
-		(a.DeathYear != nil && (a.BirthYear > *a.DeathYear))
+// Validate returns the rules the author doesn't satisfy, or nil if it's valid.
+func (a *Author) Validate() Violations {
+	return validate(a, false)
+}
+
+// ValidatePatch returns the rules the fields set by a patch of
+// the author don't satisfy, or nil if they're valid.
+func (a *Author) ValidatePatch() Violations {
+	return validate(a, true)
 }
 
 func I64Ptr(i int64) *int64 {
@@ -1,6 +1,8 @@
 package models_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"pawrest/internal/models"
@@ -8,8 +10,8 @@ import (
 
 func TestAuthorValidation(t *testing.T) {
 	authorTests := map[string]struct {
-		author    models.Author
-		isInvalid bool
+		author  models.Author
+		invalid []string
 	}{
 		"ValidFull": {
 			author: models.Author{
@@ -19,7 +21,7 @@ func TestAuthorValidation(t *testing.T) {
 				BirthYear: 1970,
 				DeathYear: models.I64Ptr(2043),
 			},
-			isInvalid: false,
+			invalid: nil,
 		},
 		"ValidNilDeathYear": {
 			author: models.Author{
@@ -29,7 +31,7 @@ func TestAuthorValidation(t *testing.T) {
 				BirthYear: 1970,
 				DeathYear: nil,
 			},
-			isInvalid: false,
+			invalid: nil,
 		},
 		"InvalidEmptyFirstName": {
 			author: models.Author{
@@ -39,7 +41,7 @@ func TestAuthorValidation(t *testing.T) {
 				BirthYear: 1970,
 				DeathYear: models.I64Ptr(2043),
 			},
-			isInvalid: true,
+			invalid: []string{"first_name"},
 		},
 		"InvalidEmptyLastName": {
 			author: models.Author{
@@ -49,7 +51,7 @@ func TestAuthorValidation(t *testing.T) {
 				BirthYear: 1970,
 				DeathYear: models.I64Ptr(2043),
 			},
-			isInvalid: true,
+			invalid: []string{"last_name"},
 		},
 		"InvalidBirthYearGreaterThanDeathYear": {
 			author: models.Author{
@@ -59,17 +61,46 @@ func TestAuthorValidation(t *testing.T) {
 				BirthYear: 2000,
 				DeathYear: models.I64Ptr(1900),
 			},
-			isInvalid: true,
+			invalid: []string{"death_year"},
+		},
+		"ValidEqualYears": {
+			author: models.Author{
+				FirstName: "Jane",
+				LastName:  "Doe",
+				BirthYear: 1970,
+				DeathYear: models.I64Ptr(1970),
+			},
+			invalid: nil,
+		},
+		"InvalidTooLongLastName": {
+			author: models.Author{
+				FirstName: "Jane",
+				LastName:  strings.Repeat("a", 129),
+				BirthYear: 1970,
+			},
+			invalid: []string{"last_name"},
+		},
+		"InvalidYearsOutOfRange": {
+			author: models.Author{
+				FirstName: "Jane",
+				LastName:  "Doe",
+				BirthYear: -100000,
+				DeathYear: models.I64Ptr(100000),
+			},
+			invalid: []string{"birth_year", "death_year"},
 		},
 	}
 
 	for name, tt := range authorTests {
 		t.Run(name, func(t *testing.T) {
-			actual := tt.author.IsNotValid()
+			var actual []string
+			for _, v := range tt.author.Validate() {
+				actual = append(actual, v.Field)
+			}
 
-			if tt.isInvalid != actual {
+			if !slices.Equal(tt.invalid, actual) {
 				t.Errorf("\n    test: %v\nexpected: %v\n     got: %v\n     for: %+v",
-					name, tt.isInvalid, actual, tt.author)
+					name, tt.invalid, actual, tt.author)
 			}
 		})
 	}
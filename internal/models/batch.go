@@ -0,0 +1,22 @@
+package models
+
+// BatchResult is the outcome of a single item of a batch request,
+// identified by its index in the request body. ID is the id of the
+// created, updated or deleted record. Errors lists the rules the
+// item doesn't satisfy when it failed validation.
+type BatchResult struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	ID     int64       `json:"id,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Errors []Violation `json:"errors,omitempty"`
+} // @Name BatchResult
+
+// Batch is the response to a batch request. Atomic is true when
+// the items were processed all-or-nothing.
+type Batch struct {
+	Atomic    bool          `json:"atomic"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+} // @Name BatchResponse
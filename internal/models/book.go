@@ -1,30 +1,32 @@
 package models
 
 type Book struct {
-	ID       int64  `json:"id"`
-	Title    string `json:"title"`
-	Year     int64  `json:"year"`
-	Pages    int64  `json:"pages"`
-	Author   int64  `json:"author"`
-	Genre    int64  `json:"genre"`
-	Language int64  `json:"language"`
+	ID       int64  `json:"id" xml:"id"`
+	Title    string `json:"title" xml:"title" validate:"required,max=256"`
+	Year     int64  `json:"year" xml:"year" validate:"required,min=-99999,past"`
+	Pages    int64  `json:"pages" xml:"pages" validate:"min=1,max=2147483647"`
+	Author   int64  `json:"author" xml:"author" validate:"min=1"`
+	Genre    int64  `json:"genre" xml:"genre" validate:"min=1"`
+	Language int64  `json:"language" xml:"language" validate:"min=1"`
 } // @Name Book
 
-func (b *Book) IsNotValid() bool {
-	return b.Title == "" ||
-		b.Year == 0 ||
-		b.Pages <= 0 ||
-		b.Author <= 0 ||
-		b.Genre <= 0 ||
-		b.Language <= 0
+// Validate returns the rules the book doesn't satisfy, or nil if it's valid.
+func (b *Book) Validate() Violations {
+	return validate(b, false)
+}
+
+// ValidatePatch returns the rules the fields set by a patch of
+// the book don't satisfy, or nil if they're valid.
+func (b *Book) ValidatePatch() Violations {
+	return validate(b, true)
 }
 
 type BookExt struct {
-	ID       int64    `json:"id"`
-	Title    string   `json:"title"`
-	Year     int64    `json:"year"`
-	Pages    int64    `json:"pages"`
-	Author   Author   `json:"author"`
-	Genre    Genre    `json:"genre"`
-	Language Language `json:"language"`
+	ID       int64    `json:"id" xml:"id"`
+	Title    string   `json:"title" xml:"title"`
+	Year     int64    `json:"year" xml:"year"`
+	Pages    int64    `json:"pages" xml:"pages"`
+	Author   Author   `json:"author" xml:"author"`
+	Genre    Genre    `json:"genre" xml:"genre"`
+	Language Language `json:"language" xml:"language"`
 } // @name BookExtended
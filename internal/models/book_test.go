@@ -1,7 +1,10 @@
 package models_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"pawrest/internal/models"
 )
@@ -24,58 +27,89 @@ func TestBookValidation(t *testing.T) {
 	}
 
 	bookTests := map[string]struct {
-		book      models.Book
-		isInvalid bool
+		book    models.Book
+		invalid []string
 	}{
 		"Valid": {
-			book:      validBook,
-			isInvalid: false,
+			book:    validBook,
+			invalid: nil,
 		},
 		"InvalidEmptyTitle": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Title = ""
 			}),
-			isInvalid: true,
+			invalid: []string{"title"},
 		},
 		"InvalidZeroYear": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Year = 0
 			}),
-			isInvalid: true,
+			invalid: []string{"year"},
 		},
 		"InvalidNegativePages": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Pages = -1
 			}),
-			isInvalid: true,
+			invalid: []string{"pages"},
 		},
 		"InvalidNegativeAuthor": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Author = -1
 			}),
-			isInvalid: true,
+			invalid: []string{"author"},
 		},
 		"InvalidNegativeGenre": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Genre = -1
 			}),
-			isInvalid: true,
+			invalid: []string{"genre"},
 		},
 		"InvalidNegativeLanguage": {
 			book: modifyBook(validBook, func(b *models.Book) {
 				b.Language = -1
 			}),
-			isInvalid: true,
+			invalid: []string{"language"},
+		},
+		"ValidLongTitle": {
+			book: modifyBook(validBook, func(b *models.Book) {
+				b.Title = strings.Repeat("ł", 256)
+			}),
+			invalid: nil,
+		},
+		"InvalidTooLongTitle": {
+			book: modifyBook(validBook, func(b *models.Book) {
+				b.Title = strings.Repeat("a", 257)
+			}),
+			invalid: []string{"title"},
+		},
+		"InvalidFutureYear": {
+			book: modifyBook(validBook, func(b *models.Book) {
+				b.Year = int64(time.Now().Year() + 1)
+			}),
+			invalid: []string{"year"},
+		},
+		"InvalidTooManyPages": {
+			book: modifyBook(validBook, func(b *models.Book) {
+				b.Pages = 1 << 31
+			}),
+			invalid: []string{"pages"},
+		},
+		"InvalidEmpty": {
+			book:    models.Book{},
+			invalid: []string{"title", "year", "pages", "author", "genre", "language"},
 		},
 	}
 
 	for name, tt := range bookTests {
 		t.Run(name, func(t *testing.T) {
-			actual := tt.book.IsNotValid()
+			var actual []string
+			for _, v := range tt.book.Validate() {
+				actual = append(actual, v.Field)
+			}
 
-			if tt.isInvalid != actual {
+			if !slices.Equal(tt.invalid, actual) {
 				t.Errorf("\n    test: %v\nexpected: %v\n     got: %v\n     for: %+v",
-					name, tt.isInvalid, actual, tt.book)
+					name, tt.invalid, actual, tt.book)
 			}
 		})
 	}
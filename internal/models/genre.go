@@ -1,10 +1,17 @@
 package models
 
 type Genre struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID   int64  `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name" validate:"required,max=128"`
 } // @Name Genre
 
-func (g *Genre) IsNotValid() bool {
-	return g.Name == ""
+// Validate returns the rules the genre doesn't satisfy, or nil if it's valid.
+func (g *Genre) Validate() Violations {
+	return validate(g, false)
+}
+
+// ValidatePatch returns the rules the fields set by a patch of
+// the genre don't satisfy, or nil if they're valid.
+func (g *Genre) ValidatePatch() Violations {
+	return validate(g, true)
 }
@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"slices"
 	"testing"
 
 	"pawrest/internal/models"
@@ -8,32 +9,35 @@ import (
 
 func TestGenreValidation(t *testing.T) {
 	genreTests := map[string]struct {
-		genre     models.Genre
-		isInvalid bool
+		genre   models.Genre
+		invalid []string
 	}{
 		"Valid": {
 			genre: models.Genre{
 				ID:   1,
 				Name: "Horror",
 			},
-			isInvalid: false,
+			invalid: nil,
 		},
 		"InvalidEmptyName": {
 			genre: models.Genre{
 				ID:   1,
 				Name: "",
 			},
-			isInvalid: true,
+			invalid: []string{"name"},
 		},
 	}
 
 	for name, tt := range genreTests {
 		t.Run(name, func(t *testing.T) {
-			actual := tt.genre.IsNotValid()
+			var actual []string
+			for _, v := range tt.genre.Validate() {
+				actual = append(actual, v.Field)
+			}
 
-			if tt.isInvalid != actual {
+			if !slices.Equal(tt.invalid, actual) {
 				t.Errorf("\n    test: %v\nexpected: %v\n     got: %v\n     for: %+v",
-					name, tt.isInvalid, actual, tt.genre)
+					name, tt.invalid, actual, tt.genre)
 			}
 		})
 	}
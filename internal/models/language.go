@@ -1,10 +1,17 @@
 package models
 
 type Language struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID   int64  `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name" validate:"required,max=64"`
 } // @Name Language
 
-func (l *Language) IsNotValid() bool {
-	return l.Name == ""
+// Validate returns the rules the language doesn't satisfy, or nil if it's valid.
+func (l *Language) Validate() Violations {
+	return validate(l, false)
+}
+
+// ValidatePatch returns the rules the fields set by a patch of
+// the language don't satisfy, or nil if they're valid.
+func (l *Language) ValidatePatch() Violations {
+	return validate(l, true)
 }
@@ -1,6 +1,8 @@
 package models_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"pawrest/internal/models"
@@ -8,32 +10,42 @@ import (
 
 func TestLanguageValidation(t *testing.T) {
 	languageTests := map[string]struct {
-		language  models.Language
-		isInvalid bool
+		language models.Language
+		invalid  []string
 	}{
 		"Valid": {
 			language: models.Language{
 				ID:   1,
 				Name: "Horror",
 			},
-			isInvalid: false,
+			invalid: nil,
 		},
 		"InvalidEmptyName": {
 			language: models.Language{
 				ID:   1,
 				Name: "",
 			},
-			isInvalid: true,
+			invalid: []string{"name"},
+		},
+		"InvalidTooLongName": {
+			language: models.Language{
+				ID:   1,
+				Name: strings.Repeat("a", 65),
+			},
+			invalid: []string{"name"},
 		},
 	}
 
 	for name, tt := range languageTests {
 		t.Run(name, func(t *testing.T) {
-			actual := tt.language.IsNotValid()
+			var actual []string
+			for _, v := range tt.language.Validate() {
+				actual = append(actual, v.Field)
+			}
 
-			if tt.isInvalid != actual {
+			if !slices.Equal(tt.invalid, actual) {
 				t.Errorf("\n    test: %v\nexpected: %v\n     got: %v\n     for: %+v",
-					name, tt.isInvalid, actual, tt.language)
+					name, tt.invalid, actual, tt.language)
 			}
 		})
 	}
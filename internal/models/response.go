@@ -1,10 +1,52 @@
 package models
 
-type Error struct {
-	Error string `json:"error"`
-} // @Name ErrorResponse
+import "net/http"
+
+// Problem describes an error as RFC 7807 problem details. Type is about:blank,
+// so Title is the text of Status, and Detail explains the occurrence of the
+// error at Instance, the path of the request. Errors lists the rules which
+// the fields of the request body don't satisfy.
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail"`
+	Instance string      `json:"instance,omitempty"`
+	Errors   []Violation `json:"errors,omitempty"`
+} // @Name Problem
+
+// NewProblem returns the problem of an error with status.
+func NewProblem(status int, detail, instance string, violations ...Violation) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Errors:   violations,
+	}
+}
 
 type Token struct {
 	Admin bool   `json:"admin"`
 	Token string `json:"token"`
 } // @Name TokenResponse
+
+// Page wraps a list of resources with the pagination metadata.
+// Limit is null when the request didn't limit the results.
+// NextCursor is only set on full pages of limited requests.
+type Page struct {
+	Data       any    `json:"data"`
+	Total      int64  `json:"total"`
+	Limit      *int64 `json:"limit"`
+	Offset     int64  `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+} // @Name PageResponse
+
+// Group is a single group of an aggregation: the values of the
+// grouped fields and of the aggregates, keyed by their names
+// in the group_by and agg parameters (e.g. avg(pages)).
+type Group struct {
+	Group      map[string]any `json:"group"`
+	Aggregates map[string]any `json:"aggregates"`
+} // @Name GroupResponse
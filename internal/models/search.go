@@ -0,0 +1,20 @@
+package models
+
+// Types of the search hits.
+const (
+	HitBook     = "book"
+	HitAuthor   = "author"
+	HitGenre    = "genre"
+	HitLanguage = "language"
+)
+
+// SearchHit is a single search result. Only the field
+// matching the type of the hit is present.
+type SearchHit struct {
+	Type     string    `json:"type" enums:"book,author,genre,language"`
+	Score    float64   `json:"score"`
+	Book     *BookExt  `json:"book,omitempty"`
+	Author   *Author   `json:"author,omitempty"`
+	Genre    *Genre    `json:"genre,omitempty"`
+	Language *Language `json:"language,omitempty"`
+} // @Name SearchHit
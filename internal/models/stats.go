@@ -0,0 +1,45 @@
+package models
+
+// BookCount is the number of books of a genre or a language.
+type BookCount struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Books int64  `json:"books"`
+} // @Name BookCount
+
+// AuthorStats is the number of books of an author and
+// the range of years in which they were published.
+type AuthorStats struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Books     int64  `json:"books"`
+	FirstYear int64  `json:"first_year"`
+	LastYear  int64  `json:"last_year"`
+} // @Name AuthorStats
+
+// DecadeCount is the number of books published in the decade
+// starting with the year Decade (e.g. 1960 for 1960-1969).
+type DecadeCount struct {
+	Decade int64 `json:"decade"`
+	Books  int64 `json:"books"`
+} // @Name DecadeCount
+
+// PageStats describes the page counts of books. Every
+// field is zero when there are no books.
+type PageStats struct {
+	Books   int64   `json:"books"`
+	Min     int64   `json:"min"`
+	Max     int64   `json:"max"`
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+} // @Name PageStats
+
+// Stats gathers all of the book statistics.
+type Stats struct {
+	Genres    []BookCount   `json:"genres"`
+	Languages []BookCount   `json:"languages"`
+	Authors   []AuthorStats `json:"authors"`
+	Decades   []DecadeCount `json:"decades"`
+	Pages     PageStats     `json:"pages"`
+} // @Name StatsResponse
@@ -0,0 +1,259 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is a rule which a field of a record doesn't satisfy.
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+} // @Name Violation
+
+// Violations lists the rules a record doesn't satisfy. It's an error,
+// so that it can be wrapped by the errors of the operations it failed.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	msgs := make([]string, len(v))
+	for i := range v {
+		msgs[i] = v[i].Message
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (v *Violations) add(field, rule, message string) {
+	*v = append(*v, Violation{Field: field, Rule: rule, Message: message})
+}
+
+// Rules of the validate struct tag, separated by commas. They're checked in
+// order, and only the first rule a field doesn't satisfy is reported.
+//
+//	required     the field can't be zero (or nil)
+//	min=N        numbers can't be less than N, strings shorter than N characters
+//	max=N        numbers can't be greater than N, strings longer than N characters
+//	past         the year can't be later than the current one
+//	gtefield=F   the number can't be less than the field named F in JSON
+//
+// Nil pointers satisfy all rules except required.
+const (
+	ruleRequired = "required"
+	ruleMin      = "min"
+	ruleMax      = "max"
+	rulePast     = "past"
+	ruleGTEField = "gtefield"
+)
+
+// rule is a parsed rule of a validate tag.
+type rule struct {
+	name  string
+	param string
+	// n is the number param of min and max.
+	n int64
+	// field is the index of the field named by the param of gtefield.
+	field int
+}
+
+// fieldRules are the rules of a field, named by its JSON name.
+type fieldRules struct {
+	index int
+	name  string
+	rules []rule
+}
+
+// rulesCache holds the fieldRules of the struct types validated so far.
+var rulesCache sync.Map
+
+// rulesOf parses the validate tags of the fields of t. It panics when
+// a tag is malformed, as that's a mistake in the definition of a model.
+func rulesOf(t reflect.Type) []fieldRules {
+	if cached, ok := rulesCache.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+
+	jsonName := func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		return name
+	}
+
+	var all []fieldRules
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		fr := fieldRules{index: i, name: jsonName(f)}
+
+		for _, r := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(r, "=")
+			parsed := rule{name: name, param: param}
+
+			switch name {
+			case ruleRequired, rulePast:
+			case ruleMin, ruleMax:
+				n, err := strconv.ParseInt(param, 10, 64)
+				if err != nil {
+					panic(fmt.Sprintf("models: invalid %s rule of %s.%s", name, t.Name(), f.Name))
+				}
+
+				parsed.n = n
+			case ruleGTEField:
+				parsed.field = -1
+
+				for j := range t.NumField() {
+					if jsonName(t.Field(j)) == param {
+						parsed.field = j
+					}
+				}
+
+				if parsed.field < 0 {
+					panic(fmt.Sprintf("models: unknown field %q in the gtefield rule of %s.%s", param, t.Name(), f.Name))
+				}
+			default:
+				panic(fmt.Sprintf("models: unknown rule %q of %s.%s", name, t.Name(), f.Name))
+			}
+
+			fr.rules = append(fr.rules, parsed)
+		}
+
+		all = append(all, fr)
+	}
+
+	rulesCache.Store(t, all)
+
+	return all
+}
+
+// validate checks the fields of the struct record points to against the
+// rules of their validate tags. With partial set, zero fields are skipped, as
+// patches leave them unchanged, and so are gtefield rules naming zero fields.
+func validate(record any, partial bool) Violations {
+	rv := reflect.ValueOf(record).Elem()
+
+	var v Violations
+
+	for _, fr := range rulesOf(rv.Type()) {
+		value := rv.Field(fr.index)
+		if partial && value.IsZero() {
+			continue
+		}
+
+		for _, r := range fr.rules {
+			if msg := check(rv, value, fr.name, r, partial); msg != "" {
+				v.add(fr.name, r.name, msg)
+				break
+			}
+		}
+	}
+
+	return v
+}
+
+// check returns the message of the violation of rule r by value,
+// the field name of record, or an empty string if it's satisfied.
+func check(record, value reflect.Value, name string, r rule, partial bool) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if r.name == ruleRequired {
+				return name + " is required"
+			}
+
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	switch r.name {
+	case ruleRequired:
+		if value.IsZero() {
+			return name + " is required"
+		}
+	case ruleMin:
+		if value.Kind() == reflect.String && int64(utf8.RuneCountInString(value.String())) < r.n {
+			return fmt.Sprintf("%s must be at least %d characters long", name, r.n)
+		}
+
+		if value.CanInt() && value.Int() < r.n {
+			return fmt.Sprintf("%s must be at least %d", name, r.n)
+		}
+	case ruleMax:
+		if value.Kind() == reflect.String && int64(utf8.RuneCountInString(value.String())) > r.n {
+			return fmt.Sprintf("%s must be at most %d characters long", name, r.n)
+		}
+
+		if value.CanInt() && value.Int() > r.n {
+			return fmt.Sprintf("%s must be at most %d", name, r.n)
+		}
+	case rulePast:
+		if value.Int() > int64(time.Now().Year()) {
+			return name + " can't be in the future"
+		}
+	case ruleGTEField:
+		other := record.Field(r.field)
+		if other.Kind() == reflect.Pointer {
+			if other.IsNil() {
+				return ""
+			}
+
+			other = other.Elem()
+		}
+
+		if partial && other.IsZero() {
+			return ""
+		}
+
+		if value.Int() < other.Int() {
+			return fmt.Sprintf("%s can't be less than %s", name, r.param)
+		}
+	}
+
+	return ""
+}
+
+// Cross-entity rules, checked by the databases as they need other records.
+const (
+	ruleAfterBirth  = "afterbirth"
+	ruleBeforeBooks = "beforebooks"
+)
+
+// ValidateBookYear checks that a book published in year isn't older
+// than its author, born in birthYear. A zero birthYear is unknown.
+func ValidateBookYear(year, birthYear int64) Violations {
+	if birthYear == 0 || year >= birthYear {
+		return nil
+	}
+
+	return Violations{{
+		Field:   "year",
+		Rule:    ruleAfterBirth,
+		Message: fmt.Sprintf("year can't be earlier than the birth year of the author (%d)", birthYear),
+	}}
+}
+
+// ValidateBirthYear checks that an author born in birthYear isn't younger
+// than the oldest of their books, published in firstBook. A zero birthYear
+// is unknown.
+func ValidateBirthYear(birthYear, firstBook int64) Violations {
+	if birthYear == 0 || birthYear <= firstBook {
+		return nil
+	}
+
+	return Violations{{
+		Field:   "birth_year",
+		Rule:    ruleBeforeBooks,
+		Message: fmt.Sprintf("birth_year can't be later than the year of the first book of the author (%d)", firstBook),
+	}}
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pawrest/internal/models"
+)
+
+func TestValidatePatch(t *testing.T) {
+	patchTests := map[string]struct {
+		patch      models.Author
+		violations models.Violations
+	}{
+		"Valid": {
+			patch: models.Author{LastName: "Doe"},
+		},
+		"ValidDeathYearOnly": {
+			patch: models.Author{DeathYear: models.I64Ptr(1900)},
+		},
+		"InvalidTooLongFirstName": {
+			patch: models.Author{FirstName: string(make([]byte, 129))},
+			violations: models.Violations{
+				{Field: "first_name", Rule: "max", Message: "first_name must be at most 128 characters long"},
+			},
+		},
+		"InvalidDeathBeforeBirth": {
+			patch: models.Author{BirthYear: 1950, DeathYear: models.I64Ptr(1900)},
+			violations: models.Violations{
+				{Field: "death_year", Rule: "gtefield", Message: "death_year can't be less than birth_year"},
+			},
+		},
+	}
+
+	for name, tt := range patchTests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.violations, tt.patch.ValidatePatch())
+		})
+	}
+}
+
+func TestViolations_Error(t *testing.T) {
+	v := (&models.Book{Title: "Foo", Year: 1999}).Validate()
+
+	assert.Equal(t, "pages must be at least 1; author must be at least 1; genre must be at least 1; language must be at least 1", v.Error())
+}
+
+func TestValidateBookYear(t *testing.T) {
+	assert.Nil(t, models.ValidateBookYear(1921, 1921))
+	assert.Nil(t, models.ValidateBookYear(-500, 0), "An unknown birth year can't be violated")
+
+	v := models.ValidateBookYear(1900, 1921)
+	if assert.Len(t, v, 1) {
+		assert.Equal(t, "year", v[0].Field)
+		assert.Equal(t, "afterbirth", v[0].Rule)
+	}
+}
+
+func TestValidateBirthYear(t *testing.T) {
+	assert.Nil(t, models.ValidateBirthYear(1921, 1946))
+	assert.Nil(t, models.ValidateBirthYear(0, 1946))
+
+	v := models.ValidateBirthYear(1950, 1946)
+	if assert.Len(t, v, 1) {
+		assert.Equal(t, "birth_year", v[0].Field)
+		assert.Equal(t, "beforebooks", v[0].Rule)
+	}
+}
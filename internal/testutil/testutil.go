@@ -1,36 +1,23 @@
 package testutil
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	"os"
-	"strings"
-)
 
-func SetupDatabase(db *sql.DB) error {
-	if err := setupFromScript(db); err != nil {
-		return fmt.Errorf("failed to set up the database from script: %w", err)
-	}
+	"pawrest/internal/db"
+)
 
-	return nil
-}
+// SetupDatabase reverts every migration and applies them again,
+// leaving the database with the schema and the sample data only.
+func SetupDatabase(d *db.Database) error {
+	ctx := context.Background()
 
-func setupFromScript(db *sql.DB) error {
-	data, err := os.ReadFile("../../../sql/02-schema.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+	if _, err := d.MigrateDown(ctx, 0); err != nil {
+		return fmt.Errorf("failed to revert migrations: %w", err)
 	}
 
-	splitData := strings.Split(string(data), ";")
-	for _, statement := range splitData {
-		st := strings.TrimSpace(statement)
-		if st == "" {
-			continue
-		}
-
-		if _, err := db.Exec(st); err != nil {
-			return fmt.Errorf("failed to exec: %w", err)
-		}
+	if _, err := d.MigrateUp(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
 	return nil
@@ -1,35 +1,57 @@
 package yamlconfig
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	DBUser string
-	DBPass string
-	DBName string
-	DBHost string
-	DBPort string
-	Secret string
+	DBDriver  string
+	DBUser    string
+	DBPass    string
+	DBName    string
+	DBHost    string
+	DBPort    string
+	DBSSLMode string
+	DBTimeout time.Duration
+	Secret    string
 }
 
 func Parse(fPath string) (*Config, error) {
+	return ParseDriver(fPath, "")
+}
+
+// ParseDriver is Parse using the database backend named by driver,
+// instead of the DBDRIVER variable, when it's not empty.
+func ParseDriver(fPath, driver string) (*Config, error) {
 	if err := Load(fPath); err != nil {
 		return nil, fmt.Errorf("failed to load: %w", err)
 	}
 
 	var missing []string
 
+	dbDriver := cmp.Or(driver, os.Getenv("DBDRIVER"))
+	if dbDriver == "" {
+		dbDriver = "mysql"
+	}
+
+	switch dbDriver {
+	case "mysql", "sqlite", "postgres", "memory":
+	default:
+		return nil, fmt.Errorf("unsupported DBDRIVER value %q", dbDriver)
+	}
+
 	dbUser := os.Getenv("DBUSER")
 	dbPass := os.Getenv("DBPASS")
 	dbName := os.Getenv("DBNAME")
-	if dbUser == "" {
+	if dbUser == "" && dbDriver != "sqlite" && dbDriver != "memory" {
 		missing = append(missing, "DBUSER")
 	}
 
-	if dbName == "" {
+	if dbName == "" && dbDriver != "memory" {
 		missing = append(missing, "DBNAME")
 	}
 
@@ -41,6 +63,20 @@ func Parse(fPath string) (*Config, error) {
 
 	if dbPort == "" {
 		dbPort = "3306"
+
+		if dbDriver == "postgres" {
+			dbPort = "5432"
+		}
+	}
+
+	dbTimeout := 10 * time.Second
+	if t := os.Getenv("DBTIMEOUT"); t != "" {
+		var err error
+
+		dbTimeout, err = time.ParseDuration(t)
+		if err != nil || dbTimeout < 0 {
+			return nil, fmt.Errorf("invalid DBTIMEOUT value %q", t)
+		}
 	}
 
 	secret := os.Getenv("SECRET")
@@ -53,11 +89,14 @@ func Parse(fPath string) (*Config, error) {
 	}
 
 	return &Config{
-		DBUser: dbUser,
-		DBPass: dbPass,
-		DBName: dbName,
-		DBHost: dbHost,
-		DBPort: dbPort,
-		Secret: secret,
+		DBDriver:  dbDriver,
+		DBUser:    dbUser,
+		DBPass:    dbPass,
+		DBName:    dbName,
+		DBHost:    dbHost,
+		DBPort:    dbPort,
+		DBSSLMode: os.Getenv("DBSSLMODE"),
+		DBTimeout: dbTimeout,
+		Secret:    secret,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"pawrest/internal/yamlconfig"
 )
@@ -39,6 +40,7 @@ SECRET: "secret-jwt-key"
 		{"testdb", cfg.DBName},
 		{"132.154.32.8", cfg.DBHost},
 		{"3306", cfg.DBPort},
+		{"mysql", cfg.DBDriver},
 		{"secret-jwt-key", cfg.Secret},
 	}
 
@@ -47,6 +49,68 @@ SECRET: "secret-jwt-key"
 			t.Errorf("got %v, want %v", v.got, v.want)
 		}
 	}
+
+	if cfg.DBTimeout != 10*time.Second {
+		t.Errorf("got %v, want %v", cfg.DBTimeout, 10*time.Second)
+	}
+}
+
+func TestParse_DBTimeout(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		"Seconds": {
+			value: "30s",
+			want:  30 * time.Second,
+		},
+		"Milliseconds": {
+			value: "1500ms",
+			want:  1500 * time.Millisecond,
+		},
+		"Disabled": {
+			value: "0",
+			want:  0,
+		},
+		"ErrNotDuration": {
+			value:   "ten seconds",
+			wantErr: true,
+		},
+		"ErrNegative": {
+			value:   "-5s",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Clearenv()
+			fileName := "testenv.yaml"
+			data := []byte("DBUSER: \"user\"\nDBNAME: \"testdb\"\nSECRET: \"secret\"\nDBTIMEOUT: \"" + tt.value + "\"")
+
+			if err := os.WriteFile(fileName, data, 0644); err != nil {
+				t.Fatalf("Error writing to file: %v", err)
+			}
+			defer os.Remove(fileName)
+
+			cfg, err := yamlconfig.Parse(fileName)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Should return an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Should not return an error: %v", err)
+			}
+
+			if cfg.DBTimeout != tt.want {
+				t.Errorf("got %v, want %v", cfg.DBTimeout, tt.want)
+			}
+		})
+	}
 }
 
 func TestParse_Error_MissingFile(t *testing.T) {
@@ -114,3 +178,96 @@ func TestParse_Error_MissingVar(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_DBDriver(t *testing.T) {
+	tests := map[string]struct {
+		fileData []byte
+		want     string
+		wantPort string
+		wantErr  string
+	}{
+		"SQLiteWithoutUser": {
+			fileData: []byte("DBDRIVER: \"sqlite\"\nDBNAME: \"paw.db\"\nSECRET: \"testsecret\""),
+			want:     "sqlite",
+			wantPort: "3306",
+		},
+		"PostgresDefaultPort": {
+			fileData: []byte("DBDRIVER: \"postgres\"\nDBUSER: \"user\"\nDBNAME: \"paw\"\nSECRET: \"testsecret\""),
+			want:     "postgres",
+			wantPort: "5432",
+		},
+		"PostgresMissingUser": {
+			fileData: []byte("DBDRIVER: \"postgres\"\nDBNAME: \"paw\"\nSECRET: \"testsecret\""),
+			wantErr:  "Missing required environment variable/s: DBUSER",
+		},
+		"MemoryWithoutUserAndName": {
+			fileData: []byte("DBDRIVER: \"memory\"\nSECRET: \"testsecret\""),
+			want:     "memory",
+			wantPort: "3306",
+		},
+		"SQLiteMissingName": {
+			fileData: []byte("DBDRIVER: \"sqlite\"\nSECRET: \"testsecret\""),
+			wantErr:  "Missing required environment variable/s: DBNAME",
+		},
+		"Unsupported": {
+			fileData: []byte("DBDRIVER: \"oracle\"\nDBNAME: \"paw\"\nSECRET: \"testsecret\""),
+			wantErr:  "unsupported DBDRIVER value \"oracle\"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Clearenv()
+			fileName := "testenv.yaml"
+
+			if err := os.WriteFile(fileName, tt.fileData, 0644); err != nil {
+				t.Fatalf("Error writing to file: %v", err)
+			}
+			defer os.Remove(fileName)
+
+			cfg, err := yamlconfig.Parse(fileName)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Should not return an error: %v", err)
+			}
+
+			if cfg.DBDriver != tt.want {
+				t.Errorf("got %v, want %v", cfg.DBDriver, tt.want)
+			}
+
+			if cfg.DBPort != tt.wantPort {
+				t.Errorf("got %v, want %v", cfg.DBPort, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestParseDriver_OverridesDBDriver(t *testing.T) {
+	os.Clearenv()
+	fileName := "testenv.yaml"
+
+	data := []byte("DBDRIVER: \"mysql\"\nSECRET: \"testsecret\"")
+	if err := os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatalf("Error writing to file: %v", err)
+	}
+	defer os.Remove(fileName)
+
+	cfg, err := yamlconfig.ParseDriver(fileName, "memory")
+	if err != nil {
+		t.Fatalf("Should not return an error: %v", err)
+	}
+
+	if cfg.DBDriver != "memory" {
+		t.Errorf("got %v, want memory", cfg.DBDriver)
+	}
+
+	if got := os.Getenv("DBDRIVER"); got != "mysql" {
+		t.Errorf("DBDRIVER changed to %v", got)
+	}
+}